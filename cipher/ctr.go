@@ -0,0 +1,133 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Seekable Counter (CTR) mode.
+
+// CTR converts a block cipher into a stream cipher by
+// repeatedly encrypting an incrementing counter and
+// xoring the resulting stream of data with the input.
+// Because every keystream block only depends on its own
+// counter value, the stream can be positioned at any byte
+// offset, e.g. to serve HTTP Range requests.
+
+package cipher
+
+import (
+	_cipher "crypto/cipher"
+	"crypto/subtle"
+	"math/bits"
+
+	"github.com/emmansun/gmsm/internal/alias"
+	"github.com/emmansun/gmsm/internal/byteorder"
+)
+
+// ctrAble is an interface implemented by ciphers that have a specific
+// optimized implementation of CTR, like sm4.
+// NewSeekableCTR will check for this interface and use the returned Stream
+// if it also supports random access through XORKeyStreamAt.
+type ctrAble interface {
+	NewCTR(iv []byte) _cipher.Stream
+}
+
+// keyStreamAtAble is implemented by optimized CTR streams which can seek into
+// the keystream without keeping any state.
+type keyStreamAtAble interface {
+	XORKeyStreamAt(dst, src []byte, offset uint64)
+}
+
+type seekableCTR struct {
+	at     keyStreamAtAble
+	offset uint64 // current position, in bytes, from the start of the keystream
+}
+
+// NewSeekableCTR returns a SeekableStream which encrypts/decrypts using the
+// given Block in counter mode. The block size must be 16 bytes and the length
+// of iv must be the same as the Block's block size.
+//
+// The iv is treated as a 128-bit big-endian counter which wraps around on
+// overflow, so the keystream is identical to the one produced by
+// [crypto/cipher.NewCTR]. XORKeyStreamAt positions the stream at the given
+// byte offset from the start, subsequent XORKeyStream calls continue from
+// the end of that call.
+func NewSeekableCTR(block _cipher.Block, iv []byte) SeekableStream {
+	if block.BlockSize() != blockSize {
+		panic("cipher: NewSeekableCTR requires 128-bit block cipher")
+	}
+	if len(iv) != blockSize {
+		panic("cipher.NewSeekableCTR: IV length must equal block size")
+	}
+	if ctr, ok := block.(ctrAble); ok {
+		if at, ok := ctr.NewCTR(iv).(keyStreamAtAble); ok {
+			return &seekableCTR{at: at}
+		}
+	}
+	return &seekableCTR{at: newCTRGeneric(block, iv)}
+}
+
+func (x *seekableCTR) XORKeyStream(dst, src []byte) {
+	x.XORKeyStreamAt(dst, src, x.offset)
+}
+
+func (x *seekableCTR) XORKeyStreamAt(dst, src []byte, offset uint64) {
+	x.at.XORKeyStreamAt(dst, src, offset)
+	x.offset = offset + uint64(len(src))
+}
+
+type ctrGeneric struct {
+	b          _cipher.Block
+	ivlo, ivhi uint64 // start counter as 64-bit limbs
+}
+
+func newCTRGeneric(block _cipher.Block, iv []byte) *ctrGeneric {
+	return &ctrGeneric{
+		b:    block,
+		ivlo: byteorder.BEUint64(iv[8:]),
+		ivhi: byteorder.BEUint64(iv[:8]),
+	}
+}
+
+// XORKeyStreamAt XORs src with the keystream starting at the given byte offset,
+// it keeps no state.
+func (x *ctrGeneric) XORKeyStreamAt(dst, src []byte, offset uint64) {
+	if len(dst) < len(src) {
+		panic("cipher: output smaller than input")
+	}
+	dst = dst[:len(src)]
+	if alias.InexactOverlap(dst, src) {
+		panic("cipher: invalid buffer overlap")
+	}
+
+	ivlo, ivhi := add128(x.ivlo, x.ivhi, offset/blockSize)
+	blockOffset := int(offset % blockSize)
+
+	batchBlocks := 1
+	concCipher, isConc := x.b.(concurrentBlocks)
+	if isConc {
+		batchBlocks = concCipher.Concurrency()
+	}
+	ctrs := make([]byte, batchBlocks*blockSize)
+	keyStream := make([]byte, batchBlocks*blockSize)
+	for len(src) > 0 {
+		for i := 0; i < len(ctrs); i += blockSize {
+			byteorder.BEPutUint64(ctrs[i:], ivhi)
+			byteorder.BEPutUint64(ctrs[i+8:], ivlo)
+			ivlo, ivhi = add128(ivlo, ivhi, 1)
+		}
+		if isConc {
+			concCipher.EncryptBlocks(keyStream, ctrs)
+		} else {
+			x.b.Encrypt(keyStream, ctrs)
+		}
+		n := subtle.XORBytes(dst, src, keyStream[blockOffset:])
+		blockOffset = 0
+		dst = dst[n:]
+		src = src[n:]
+	}
+}
+
+func add128(lo, hi uint64, x uint64) (uint64, uint64) {
+	lo, c := bits.Add64(lo, x, 0)
+	hi, _ = bits.Add64(hi, 0, c)
+	return lo, hi
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/cipher"
 	"testing"
 
+	smcipher "github.com/emmansun/gmsm/cipher"
 	"github.com/emmansun/gmsm/internal/cryptotest"
 	"github.com/emmansun/gmsm/sm4"
 )
@@ -184,3 +185,67 @@ func TestCTRStream(t *testing.T) {
 		cryptotest.TestStreamFromBlock(t, block, cipher.NewCTR)
 	})
 }
+
+func TestSeekableCTRStream(t *testing.T) {
+	rng := newRandReader(t)
+
+	key := make([]byte, 16)
+	rng.Read(key)
+
+	block, err := sm4.NewCipher(key)
+	if err != nil {
+		panic(err)
+	}
+
+	cryptotest.TestStreamFromBlock(t, block, func(b cipher.Block, iv []byte) cipher.Stream {
+		return smcipher.NewSeekableCTR(b, iv)
+	})
+}
+
+// noCTRBlock hides the optimized CTR implementation of the wrapped block.
+type noCTRBlock struct {
+	cipher.Block
+}
+
+func TestSeekableCTR_SM4(t *testing.T) {
+	key := make([]byte, 16)
+	block, err := sm4.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ivs := [][]byte{
+		{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f},
+		// low 64 bits wraparound
+		{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfd},
+		// 128-bit counter wraparound
+		{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xfe},
+	}
+	src := make([]byte, 1027)
+	for i := range src {
+		src[i] = byte(i)
+	}
+	for _, iv := range ivs {
+		want := make([]byte, len(src))
+		cipher.NewCTR(block, iv).XORKeyStream(want, src)
+
+		for _, b := range []cipher.Block{block, noCTRBlock{block}} {
+			for _, offset := range []int{0, 1, 15, 16, 17, 127, 128, 129, 500, 1026} {
+				for _, length := range []int{0, 1, 16, 31, 128, 257} {
+					end := min(offset+length, len(src))
+					got := make([]byte, end-offset)
+					stream := smcipher.NewSeekableCTR(b, iv)
+					stream.XORKeyStreamAt(got, src[offset:end], uint64(offset))
+					if !bytes.Equal(got, want[offset:end]) {
+						t.Fatalf("iv %x, offset %d, length %d: got %x, want %x", iv, offset, length, got, want[offset:end])
+					}
+					// XORKeyStream continues from the end of last XORKeyStreamAt
+					got = make([]byte, len(src)-end)
+					stream.XORKeyStream(got, src[end:])
+					if !bytes.Equal(got, want[end:]) {
+						t.Fatalf("iv %x, continue from %d: got %x, want %x", iv, end, got, want[end:])
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,324 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package ecies implements the Elliptic Curve Integrated Encryption Scheme of
+// SEC 1, Version 2.0, section 5.1, and ISO/IEC 18033-2 over the SM2 curve,
+// with SM3 based key derivation functions and SM4 based data encapsulation.
+//
+// This is not the public key encryption of GB/T 32918.4, which is implemented
+// by [sm2.Encrypt]; it is meant for the interoperability with the systems
+// which use the standard ECIES with the SM algorithms.
+//
+// The ciphertext is R || C || T, where:
+//
+//   - R is the ephemeral public key, 65 bytes uncompressed (0x04 || x || y) or
+//     33 bytes compressed (0x02/0x03 || x), see [Opts.PointCompressed];
+//   - Z is the x-coordinate of d*R, the shared secret, and Z' is Z, or R || Z
+//     in DHAES mode, see [Opts.DHAES];
+//   - the keys are KDF(Z', SharedInfo1), see [KDF];
+//   - C and T are computed by the data encapsulation, see [Cipher].
+//
+// The decryption recognizes the format of R by its first byte.
+package ecies
+
+import (
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/emmansun/gmsm/kdf"
+	"github.com/emmansun/gmsm/padding"
+	"github.com/emmansun/gmsm/sm2"
+	"github.com/emmansun/gmsm/sm2/sm2ec"
+	"github.com/emmansun/gmsm/sm3"
+	"github.com/emmansun/gmsm/sm4"
+)
+
+// KDF is a key derivation function with SM3. X963 and KDF2 give the same
+// output when SharedInfo1 is empty, which is also the key derivation function
+// of GB/T 32918.4.
+type KDF byte
+
+const (
+	// KDFX963 is the key derivation function of ANSI X9.63 and SEC 1, section
+	// 3.6.1, also KDF2 of IEEE 1363a:
+	// SM3(Z' || Counter || SharedInfo1) with a 32-bit counter starting at 1.
+	KDFX963 KDF = iota
+	// KDF2 is KDF2 of ISO/IEC 18033-2, section 6.2.3, applied to
+	// Z' || SharedInfo1: SM3(Z' || SharedInfo1 || Counter).
+	KDF2
+	// HKDF is HKDF-SM3 of RFC 5869, with an empty salt, Z' as the input keying
+	// material and SharedInfo1 as the info.
+	HKDF
+)
+
+// Cipher is a data encapsulation scheme with SM4. Since the keys are used for
+// one message only, the IV and the nonce are all zeros.
+type Cipher byte
+
+const (
+	// SM4CBCHMACSM3 encrypts with SM4 in CBC mode with PKCS #7 padding and
+	// authenticates with HMAC-SM3, as in SEC 1, section 5.1.3:
+	//
+	//	K_E || K_M = KDF(Z', 16 + 32, SharedInfo1)
+	//	C = SM4-CBC(K_E, IV = 0, PKCS7(M))
+	//	T = HMAC-SM3(K_M, C || SharedInfo2), or in DHAES mode
+	//	T = HMAC-SM3(K_M, C || SharedInfo2 || L2)
+	//
+	// L2 is the length in bits of SharedInfo2 as a 64-bit big-endian integer,
+	// as in the DEM of ISO/IEC 18033-2 and DHAES. T is 32 bytes.
+	SM4CBCHMACSM3 Cipher = iota
+	// SM4GCM encrypts and authenticates with SM4-GCM:
+	//
+	//	K = KDF(Z', 16, SharedInfo1)
+	//	C || T = SM4-GCM(K, nonce = 0, M, additional data = SharedInfo2)
+	//
+	// T is 16 bytes.
+	SM4GCM
+)
+
+// Opts are the options of the ECIES, both parties must use the same options
+// except for PointCompressed.
+type Opts struct {
+	KDF    KDF
+	Cipher Cipher
+	// PointCompressed selects the compressed encoding of the ephemeral public
+	// key R.
+	PointCompressed bool
+	// DHAES selects the DHAES mode, in which R is the prefix of the input of
+	// the KDF, which is required for the CCA security with some KDFs, and the
+	// length of SharedInfo2 is authenticated.
+	DHAES bool
+	// SharedInfo1 is the optional shared information of the KDF.
+	SharedInfo1 []byte
+	// SharedInfo2 is the optional shared information authenticated with the
+	// ciphertext.
+	SharedInfo2 []byte
+}
+
+// defaultOpts are the options of SEC 1: the X9.63 KDF, SM4-CBC with
+// HMAC-SM3, and the uncompressed ephemeral public key.
+var defaultOpts = &Opts{KDF: KDFX963, Cipher: SM4CBCHMACSM3}
+
+// ErrDecryption represents a failure to decrypt a message. It is deliberately
+// vague to avoid adaptive attacks.
+var ErrDecryption = errors.New("ecies: decryption error")
+
+const (
+	uncompressedSize = 65
+	compressedSize   = 33
+	keySize          = 16
+	macKeySize       = sm3.Size
+)
+
+// Encrypt encrypts msg with the SM2 public key pub.
+//
+// The random parameter is used as a source of entropy to ensure that
+// encrypting the same message twice doesn't result in the same ciphertext.
+// Most applications should use [crypto/rand.Reader] as random. If opts is nil,
+// the options of SEC 1 are used: the X9.63 KDF, SM4-CBC with HMAC-SM3 and the
+// uncompressed ephemeral public key.
+func Encrypt(random io.Reader, pub *ecdsa.PublicKey, msg []byte, opts *Opts) ([]byte, error) {
+	if opts == nil {
+		opts = defaultOpts
+	}
+	if opts.KDF > HKDF || opts.Cipher > SM4GCM {
+		return nil, errors.New("ecies: invalid options")
+	}
+	q, err := publicKeyToPoint(pub)
+	if err != nil {
+		return nil, err
+	}
+	k, err := sm2.GenerateKey(random)
+	if err != nil {
+		return nil, err
+	}
+	d := k.D.FillBytes(make([]byte, sm2ec.ScalarSize))
+	r, err := sm2ec.NewPoint().ScalarBaseMult(d)
+	if err != nil {
+		return nil, err
+	}
+	var ephemeral []byte
+	if opts.PointCompressed {
+		ephemeral = r.BytesCompressed()
+	} else {
+		ephemeral = r.Bytes()
+	}
+	if _, err := q.ScalarMult(q, d); err != nil {
+		return nil, err
+	}
+	z, err := q.BytesX()
+	if err != nil {
+		return nil, err
+	}
+	return seal(ephemeral, opts.deriveKeys(ephemeral, z), msg, opts)
+}
+
+// Decrypt decrypts ciphertext with the SM2 private key priv. If opts is nil,
+// the options of SEC 1 are used, see [Encrypt].
+func Decrypt(priv *sm2.PrivateKey, ciphertext []byte, opts *Opts) ([]byte, error) {
+	if opts == nil {
+		opts = defaultOpts
+	}
+	if opts.KDF > HKDF || opts.Cipher > SM4GCM {
+		return nil, errors.New("ecies: invalid options")
+	}
+	if len(ciphertext) == 0 {
+		return nil, ErrDecryption
+	}
+	pointSize := uncompressedSize
+	if ciphertext[0] == 2 || ciphertext[0] == 3 {
+		pointSize = compressedSize
+	}
+	if len(ciphertext) < pointSize {
+		return nil, ErrDecryption
+	}
+	ephemeral := ciphertext[:pointSize]
+	// SetBytes rejects the point at infinity and the points not on the curve.
+	r, err := sm2ec.NewPoint().SetBytes(ephemeral)
+	if err != nil {
+		return nil, ErrDecryption
+	}
+	if priv.D == nil || priv.D.BitLen() > 256 {
+		return nil, errors.New("ecies: invalid private key")
+	}
+	if _, err := r.ScalarMult(r, priv.D.FillBytes(make([]byte, sm2ec.ScalarSize))); err != nil {
+		return nil, ErrDecryption
+	}
+	z, err := r.BytesX()
+	if err != nil {
+		return nil, ErrDecryption
+	}
+	return open(opts.deriveKeys(ephemeral, z), ciphertext[pointSize:], opts)
+}
+
+func publicKeyToPoint(pub *ecdsa.PublicKey) (*sm2ec.Point, error) {
+	if pub == nil || pub.Curve != sm2.P256() || pub.X == nil || pub.Y == nil {
+		return nil, errors.New("ecies: public key is not on the SM2 curve")
+	}
+	b := make([]byte, uncompressedSize)
+	b[0] = 4
+	pub.X.FillBytes(b[1:33])
+	pub.Y.FillBytes(b[33:])
+	p, err := sm2ec.NewPoint().SetBytes(b)
+	if err != nil {
+		return nil, errors.New("ecies: invalid public key")
+	}
+	return p, nil
+}
+
+// deriveKeys returns the keys of the data encapsulation derived from the
+// ephemeral public key and the shared secret z.
+func (opts *Opts) deriveKeys(ephemeral, z []byte) []byte {
+	size := keySize
+	if opts.Cipher == SM4CBCHMACSM3 {
+		size += macKeySize
+	}
+	secret := z
+	if opts.DHAES {
+		secret = append(append([]byte{}, ephemeral...), z...)
+	}
+	switch opts.KDF {
+	case KDF2:
+		return kdf.Kdf(sm3.New, append(secret[:len(secret):len(secret)], opts.SharedInfo1...), size)
+	case HKDF:
+		key, err := hkdf.Key(sm3.New, secret, nil, string(opts.SharedInfo1), size)
+		if err != nil {
+			panic("ecies: internal error: " + err.Error())
+		}
+		return key
+	default:
+		return x963KDF(secret, opts.SharedInfo1, size)
+	}
+}
+
+// x963KDF is the key derivation function of ANSI X9.63 with SM3.
+func x963KDF(z, sharedInfo []byte, size int) []byte {
+	out := make([]byte, 0, size+sm3.Size)
+	var counter [4]byte
+	h := sm3.New()
+	for i := uint32(1); len(out) < size; i++ {
+		h.Reset()
+		h.Write(z)
+		binary.BigEndian.PutUint32(counter[:], i)
+		h.Write(counter[:])
+		h.Write(sharedInfo)
+		out = h.Sum(out)
+	}
+	return out[:size]
+}
+
+func newGCM(key []byte) cipher.AEAD {
+	block, err := sm4.NewCipher(key)
+	if err != nil {
+		panic("ecies: internal error: " + err.Error())
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		panic("ecies: internal error: " + err.Error())
+	}
+	return aead
+}
+
+// mac returns the tag of SM4CBCHMACSM3.
+func (opts *Opts) mac(key, c []byte) []byte {
+	m := hmac.New(sm3.New, key)
+	m.Write(c)
+	m.Write(opts.SharedInfo2)
+	if opts.DHAES {
+		var l2 [8]byte
+		binary.BigEndian.PutUint64(l2[:], uint64(len(opts.SharedInfo2))*8)
+		m.Write(l2[:])
+	}
+	return m.Sum(nil)
+}
+
+func seal(ephemeral, keys, msg []byte, opts *Opts) ([]byte, error) {
+	out := append(make([]byte, 0, len(ephemeral)+len(msg)+2*sm3.Size), ephemeral...)
+	if opts.Cipher == SM4GCM {
+		aead := newGCM(keys)
+		return aead.Seal(out, make([]byte, aead.NonceSize()), msg, opts.SharedInfo2), nil
+	}
+	block, err := sm4.NewCipher(keys[:keySize])
+	if err != nil {
+		return nil, err
+	}
+	c := padding.NewPKCS7Padding(sm4.BlockSize).Pad(append([]byte{}, msg...))
+	cipher.NewCBCEncrypter(block, make([]byte, sm4.BlockSize)).CryptBlocks(c, c)
+	out = append(out, c...)
+	return append(out, opts.mac(keys[keySize:], c)...), nil
+}
+
+func open(keys, ciphertext []byte, opts *Opts) ([]byte, error) {
+	if opts.Cipher == SM4GCM {
+		aead := newGCM(keys)
+		msg, err := aead.Open(nil, make([]byte, aead.NonceSize()), ciphertext, opts.SharedInfo2)
+		if err != nil {
+			return nil, ErrDecryption
+		}
+		return msg, nil
+	}
+	if len(ciphertext) < sm4.BlockSize+sm3.Size || (len(ciphertext)-sm3.Size)%sm4.BlockSize != 0 {
+		return nil, ErrDecryption
+	}
+	c, tag := ciphertext[:len(ciphertext)-sm3.Size], ciphertext[len(ciphertext)-sm3.Size:]
+	if !hmac.Equal(tag, opts.mac(keys[keySize:], c)) {
+		return nil, ErrDecryption
+	}
+	block, err := sm4.NewCipher(keys[:keySize])
+	if err != nil {
+		return nil, err
+	}
+	msg := make([]byte, len(c))
+	cipher.NewCBCDecrypter(block, make([]byte, sm4.BlockSize)).CryptBlocks(msg, c)
+	msg, err = padding.NewPKCS7Padding(sm4.BlockSize).ConstantTimeUnpad(msg)
+	if err != nil {
+		return nil, ErrDecryption
+	}
+	return msg, nil
+}
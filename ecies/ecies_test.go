@@ -0,0 +1,180 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ecies
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/emmansun/gmsm/sm2"
+)
+
+// testKey is SM3("ecies test key") reduced modulo N.
+const testKey = "cc5f96b1b8779a42ea98cdd683b74cd01be342a683d7efaaa2196a0512c32a2b"
+
+// The ciphertexts are recorded from this package for each combination of KDF
+// and cipher, the ephemeral key of the i-th vector is SM3("ecies test nonce i")
+// reduced modulo N.
+var vectors = []struct {
+	kdf         KDF
+	cipher      Cipher
+	compressed  bool
+	dhaes       bool
+	sharedInfo1 string
+	sharedInfo2 string
+	msg         string
+	ciphertext  string
+}{
+	{KDFX963, SM4CBCHMACSM3, false, false, "", "", "encryption standard",
+		"04749eb98e2c7f082dd7664e76f3d24702cb49ac67a907843f32b1dd742016348aaca2669144f24b68573b34c2e54d5622c9890be4af53284aa61b6b8923653decd81ad894179d81963b1b6785a0bbb3a6f3743073f05340eae51b4d709b978f0736427a3ecf8de3458ecd67a286b5cebc41dcc384b9edc4b276765cb7a19d2783"},
+	{KDF2, SM4CBCHMACSM3, true, true, "shared info 1", "shared info 2", "encryption standard",
+		"033bc540c41682361d453dac8faceb6f762b4998ba753212705333ad8645f91429ba29854ab34d0f3808630464fd30199f043baf8133ba2e04d826883fee875440c0c586735fbb098c4b41b47d691f5527ce01d3270ce810bec23ee611e6ee7a16"},
+	{HKDF, SM4CBCHMACSM3, false, true, "info", "", "",
+		"044ab6dd0a73be5d671a391e7bb43d98fc470e76907d51b17180904dbdf230933e5652b4f90eeef604d74f13f0251dd5c69d85efc6fc66806af49673df3efc1a9502ec9e546a03cd14fe0fa7d1833abde9d97e0e4583d2eaa55b8f6e146426da12a3c55b8fee4584631316735b35f8c473"},
+	{KDFX963, SM4GCM, true, false, "", "", "encryption standard",
+		"033abb77d28c28ff7153ad09c21ddd4dd6dfe1b481dd2ecd1b22f7cb368760a3fe1d2c0cecbcde893a62be17c1d1ba7eb814f5c695cf67477380595b5732f5341b140949"},
+	{KDF2, SM4GCM, false, true, "shared info 1", "shared info 2", "0123456789abcdef0123456789abcdef!",
+		"047bafe868e2cdca88df3bb210482f3a1606b2710678c68332f21ef404304a3216a7bc9ee9a1160522a5059193bb7c37b395d3290f43d7c016a4b145666e09e92489557bd3efae55fe7f9316fb9245f4137a09174e33bbb95e688e26ba9ca253fc002d8413ff7854885213e44e82f6a04ae1"},
+	{HKDF, SM4GCM, true, true, "info", "aad", "",
+		"03dbf087f52c81d18ce32302589bb4ab7d6ee80ca47825214eea56ef3a988ac403af10e95ecc4beaad99f8ef7fb94e774d"},
+}
+
+func testPrivateKey(t testing.TB) *sm2.PrivateKey {
+	t.Helper()
+	d, _ := hex.DecodeString(testKey)
+	priv, err := sm2.NewPrivateKey(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return priv
+}
+
+func TestVectors(t *testing.T) {
+	priv := testPrivateKey(t)
+	for i, v := range vectors {
+		opts := &Opts{
+			KDF:             v.kdf,
+			Cipher:          v.cipher,
+			PointCompressed: v.compressed,
+			DHAES:           v.dhaes,
+			SharedInfo1:     []byte(v.sharedInfo1),
+			SharedInfo2:     []byte(v.sharedInfo2),
+		}
+		ciphertext, _ := hex.DecodeString(v.ciphertext)
+		msg, err := Decrypt(priv, ciphertext, opts)
+		if err != nil {
+			t.Errorf("case %d: %v", i, err)
+			continue
+		}
+		if string(msg) != v.msg {
+			t.Errorf("case %d: got %q, want %q", i, msg, v.msg)
+		}
+		// any change of the options gives another key
+		opts.DHAES = !opts.DHAES
+		if _, err := Decrypt(priv, ciphertext, opts); err != ErrDecryption {
+			t.Errorf("case %d: expected decryption error with another mode, got %v", i, err)
+		}
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	priv, err := sm2.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, kdf := range []KDF{KDFX963, KDF2, HKDF} {
+		for _, cipher := range []Cipher{SM4CBCHMACSM3, SM4GCM} {
+			for _, compressed := range []bool{false, true} {
+				for _, dhaes := range []bool{false, true} {
+					opts := &Opts{KDF: kdf, Cipher: cipher, PointCompressed: compressed, DHAES: dhaes, SharedInfo2: []byte("label")}
+					t.Run(fmt.Sprintf("%d-%d-%v-%v", kdf, cipher, compressed, dhaes), func(t *testing.T) {
+						for _, msg := range []string{"", "a", "0123456789abcdef", "encryption standard"} {
+							ciphertext, err := Encrypt(rand.Reader, &priv.PublicKey, []byte(msg), opts)
+							if err != nil {
+								t.Fatal(err)
+							}
+							got, err := Decrypt(priv, ciphertext, opts)
+							if err != nil {
+								t.Fatal(err)
+							}
+							if string(got) != msg {
+								t.Fatalf("got %q, want %q", got, msg)
+							}
+						}
+					})
+				}
+			}
+		}
+	}
+	// the default options
+	ciphertext, err := Encrypt(rand.Reader, &priv.PublicKey, []byte("default"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ciphertext) != 65+16+32 {
+		t.Errorf("unexpected ciphertext length %d", len(ciphertext))
+	}
+	if got, err := Decrypt(priv, ciphertext, &Opts{}); err != nil || string(got) != "default" {
+		t.Errorf("got %q, %v", got, err)
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	priv := testPrivateKey(t)
+	other, err := sm2.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cipher := range []Cipher{SM4CBCHMACSM3, SM4GCM} {
+		opts := &Opts{Cipher: cipher, SharedInfo2: []byte("label")}
+		ciphertext, err := Encrypt(rand.Reader, &priv.PublicKey, []byte("encryption standard"), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Decrypt(other, ciphertext, opts); err != ErrDecryption {
+			t.Errorf("expected decryption error with another key, got %v", err)
+		}
+		if _, err := Decrypt(priv, ciphertext, &Opts{Cipher: cipher}); err != ErrDecryption {
+			t.Errorf("expected decryption error with another SharedInfo2, got %v", err)
+		}
+		for _, i := range []int{0, 1, 70, len(ciphertext) - 1} {
+			tampered := bytes.Clone(ciphertext)
+			tampered[i] ^= 1
+			if _, err := Decrypt(priv, tampered, opts); err != ErrDecryption {
+				t.Errorf("expected decryption error with byte %d tampered, got %v", i, err)
+			}
+		}
+		for _, size := range []int{0, 1, 33, 65, len(ciphertext) - 1} {
+			if _, err := Decrypt(priv, ciphertext[:size], opts); err != ErrDecryption {
+				t.Errorf("expected decryption error with %d bytes, got %v", size, err)
+			}
+		}
+	}
+	if _, err := Decrypt(priv, []byte{4}, &Opts{KDF: 3}); err == nil {
+		t.Error("expected error with invalid options")
+	}
+	if _, err := Encrypt(rand.Reader, &priv.PublicKey, nil, &Opts{Cipher: 2}); err == nil {
+		t.Error("expected error with invalid options")
+	}
+}
+
+func BenchmarkEncrypt(b *testing.B) {
+	priv := testPrivateKey(b)
+	msg := make([]byte, 1024)
+	for _, opts := range []*Opts{{Cipher: SM4CBCHMACSM3}, {Cipher: SM4GCM}} {
+		b.Run(fmt.Sprint(opts.Cipher), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(msg)))
+			for i := 0; i < b.N; i++ {
+				if _, err := Encrypt(rand.Reader, &priv.PublicKey, msg, opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ecqv
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/emmansun/gmsm/sm2/sm2ec"
+)
+
+// KeyUsage is the set of the usages of the certified key.
+type KeyUsage byte
+
+const (
+	KeyUsageDigitalSignature KeyUsage = 1 << iota
+	KeyUsageKeyAgreement
+	KeyUsageKeyEncipherment
+	KeyUsageCertSign
+)
+
+// IDSize is the size in bytes of the issuer and subject identifiers.
+const IDSize = 8
+
+// CertificateSize is the size in bytes of an encoded certificate.
+const CertificateSize = 1 + 8 + IDSize + 5 + 4 + IDSize + 1 + pointSize
+
+const (
+	certType  = 0x00 // fixed-length fields, SM2 curve, SM3
+	pointSize = 33   // compressed point
+)
+
+// Certificate is an ECQV implicit certificate. It doesn't contain the public
+// key of the subject but the public key reconstruction data, from which anyone
+// can compute the public key with the public key of the issuer, see
+// [Certificate.PublicKey].
+//
+// The encoding follows the fixed-length fields of the minimal encoding scheme
+// of SEC 4, Version 1.0, with the curve and hash fields implied by the type:
+//
+//	type            1 byte, 0x00
+//	serial number   8 bytes
+//	issuer          8 bytes
+//	not before      5 bytes, seconds since the Unix epoch
+//	validity        4 bytes, seconds
+//	subject         8 bytes
+//	key usage       1 byte
+//	reconstruction  33 bytes, compressed point
+//
+// All the integers are big-endian.
+type Certificate struct {
+	Raw []byte // the complete encoding, the input of the hash of SEC 4
+
+	SerialNumber uint64
+	Issuer       [IDSize]byte
+	Subject      [IDSize]byte
+	NotBefore    time.Time
+	NotAfter     time.Time
+	KeyUsage     KeyUsage
+
+	// PublicKeyReconstruction is the compressed public key reconstruction
+	// point P_U.
+	PublicKeyReconstruction []byte
+}
+
+// marshal encodes the fields of c, without checking the point.
+func (c *Certificate) marshal() ([]byte, error) {
+	notBefore := c.NotBefore.Unix()
+	validity := c.NotAfter.Unix() - notBefore
+	if notBefore < 0 || notBefore >= 1<<40 {
+		return nil, errors.New("ecqv: NotBefore is out of range")
+	}
+	if validity < 0 || validity >= 1<<32 {
+		return nil, errors.New("ecqv: validity period is out of range")
+	}
+	if len(c.PublicKeyReconstruction) != pointSize {
+		return nil, errors.New("ecqv: invalid public key reconstruction point")
+	}
+	b := make([]byte, 0, CertificateSize)
+	b = append(b, certType)
+	b = binary.BigEndian.AppendUint64(b, c.SerialNumber)
+	b = append(b, c.Issuer[:]...)
+	b = append(b, byte(notBefore>>32))
+	b = binary.BigEndian.AppendUint32(b, uint32(notBefore))
+	b = binary.BigEndian.AppendUint32(b, uint32(validity))
+	b = append(b, c.Subject[:]...)
+	b = append(b, byte(c.KeyUsage))
+	return append(b, c.PublicKeyReconstruction...), nil
+}
+
+// ParseCertificate parses an encoded implicit certificate. It checks that the
+// public key reconstruction data is a valid point.
+func ParseCertificate(b []byte) (*Certificate, error) {
+	if len(b) != CertificateSize || b[0] != certType {
+		return nil, errors.New("ecqv: invalid certificate encoding")
+	}
+	c := &Certificate{Raw: append([]byte{}, b...)}
+	b = b[1:]
+	c.SerialNumber = binary.BigEndian.Uint64(b)
+	b = b[8:]
+	copy(c.Issuer[:], b)
+	b = b[IDSize:]
+	notBefore := int64(b[0])<<32 | int64(binary.BigEndian.Uint32(b[1:]))
+	validity := int64(binary.BigEndian.Uint32(b[5:]))
+	c.NotBefore = time.Unix(notBefore, 0).UTC()
+	c.NotAfter = time.Unix(notBefore+validity, 0).UTC()
+	b = b[9:]
+	copy(c.Subject[:], b)
+	b = b[IDSize:]
+	c.KeyUsage = KeyUsage(b[0])
+	c.PublicKeyReconstruction = b[1:]
+	if _, err := sm2ec.NewPoint().SetBytes(c.PublicKeyReconstruction); err != nil {
+		return nil, errors.New("ecqv: invalid public key reconstruction point")
+	}
+	return c, nil
+}
+
+// ValidAt reports whether t is within the validity period of c.
+func (c *Certificate) ValidAt(t time.Time) bool {
+	return !t.Before(c.NotBefore) && !t.After(c.NotAfter)
+}
@@ -0,0 +1,239 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package ecqv implements the elliptic curve Qu-Vanstone implicit certificate
+// scheme of SEC 4, Version 1.0, over the SM2 curve with SM3.
+//
+// An implicit certificate is 68 bytes long: instead of the public key of
+// the subject and a signature of the CA, it contains a point from which the
+// public key is reconstructed with the public key of the CA. The certificate
+// is implicitly verified when the subject uses the key, for example when an
+// SM2 signature made with the reconstructed private key is verified with the
+// public key extracted from the certificate.
+//
+// The issuance has three steps:
+//
+//  1. the requester calls [NewRequest] and sends the [Request] to the CA;
+//  2. the CA calls [CreateCertificate], and returns the certificate and the
+//     private key reconstruction data r to the requester;
+//  3. the requester calls [ReconstructPrivateKey] to compute its private key.
+//
+// Anyone computes the public key with [Certificate.PublicKey]. The hash Hn of
+// SEC 4 is SM3 of the encoded certificate, reduced modulo the order N.
+package ecqv
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"io"
+
+	"github.com/emmansun/gmsm/internal/bigmod"
+	"github.com/emmansun/gmsm/sm2"
+	"github.com/emmansun/gmsm/sm2/sm2ec"
+	"github.com/emmansun/gmsm/sm3"
+)
+
+var orderModulus, _ = bigmod.NewModulus(sm2ec.P256().Params().N.Bytes())
+
+// Request is a certificate request of SEC 4, section 3.3. Its encoding is the
+// subject identifier followed by the compressed point R_U.
+type Request struct {
+	Subject [IDSize]byte
+	R       []byte // the compressed point R_U = k_U*G
+}
+
+// RequestSize is the size in bytes of an encoded request.
+const RequestSize = IDSize + pointSize
+
+// NewRequest generates a certificate request of the subject. The returned
+// private key k_U is ephemeral, it must be kept by the requester until the
+// private key is reconstructed with [ReconstructPrivateKey].
+func NewRequest(rand io.Reader, subject [IDSize]byte) (*Request, *sm2.PrivateKey, error) {
+	k, err := sm2.GenerateKey(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	p, err := publicKeyToPoint(&k.PublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &Request{Subject: subject, R: p.BytesCompressed()}, k, nil
+}
+
+// Bytes returns the encoding of the request.
+func (req *Request) Bytes() []byte {
+	return append(req.Subject[:len(req.Subject):len(req.Subject)], req.R...)
+}
+
+// ParseRequest parses an encoded request and checks that R_U is a valid
+// point.
+func ParseRequest(b []byte) (*Request, error) {
+	if len(b) != RequestSize {
+		return nil, errors.New("ecqv: invalid request encoding")
+	}
+	req := &Request{R: append([]byte{}, b[IDSize:]...)}
+	copy(req.Subject[:], b)
+	if _, err := sm2ec.NewPoint().SetBytes(req.R); err != nil || len(req.R) != pointSize {
+		return nil, errors.New("ecqv: invalid request point")
+	}
+	return req, nil
+}
+
+// hashToScalar returns e = Hn(cert), SM3 of the certificate reduced modulo N.
+func hashToScalar(cert []byte) *sm2ec.Scalar {
+	h := sm3.Sum(cert)
+	e, err := bigmod.NewNat().SetOverflowingBytes(h[:], orderModulus)
+	if err != nil {
+		panic("ecqv: internal error: " + err.Error())
+	}
+	s, err := sm2ec.NewScalar().SetCanonicalBytes(e.Bytes(orderModulus))
+	if err != nil {
+		panic("ecqv: internal error: " + err.Error())
+	}
+	return s
+}
+
+func publicKeyToPoint(pub *ecdsa.PublicKey) (*sm2ec.Point, error) {
+	if pub.Curve != sm2ec.P256() || pub.X == nil || pub.Y == nil {
+		return nil, errors.New("ecqv: public key is not on the SM2 curve")
+	}
+	b := make([]byte, 65)
+	b[0] = 4
+	pub.X.FillBytes(b[1:33])
+	pub.Y.FillBytes(b[33:])
+	return sm2ec.NewPoint().SetBytes(b)
+}
+
+func privateKeyToScalar(priv *sm2.PrivateKey) (*sm2ec.Scalar, error) {
+	if priv.D == nil || priv.D.BitLen() > 256 {
+		return nil, errors.New("ecqv: invalid private key")
+	}
+	return sm2ec.NewScalar().SetCanonicalBytes(priv.D.FillBytes(make([]byte, sm2ec.ScalarSize)))
+}
+
+// IssuerID returns the default issuer identifier of a CA, the first 8 bytes
+// of SM3 of its compressed public key.
+func IssuerID(ca *ecdsa.PublicKey) ([IDSize]byte, error) {
+	var id [IDSize]byte
+	p, err := publicKeyToPoint(ca)
+	if err != nil {
+		return id, err
+	}
+	h := sm3.Sum(p.BytesCompressed())
+	copy(id[:], h[:])
+	return id, nil
+}
+
+// CreateCertificate processes the request and issues an implicit certificate
+// with the fields of template, as the certificate generation of SEC 4,
+// section 3.4. The subject is the one of the request, and the issuer is
+// [IssuerID] of the CA if the one of template is zero.
+//
+// It returns the encoded certificate and the 32-byte private key
+// reconstruction data r, both sent to the requester.
+func CreateCertificate(rand io.Reader, template *Certificate, req *Request, ca *sm2.PrivateKey) (cert, r []byte, err error) {
+	ru, err := sm2ec.NewPoint().SetBytes(req.R)
+	if err != nil || len(req.R) != pointSize {
+		return nil, nil, errors.New("ecqv: invalid request point")
+	}
+	dCA, err := privateKeyToScalar(ca)
+	if err != nil {
+		return nil, nil, err
+	}
+	c := *template
+	c.Subject = req.Subject
+	if c.Issuer == [IDSize]byte{} {
+		if c.Issuer, err = IssuerID(&ca.PublicKey); err != nil {
+			return nil, nil, err
+		}
+	}
+	infinity := sm2ec.NewPoint()
+	for {
+		k, err := sm2.GenerateKey(rand)
+		if err != nil {
+			return nil, nil, err
+		}
+		kG, err := publicKeyToPoint(&k.PublicKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		// P_U = R_U + k*G, which is the identity with negligible probability
+		pu := sm2ec.NewPoint().Add(ru, kG)
+		if pu.Equal(infinity) == 1 {
+			continue
+		}
+		c.PublicKeyReconstruction = pu.BytesCompressed()
+		cert, err = c.marshal()
+		if err != nil {
+			return nil, nil, err
+		}
+		kScalar, err := privateKeyToScalar(k)
+		if err != nil {
+			return nil, nil, err
+		}
+		// r = e*k + d_CA mod N
+		e := hashToScalar(cert)
+		rs := sm2ec.NewScalar().Multiply(e, kScalar)
+		rs.Add(rs, dCA)
+		return cert, rs.Bytes(), nil
+	}
+}
+
+// ReconstructPrivateKey computes the private key d_U = e*k_U + r mod N of the
+// certificate, where k_U is the private key returned by [NewRequest], as the
+// certificate reception of SEC 4, section 3.5. It checks that the key pair is
+// consistent with the public key extracted from the certificate.
+func ReconstructPrivateKey(cert, r []byte, requestKey *sm2.PrivateKey, ca *ecdsa.PublicKey) (*sm2.PrivateKey, error) {
+	c, err := ParseCertificate(cert)
+	if err != nil {
+		return nil, err
+	}
+	rs, err := sm2ec.NewScalar().SetCanonicalBytes(r)
+	if err != nil {
+		return nil, errors.New("ecqv: invalid private key reconstruction data")
+	}
+	ku, err := privateKeyToScalar(requestKey)
+	if err != nil {
+		return nil, err
+	}
+	du := sm2ec.NewScalar().Multiply(hashToScalar(c.Raw), ku)
+	du.Add(du, rs)
+	priv, err := sm2.NewPrivateKey(du.Bytes())
+	if err != nil {
+		return nil, errors.New("ecqv: invalid reconstructed private key")
+	}
+	pub, err := c.PublicKey(ca)
+	if err != nil {
+		return nil, err
+	}
+	if !priv.PublicKey.Equal(pub) {
+		return nil, errors.New("ecqv: reconstructed private key doesn't match the certificate")
+	}
+	return priv, nil
+}
+
+// PublicKey extracts the public key Q_U = e*P_U + Q_CA of the subject from the
+// certificate and the public key of the CA, as SEC 4, section 3.6.
+func (c *Certificate) PublicKey(ca *ecdsa.PublicKey) (*ecdsa.PublicKey, error) {
+	qCA, err := publicKeyToPoint(ca)
+	if err != nil {
+		return nil, err
+	}
+	pu, err := sm2ec.NewPoint().SetBytes(c.PublicKeyReconstruction)
+	if err != nil {
+		return nil, errors.New("ecqv: invalid public key reconstruction point")
+	}
+	raw := c.Raw
+	if raw == nil {
+		if raw, err = c.marshal(); err != nil {
+			return nil, err
+		}
+	}
+	qu, err := sm2ec.NewPoint().ScalarMult(pu, hashToScalar(raw).Bytes())
+	if err != nil {
+		return nil, err
+	}
+	qu.Add(qu, qCA)
+	return sm2.NewPublicKey(qu.Bytes())
+}
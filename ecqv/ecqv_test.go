@@ -0,0 +1,232 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ecqv
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/emmansun/gmsm/sm2"
+	"github.com/emmansun/gmsm/sm3"
+)
+
+func decodeHex(t testing.TB, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// TestVector pins the certificate and the keys issued for fixed inputs: the
+// CA key, the request key and the CA nonce are SM3 of "ecqv test CA",
+// "ecqv test request" and "ecqv test CA nonce" reduced modulo N.
+func TestVector(t *testing.T) {
+	ca, err := sm2.NewPrivateKey(decodeHex(t, "074fe288b6eed093de4196e5bb6b13fae7487e1694c7d88786904cefa8dcec0a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ku, err := sm2.NewPrivateKey(decodeHex(t, "f6fb586866c3169ecd27b93c666a4ba8bcccfdb534865ce91a05de18f781204a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	certBytes := decodeHex(t, "00010203040506070848fa9c79f506e3fd006955b90001e133806465766963653031010387243d99b51a7c87f26e827d8988848b6ea409d0ebd41ac1bfb672871f44dcc8")
+	r := decodeHex(t, "5db3cf01091f0f34e2616f77360071dd441a08d13e12252f2050171bd7b28cfb")
+	wantD := decodeHex(t, "df6673b6a00c19a97853fdeab6e3f4b15ada56678ddb3642d971d9a0e0cf1b27")
+	wantQ := decodeHex(t, "04fd96b68438c39f3fcfc53b23ff81159a402e40e6b7a3c24da623b4774f999eac5b99605b3f13065d881733cbe3196fb8d1a66a9ad22fe11aef1687536f6ca778")
+
+	cert, err := ParseCertificate(certBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer, err := IssuerID(&ca.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.SerialNumber != 0x0102030405060708 || cert.Issuer != issuer ||
+		string(cert.Subject[:]) != "device01" || cert.KeyUsage != KeyUsageDigitalSignature ||
+		!cert.NotBefore.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) ||
+		!cert.NotAfter.Equal(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected certificate fields %+v", cert)
+	}
+	if raw, err := cert.marshal(); err != nil || !bytes.Equal(raw, certBytes) {
+		t.Errorf("re-encoding mismatch: %x, %v", raw, err)
+	}
+
+	pub, err := cert.PublicKey(&ca.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := append(append([]byte{4}, pub.X.FillBytes(make([]byte, 32))...), pub.Y.FillBytes(make([]byte, 32))...); !bytes.Equal(got, wantQ) {
+		t.Errorf("public key = %x, want %x", got, wantQ)
+	}
+	priv, err := ReconstructPrivateKey(certBytes, r, ku, &ca.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := priv.D.FillBytes(make([]byte, 32)); !bytes.Equal(got, wantD) {
+		t.Errorf("private key = %x, want %x", got, wantD)
+	}
+}
+
+func issue(t *testing.T, ca *sm2.PrivateKey) (*Request, *sm2.PrivateKey, []byte, []byte) {
+	t.Helper()
+	req, ku, err := NewRequest(rand.Reader, [IDSize]byte{'s', 'u', 'b', 'j', 'e', 'c', 't'})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the request goes through its encoding to the CA
+	req, err = ParseRequest(req.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &Certificate{
+		SerialNumber: 42,
+		NotBefore:    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC),
+		KeyUsage:     KeyUsageDigitalSignature | KeyUsageKeyAgreement,
+	}
+	cert, r, err := CreateCertificate(rand.Reader, template, req, ca)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cert) != CertificateSize || len(r) != 32 {
+		t.Fatalf("unexpected sizes %d, %d", len(cert), len(r))
+	}
+	return req, ku, cert, r
+}
+
+func TestRoundTrip(t *testing.T) {
+	ca, err := sm2.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, ku, certBytes, r := issue(t, ca)
+	priv, err := ReconstructPrivateKey(certBytes, r, ku, &ca.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := ParseCertificate(certBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert.Subject != req.Subject || cert.SerialNumber != 42 ||
+		cert.KeyUsage != KeyUsageDigitalSignature|KeyUsageKeyAgreement {
+		t.Errorf("unexpected certificate fields %+v", cert)
+	}
+	if !cert.ValidAt(time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)) || cert.ValidAt(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("unexpected validity period")
+	}
+	pub, err := cert.PublicKey(&ca.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pub.Equal(&priv.PublicKey) {
+		t.Fatal("extracted public key doesn't match the private key")
+	}
+
+	// the key is implicitly verified by its use
+	digest := sm3.Sum([]byte("implicitly certified"))
+	sig, err := priv.Sign(rand.Reader, digest[:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sm2.VerifyASN1(pub, digest[:], sig) {
+		t.Error("signature verification failed")
+	}
+
+	// a certificate without Raw is re-encoded
+	cert.Raw = nil
+	if pub2, err := cert.PublicKey(&ca.PublicKey); err != nil || !pub2.Equal(pub) {
+		t.Errorf("public key without Raw mismatch: %v", err)
+	}
+}
+
+func TestTampering(t *testing.T) {
+	ca, err := sm2.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := sm2.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ku, cert, r := issue(t, ca)
+
+	if _, err := ReconstructPrivateKey(cert, r, ku, &other.PublicKey); err == nil {
+		t.Error("expected error with another CA key")
+	}
+	if _, err := ReconstructPrivateKey(cert, r, other, &ca.PublicKey); err == nil {
+		t.Error("expected error with another request key")
+	}
+	badR := bytes.Clone(r)
+	badR[31] ^= 1
+	if _, err := ReconstructPrivateKey(cert, badR, ku, &ca.PublicKey); err == nil {
+		t.Error("expected error with tampered reconstruction data")
+	}
+	// the serial number is hashed, any change gives another key
+	badCert := bytes.Clone(cert)
+	badCert[8] ^= 1
+	if _, err := ReconstructPrivateKey(badCert, r, ku, &ca.PublicKey); err == nil {
+		t.Error("expected error with tampered certificate")
+	}
+	badCert = bytes.Clone(cert)
+	badCert[0] = 1
+	if _, err := ParseCertificate(badCert); err == nil {
+		t.Error("expected error with unknown certificate type")
+	}
+	if _, err := ParseCertificate(cert[:len(cert)-1]); err == nil {
+		t.Error("expected error with truncated certificate")
+	}
+	badCert = bytes.Clone(cert)
+	badCert[CertificateSize-pointSize] = 4
+	if _, err := ParseCertificate(badCert); err == nil {
+		t.Error("expected error with invalid point")
+	}
+	if _, err := ParseRequest(make([]byte, RequestSize)); err == nil {
+		t.Error("expected error with invalid request point")
+	}
+}
+
+func TestTemplateRange(t *testing.T) {
+	ca, err := sm2.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _, err := NewRequest(rand.Reader, [IDSize]byte{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, template := range []*Certificate{
+		{NotBefore: time.Unix(-1, 0), NotAfter: time.Unix(10, 0)},
+		{NotBefore: time.Unix(10, 0), NotAfter: time.Unix(9, 0)},
+		{NotBefore: time.Unix(0, 0), NotAfter: time.Unix(1<<32, 0)},
+	} {
+		if _, _, err := CreateCertificate(rand.Reader, template, req, ca); err == nil {
+			t.Errorf("expected error with validity %v - %v", template.NotBefore, template.NotAfter)
+		}
+	}
+}
+
+func BenchmarkPublicKey(b *testing.B) {
+	ca, _ := sm2.GenerateKey(rand.Reader)
+	req, _, _ := NewRequest(rand.Reader, [IDSize]byte{})
+	certBytes, _, err := CreateCertificate(rand.Reader, &Certificate{}, req, ca)
+	if err != nil {
+		b.Fatal(err)
+	}
+	cert, _ := ParseCertificate(certBytes)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := cert.PublicKey(&ca.PublicKey); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package ecvrf implements the elliptic curve verifiable random function
+// (ECVRF) specified in RFC 9381.
+//
+// Besides ECVRF-P256-SHA256-SSWU of RFC 9381, section 5.5, the package defines
+// the ciphersuite ECVRF-SM2-SM3-SSWU, which is the same construction over the
+// SM2 curve:
+//
+//   - suite_string is 0xF1, outside of the values assigned by RFC 9381;
+//   - the hash function is SM3, for the challenge, the nonce and the output;
+//   - ECVRF_encode_to_curve is encode_to_curve with the suite
+//     SM2P256_XMD:SM3_SSWU_NU_ of package h2c, the salt is the public key and
+//     the domain separation tag is "ECVRF_" || h2c_suite_ID_string || suite_string;
+//   - ECVRF_nonce_generation is RFC 6979, section 3.2, with HMAC-SM3;
+//   - points are encoded in compressed form, ptLen = 33, cLen = 16, qLen = 32.
+//
+// Keys are ordinary [sm2.PrivateKey] and [ecdsa.PublicKey] values, so an SM2
+// signing key can be used as a VRF key as well.
+package ecvrf
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"hash"
+
+	"github.com/emmansun/gmsm/h2c"
+	"github.com/emmansun/gmsm/internal/bigmod"
+	"github.com/emmansun/gmsm/internal/ecgroup"
+	"github.com/emmansun/gmsm/sm2"
+	"github.com/emmansun/gmsm/sm2/sm2ec"
+	"github.com/emmansun/gmsm/sm3"
+)
+
+// The identifiers of the supported ciphersuites.
+const (
+	SM2SM3SSWU     = "ECVRF-SM2-SM3-SSWU"
+	P256SHA256SSWU = "ECVRF-P256-SHA256-SSWU"
+)
+
+const (
+	ptLen = 33 // length of a compressed point
+	cLen  = 16 // length of the challenge
+	qLen  = 32 // length of a scalar
+
+	// ProofSize is the size in bytes of a proof of both suites.
+	ProofSize = ptLen + cLen + qLen
+)
+
+var errInvalidProof = errors.New("ecvrf: invalid proof")
+
+// Suite is an ECVRF ciphersuite.
+type Suite struct {
+	id          string
+	suiteString byte
+	curve       elliptic.Curve // the curve of the keys
+	group       *ecgroup.Group
+	n           *bigmod.Modulus
+	h2c         *h2c.Suite
+	dst         []byte
+	hash        func() hash.Hash
+}
+
+var suites = map[string]*Suite{
+	SM2SM3SSWU:     newSuite(SM2SM3SSWU, 0xF1, sm2ec.P256(), ecgroup.SM2, h2c.SM2P256NU, sm3.New),
+	P256SHA256SSWU: newSuite(P256SHA256SSWU, 0x02, elliptic.P256(), ecgroup.P256, h2c.P256NU, sha256.New),
+}
+
+func newSuite(id string, suiteString byte, curve elliptic.Curve, group *ecgroup.Group, h2cID string, h func() hash.Hash) *Suite {
+	encoder, err := h2c.NewSuite(h2cID)
+	if err != nil {
+		panic(err)
+	}
+	dst := append([]byte("ECVRF_"+h2cID), suiteString)
+	return &Suite{id: id, suiteString: suiteString, curve: curve, group: group, n: group.Order(), h2c: encoder, dst: dst, hash: h}
+}
+
+// NewSuite returns the ciphersuite of the identifier id.
+func NewSuite(id string) (*Suite, error) {
+	s, ok := suites[id]
+	if !ok {
+		return nil, errors.New("ecvrf: unsupported suite " + id)
+	}
+	return s, nil
+}
+
+// ID returns the identifier of the suite.
+func (s *Suite) ID() string {
+	return s.id
+}
+
+// Prove computes the proof pi of alpha with the private key priv, as
+// ECVRF_prove of RFC 9381, section 5.1. The proof is deterministic.
+func (s *Suite) Prove(priv *ecdsa.PrivateKey, alpha []byte) ([]byte, error) {
+	if priv.Curve != s.curve {
+		return nil, errors.New("ecvrf: private key is not on the curve of the suite")
+	}
+	if priv.D == nil || priv.D.BitLen() > 8*qLen {
+		return nil, errors.New("ecvrf: invalid private key")
+	}
+	x, err := bigmod.NewNat().SetBytes(priv.D.FillBytes(make([]byte, qLen)), s.n)
+	if err != nil || x.IsZero() == 1 {
+		return nil, errors.New("ecvrf: invalid private key")
+	}
+
+	Y := s.group.ScalarBaseMult(x)
+	H, err := s.encodeToCurve(Y, alpha)
+	if err != nil {
+		return nil, err
+	}
+	gamma := H.ScalarMult(x)
+	k := s.nonce(x.Bytes(s.n), H.BytesCompressed())
+	U := s.group.ScalarBaseMult(k)
+	V := H.ScalarMult(k)
+	challenge := s.challenge(Y, H, gamma, U, V)
+
+	// s = (k + c*x) mod q
+	e, _ := bigmod.NewNat().SetBytes(challenge, s.n)
+	k.Add(e.Mul(x, s.n), s.n)
+
+	pi := make([]byte, 0, ProofSize)
+	pi = append(pi, gamma.BytesCompressed()...)
+	pi = append(pi, challenge...)
+	return append(pi, k.Bytes(s.n)...), nil
+}
+
+// Verify checks the proof pi of alpha with the public key pub, as
+// ECVRF_verify of RFC 9381, section 5.3, with the key validation of section
+// 5.4.5. It returns the VRF output beta if the proof is valid.
+func (s *Suite) Verify(pub *ecdsa.PublicKey, alpha, pi []byte) ([]byte, error) {
+	if pub.Curve != s.curve || pub.X == nil || pub.Y == nil || !s.curve.IsOnCurve(pub.X, pub.Y) {
+		return nil, errors.New("ecvrf: invalid public key")
+	}
+	Y, err := s.group.DecodeUncompressed(elliptic.Marshal(s.curve, pub.X, pub.Y))
+	if err != nil {
+		return nil, errors.New("ecvrf: invalid public key")
+	}
+	gamma, challenge, sc, err := s.decodeProof(pi)
+	if err != nil {
+		return nil, err
+	}
+	H, err := s.encodeToCurve(Y, alpha)
+	if err != nil {
+		return nil, err
+	}
+	// -c mod q, so that U = s*B - c*Y and V = s*H - c*Gamma are sums
+	c, _ := bigmod.NewNat().SetBytes(challenge, s.n)
+	negC := bigmod.NewNat().ExpandFor(s.n).Sub(c, s.n)
+	U := s.group.ScalarBaseMult(sc).Add(Y.ScalarMult(negC))
+	V := H.ScalarMult(sc).Add(gamma.ScalarMult(negC))
+
+	expected := s.challenge(Y, H, gamma, U, V)
+	if subtle.ConstantTimeCompare(challenge, expected) != 1 {
+		return nil, errInvalidProof
+	}
+	return s.proofToHash(gamma), nil
+}
+
+// ProofToHash returns the VRF output beta of the proof pi, as
+// ECVRF_proof_to_hash of RFC 9381, section 5.2. It does NOT verify the proof,
+// use [Suite.Verify] unless pi is known to be valid.
+func (s *Suite) ProofToHash(pi []byte) ([]byte, error) {
+	gamma, _, _, err := s.decodeProof(pi)
+	if err != nil {
+		return nil, err
+	}
+	return s.proofToHash(gamma), nil
+}
+
+// decodeProof implements ECVRF_decode_proof of RFC 9381, section 5.4.4.
+func (s *Suite) decodeProof(pi []byte) (gamma *ecgroup.Element, c []byte, sc *bigmod.Nat, err error) {
+	if len(pi) != ProofSize {
+		return nil, nil, nil, errInvalidProof
+	}
+	gamma, err = s.group.DecodeCompressed(pi[:ptLen])
+	if err != nil {
+		return nil, nil, nil, errInvalidProof
+	}
+	sc, err = bigmod.NewNat().SetBytes(pi[ptLen+cLen:], s.n)
+	if err != nil {
+		return nil, nil, nil, errInvalidProof
+	}
+	return gamma, pi[ptLen : ptLen+cLen], sc, nil
+}
+
+func (s *Suite) proofToHash(gamma *ecgroup.Element) []byte {
+	// the cofactor is 1
+	h := s.hash()
+	h.Write([]byte{s.suiteString, 0x03})
+	h.Write(gamma.BytesCompressed())
+	h.Write([]byte{0x00})
+	return h.Sum(nil)
+}
+
+// encodeToCurve implements ECVRF_encode_to_curve_h2c_suite of RFC 9381,
+// section 5.4.1.2, with the public key as encode_to_curve_salt.
+func (s *Suite) encodeToCurve(Y *ecgroup.Element, alpha []byte) (*ecgroup.Element, error) {
+	msg := append(Y.BytesCompressed(), alpha...)
+	p, err := s.h2c.Hash(msg, s.dst)
+	if err != nil {
+		return nil, err
+	}
+	if len(p) == 1 {
+		return nil, errors.New("ecvrf: alpha is encoded to the point at infinity")
+	}
+	return s.group.DecodeUncompressed(p)
+}
+
+// challenge implements ECVRF_challenge_generation of RFC 9381, section 5.4.3.
+func (s *Suite) challenge(points ...*ecgroup.Element) []byte {
+	h := s.hash()
+	h.Write([]byte{s.suiteString, 0x02})
+	for _, p := range points {
+		h.Write(p.BytesCompressed())
+	}
+	h.Write([]byte{0x00})
+	return h.Sum(nil)[:cLen]
+}
+
+// nonce implements ECVRF_nonce_generation_RFC6979 of RFC 9381, section
+// 5.4.2.1. Both the hash and the order of the suites are 256 bits long, so
+// bits2int is a plain conversion of the leftmost qLen bytes.
+func (s *Suite) nonce(x, hString []byte) *bigmod.Nat {
+	h := s.hash()
+	h.Write(hString)
+	h1, _ := bigmod.NewNat().SetOverflowingBytes(h.Sum(nil)[:qLen], s.n)
+	seed := append(append([]byte{}, x...), h1.Bytes(s.n)...) // int2octets(x) || bits2octets(h1)
+
+	size := h.Size()
+	K := make([]byte, size)
+	V := make([]byte, size)
+	for i := range V {
+		V[i] = 0x01
+	}
+	update := func(sep byte, data []byte) {
+		m := hmac.New(s.hash, K)
+		m.Write(V)
+		m.Write([]byte{sep})
+		m.Write(data)
+		K = m.Sum(K[:0])
+		m = hmac.New(s.hash, K)
+		m.Write(V)
+		V = m.Sum(V[:0])
+	}
+	update(0x00, seed)
+	update(0x01, seed)
+	k := bigmod.NewNat()
+	for {
+		m := hmac.New(s.hash, K)
+		m.Write(V)
+		V = m.Sum(V[:0])
+		if _, err := k.SetBytes(V[:qLen], s.n); err == nil && k.IsZero() == 0 {
+			return k
+		}
+		update(0x00, nil)
+	}
+}
+
+// Prove computes the proof pi of alpha with the SM2 private key priv and the
+// suite ECVRF-SM2-SM3-SSWU, see [Suite.Prove].
+func Prove(priv *sm2.PrivateKey, alpha []byte) ([]byte, error) {
+	return suites[SM2SM3SSWU].Prove(&priv.PrivateKey, alpha)
+}
+
+// Verify checks the proof pi of alpha with the SM2 public key pub and the
+// suite ECVRF-SM2-SM3-SSWU, and returns the VRF output beta, see [Suite.Verify].
+func Verify(pub *ecdsa.PublicKey, alpha, pi []byte) ([]byte, error) {
+	return suites[SM2SM3SSWU].Verify(pub, alpha, pi)
+}
+
+// ProofToHash returns the VRF output beta of the proof pi of the suite
+// ECVRF-SM2-SM3-SSWU, see [Suite.ProofToHash].
+func ProofToHash(pi []byte) ([]byte, error) {
+	return suites[SM2SM3SSWU].ProofToHash(pi)
+}
@@ -0,0 +1,203 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ecvrf
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/emmansun/gmsm/sm2"
+	"github.com/emmansun/gmsm/sm2/sm2ec"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func newKey(t *testing.T, curve elliptic.Curve, d []byte) *ecdsa.PrivateKey {
+	t.Helper()
+	priv := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(d)}
+	priv.Curve = curve
+	priv.X, priv.Y = curve.ScalarBaseMult(d)
+	return priv
+}
+
+// The vectors of ECVRF-P256-SHA256-SSWU use the keys of RFC 6979, appendix
+// A.2.5, like the examples of RFC 9381, appendix B.2. ECVRF-SM2-SM3-SSWU has
+// no published vectors, its entries pin the proofs of Prove with the same keys.
+var vectors = []struct {
+	suite string
+	sk    string
+	pk    string
+	alpha string
+	pi    string
+	beta  string
+}{
+	{
+		P256SHA256SSWU,
+		"c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721",
+		"0360fed4ba255a9d31c961eb74c6356d68c049b8923b61fa6ce669622e60f29fb6",
+		"73616d706c65",
+		"0331d984ca8fece9cbb9a144c0d53df3c4c7a33080c1e02ddb1a96a365394c7888782fffde7b842c38c20c08de6ec6c2e7027a97000f2c9fa4425d5c03e639fb48fde58114d755985498d7eb234cf4aed9",
+		"21e66dc9747430f17ed9efeda054cf4a264b097b9e8956a1787526ed00dc664b",
+	},
+	{
+		P256SHA256SSWU,
+		"c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721",
+		"0360fed4ba255a9d31c961eb74c6356d68c049b8923b61fa6ce669622e60f29fb6",
+		"74657374",
+		"03f814c0455d32dbc75ad3aea08c7e2db31748e12802db23640203aebf1fa8db2743aad348a3006dc1caad7da28687320740bf7dd78fe13c298867321ce3b36b79ec3093b7083ac5e4daf3465f9f43c627",
+		"8e7185d2b420e4f4681f44ce313a26d05613323837da09a69f00491a83ad25dd",
+	},
+	{
+		P256SHA256SSWU,
+		"2ca1411a41b17b24cc8c3b089cfd033f1920202a6c0de8abb97df1498d50d2c8",
+		"03596375e6ce57e0f20294fc46bdfcfd19a39f8161b58695b3ec5b3d16427c274d",
+		"",
+		"02f7f1222557cc9cb5979d1b357fea54f797f7463444e05ba7a76e8aeb56d66d3525e653aa5f24578970d71e53e7a9adcd6b559d68387a616a71e98f7e284655b356c907140bde5abe99f81b870dfcd056",
+		"e2458cba9b336bee7d2d6b0cee40f82521fea363dd1ab75bfcc5a187c7fba0cd",
+	},
+	{
+		SM2SM3SSWU,
+		"c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721",
+		"0357744271cea7d618858286b96b2a77ca6162559b00e7f1d5bd88226b45b6d076",
+		"73616d706c65",
+		"036a203d2871dc3ff1ba0c0140d6555d45b649b4eefd94ae4d3179db1ea5316ac3d4800bb847f90ac624904f73773ecd0c3482c12d5fedf8aa46427139c5eb80dff88b8284c020ede3f92e8ccebbf7158d",
+		"f437f50ed4a2c22099b6847975d2a12b1ce50f1d4a3f9474636f3f453a5f0c7a",
+	},
+	{
+		SM2SM3SSWU,
+		"c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721",
+		"0357744271cea7d618858286b96b2a77ca6162559b00e7f1d5bd88226b45b6d076",
+		"74657374",
+		"024273042478eca0e96856e8914c5d0670640263b149f4c7c2db2ebe9946d09534bc4ae235eba99fce973fa7f9108cea3a6b866846095611da3395b3d8a02f7a6c4d135868e19ab7805004a894416706fa",
+		"e92054b21661a054d77983c5dfcefb17a740c70c3471ac41239d973b0392dd32",
+	},
+	{
+		SM2SM3SSWU,
+		"2ca1411a41b17b24cc8c3b089cfd033f1920202a6c0de8abb97df1498d50d2c8",
+		"029097ba1fdc1e702261ab114dd9f5a346ad603d2a691f3b3f73c0f305e0e1fe49",
+		"",
+		"02e3f4d14ab79b46356a350e87f90216dde187bba353ddccdae9de7e6d3ea87e64401553283a77fbd3d06a6697632882865c9d3f3a5014850e342380a2845b4a1861de82abf47ff9ad08a2e58a0343a6a6",
+		"0fedf0bb4f438122bd50abdab4daa8a221683b3164f2822c0cbe8096fb428435",
+	},
+}
+
+func TestVectors(t *testing.T) {
+	for i, v := range vectors {
+		s, err := NewSuite(v.suite)
+		if err != nil {
+			t.Fatal(err)
+		}
+		priv := newKey(t, s.curve, decodeHex(t, v.sk))
+		if pk := elliptic.MarshalCompressed(priv.Curve, priv.X, priv.Y); !bytes.Equal(pk, decodeHex(t, v.pk)) {
+			t.Errorf("case %d: public key = %x, want %s", i, pk, v.pk)
+		}
+		alpha := decodeHex(t, v.alpha)
+		pi, err := s.Prove(priv, alpha)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(pi) != v.pi {
+			t.Errorf("case %d: pi = %x, want %s", i, pi, v.pi)
+		}
+		beta, err := s.Verify(&priv.PublicKey, alpha, pi)
+		if err != nil {
+			t.Fatalf("case %d: %v", i, err)
+		}
+		if hex.EncodeToString(beta) != v.beta {
+			t.Errorf("case %d: beta = %x, want %s", i, beta, v.beta)
+		}
+		if beta, err = s.ProofToHash(pi); err != nil || hex.EncodeToString(beta) != v.beta {
+			t.Errorf("case %d: ProofToHash mismatch", i)
+		}
+	}
+}
+
+func TestSM2Keys(t *testing.T) {
+	priv, err := sm2.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alpha := []byte("round 42")
+	pi, err := Prove(priv, alpha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	beta, err := Verify(&priv.PublicKey, alpha, pi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if beta2, _ := ProofToHash(pi); !bytes.Equal(beta, beta2) {
+		t.Fatal("ProofToHash mismatch")
+	}
+	if _, err := Verify(&priv.PublicKey, []byte("round 43"), pi); err == nil {
+		t.Fatal("expected error for another alpha")
+	}
+	other, _ := sm2.GenerateKey(rand.Reader)
+	if _, err := Verify(&other.PublicKey, alpha, pi); err == nil {
+		t.Fatal("expected error for another key")
+	}
+	for _, i := range []int{0, ptLen, ptLen + cLen, ProofSize - 1} {
+		bad := bytes.Clone(pi)
+		bad[i] ^= 1
+		if _, err := Verify(&priv.PublicKey, alpha, bad); err == nil {
+			t.Fatalf("expected error for proof modified at %d", i)
+		}
+	}
+	if _, err := Verify(&priv.PublicKey, alpha, pi[:ProofSize-1]); err == nil {
+		t.Fatal("expected error for short proof")
+	}
+	bad := bytes.Clone(pi)
+	sm2ec.P256().Params().N.FillBytes(bad[ptLen+cLen:])
+	if _, err := ProofToHash(bad); err == nil {
+		t.Fatal("expected error for s >= N")
+	}
+	p256, _ := NewSuite(P256SHA256SSWU)
+	if _, err := p256.Prove(&priv.PrivateKey, alpha); err == nil {
+		t.Fatal("expected error for SM2 key with the P-256 suite")
+	}
+	if _, err := p256.Verify(&priv.PublicKey, alpha, pi); err == nil {
+		t.Fatal("expected error for SM2 key with the P-256 suite")
+	}
+	if _, err := NewSuite("ECVRF-EDWARDS25519-SHA512-ELL2"); err == nil {
+		t.Fatal("expected error for unsupported suite")
+	}
+}
+
+func BenchmarkProve(b *testing.B) {
+	priv, _ := sm2.GenerateKey(rand.Reader)
+	alpha := []byte("round 42")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Prove(priv, alpha); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	priv, _ := sm2.GenerateKey(rand.Reader)
+	alpha := []byte("round 42")
+	pi, _ := Prove(priv, alpha)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Verify(&priv.PublicKey, alpha, pi); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package h2c
+
+import (
+	"encoding/hex"
+	"math/big"
+
+	"github.com/emmansun/gmsm/internal/bigmod"
+)
+
+// curve is a prime order short Weierstrass curve y² = x³ - 3x + b over GF(p)
+// with p ≡ 3 mod 4, which covers both the SM2 curve and NIST P-256. All the
+// field arithmetic is done with bigmod, in constant time.
+type curve struct {
+	p, n *bigmod.Modulus
+	a, b *bigmod.Nat
+	z    *bigmod.Nat // the non-square Z of the simplified SWU map
+
+	c1      []byte      // (p - 3) / 4
+	c2      *bigmod.Nat // sqrt(-Z)
+	pMinus2 []byte
+	size    int
+}
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func newCurve(pHex, nHex, bHex string, z int64) *curve {
+	pBytes := mustDecodeHex(pHex)
+	p, err := bigmod.NewModulus(pBytes)
+	if err != nil {
+		panic(err)
+	}
+	n, err := bigmod.NewModulus(mustDecodeHex(nHex))
+	if err != nil {
+		panic(err)
+	}
+	c := &curve{p: p, n: n, size: len(pBytes)}
+	c.b, err = bigmod.NewNat().SetBytes(mustDecodeHex(bHex), p)
+	if err != nil {
+		panic(err)
+	}
+	c.a = bigmod.NewNat().ExpandFor(p).Sub(bigmod.NewNat().SetUint(3, p), p)
+
+	// the constants only depend on the public curve parameters
+	pInt := new(big.Int).SetBytes(pBytes)
+	zInt := new(big.Int).Mod(big.NewInt(z), pInt)
+	c.z, _ = bigmod.NewNat().SetBytes(zInt.Bytes(), p)
+	c1 := new(big.Int).Rsh(pInt, 2) // (p - 3) / 4 as p ≡ 3 mod 4
+	c.c1 = c1.Bytes()
+	c2 := new(big.Int).Neg(zInt)
+	c2.Mod(c2, pInt).Exp(c2, c1.Add(c1, big.NewInt(1)), pInt) // (p + 1) / 4
+	c.c2, _ = bigmod.NewNat().SetBytes(c2.Bytes(), p)
+	c.pMinus2 = new(big.Int).Sub(pInt, big.NewInt(2)).Bytes()
+	return c
+}
+
+func (c *curve) element() *bigmod.Nat {
+	return bigmod.NewNat().ExpandFor(c.p)
+}
+
+func (c *curve) mul(x, y *bigmod.Nat) *bigmod.Nat {
+	return bigmod.NewNat().Set(x).Mul(y, c.p)
+}
+
+func (c *curve) add(x, y *bigmod.Nat) *bigmod.Nat {
+	return bigmod.NewNat().Set(x).Add(y, c.p)
+}
+
+func (c *curve) sub(x, y *bigmod.Nat) *bigmod.Nat {
+	return bigmod.NewNat().Set(x).Sub(y, c.p)
+}
+
+func (c *curve) neg(x *bigmod.Nat) *bigmod.Nat {
+	return c.element().Sub(x, c.p)
+}
+
+// inv0 returns the inverse of x, or zero if x is zero.
+func (c *curve) inv0(x *bigmod.Nat) *bigmod.Nat {
+	return bigmod.NewNat().Exp(x, c.pMinus2, c.p)
+}
+
+// sqrtRatio implements sqrt_ratio for q ≡ 3 mod 4 of RFC 9380, appendix
+// F.2.1.2. It returns whether u/v is square, and sqrt(u/v) if it is or
+// sqrt(Z * u/v) otherwise.
+func (c *curve) sqrtRatio(u, v *bigmod.Nat) (int, *bigmod.Nat) {
+	tv1 := c.mul(v, v)                            // 1. tv1 = v^2
+	tv2 := c.mul(u, v)                            // 2. tv2 = u * v
+	tv1.Mul(tv2, c.p)                             // 3. tv1 = tv1 * tv2
+	y1 := bigmod.NewNat().Exp(tv1, c.c1, c.p)     // 4. y1 = tv1^c1
+	y1.Mul(tv2, c.p)                              // 5. y1 = y1 * tv2
+	y2 := c.mul(y1, c.c2)                         // 6. y2 = y1 * c2
+	tv3 := c.mul(y1, y1)                          // 7. tv3 = y1^2
+	tv3.Mul(v, c.p)                               // 8. tv3 = tv3 * v
+	isQR := int(tv3.Equal(u))                     // 9. isQR = tv3 == u
+	y := bigmod.NewNat().Set(y2).Select(isQR, y1) // 10. y = CMOV(y2, y1, isQR)
+	return isQR, y
+}
+
+// mapToCurve implements the simplified SWU map of RFC 9380, section 6.6.2,
+// with the straight-line procedure of appendix F.2. It returns the affine
+// coordinates of the point.
+func (c *curve) mapToCurve(u *bigmod.Nat) (x, y *bigmod.Nat) {
+	tv1 := c.mul(u, u)                                 // 1. tv1 = u^2
+	tv1.Mul(c.z, c.p)                                  // 2. tv1 = Z * tv1
+	tv2 := c.mul(tv1, tv1)                             // 3. tv2 = tv1^2
+	tv2.Add(tv1, c.p)                                  // 4. tv2 = tv2 + tv1
+	tv3 := c.add(tv2, bigmod.NewNat().SetUint(1, c.p)) // 5. tv3 = tv2 + 1
+	tv3.Mul(c.b, c.p)                                  // 6. tv3 = B * tv3
+	tv4 := bigmod.NewNat().Set(c.z)                    // 7. tv4 = CMOV(Z, -tv2, tv2 != 0)
+	tv4.Select(1^int(tv2.IsZero()), c.neg(tv2))        //
+	tv4.Mul(c.a, c.p)                                  // 8. tv4 = A * tv4
+	tv2 = c.mul(tv3, tv3)                              // 9. tv2 = tv3^2
+	tv6 := c.mul(tv4, tv4)                             // 10. tv6 = tv4^2
+	tv5 := c.mul(c.a, tv6)                             // 11. tv5 = A * tv6
+	tv2.Add(tv5, c.p)                                  // 12. tv2 = tv2 + tv5
+	tv2.Mul(tv3, c.p)                                  // 13. tv2 = tv2 * tv3
+	tv6.Mul(tv4, c.p)                                  // 14. tv6 = tv6 * tv4
+	tv5 = c.mul(c.b, tv6)                              // 15. tv5 = B * tv6
+	tv2.Add(tv5, c.p)                                  // 16. tv2 = tv2 + tv5
+	x = c.mul(tv1, tv3)                                // 17. x = tv1 * tv3
+	isGx1Square, y1 := c.sqrtRatio(tv2, tv6)           // 18. (is_gx1_square, y1) = sqrt_ratio(tv2, tv6)
+	y = c.mul(tv1, u)                                  // 19. y = tv1 * u
+	y.Mul(y1, c.p)                                     // 20. y = y * y1
+	x.Select(isGx1Square, tv3)                         // 21. x = CMOV(x, tv3, is_gx1_square)
+	y.Select(isGx1Square, y1)                          // 22. y = CMOV(y, y1, is_gx1_square)
+	e1 := 1 ^ int(u.IsOdd()^y.IsOdd())                 // 23. e1 = sgn0(u) == sgn0(y)
+	y = c.neg(y).Select(e1, y)                         // 24. y = CMOV(-y, y, e1)
+	x.Mul(c.inv0(tv4), c.p)                            // 25-26. x = x / tv4
+	return x, y
+}
+
+// point is a point of the curve in projective coordinates (X:Y:Z), the point
+// at infinity is (0:1:0).
+type point struct {
+	x, y, z *bigmod.Nat
+}
+
+func (c *curve) newAffinePoint(x, y *bigmod.Nat) *point {
+	return &point{x: x, y: y, z: bigmod.NewNat().SetUint(1, c.p)}
+}
+
+// addPoints returns p1 + p2, with the complete addition formula for a = -3 from
+// "Complete addition formulas for prime order elliptic curves"
+// (https://eprint.iacr.org/2015/1060), algorithm 4.
+func (c *curve) addPoints(p1, p2 *point) *point {
+	t0 := c.mul(p1.x, p2.x) // t0 := X1 * X2
+	t1 := c.mul(p1.y, p2.y) // t1 := Y1 * Y2
+	t2 := c.mul(p1.z, p2.z) // t2 := Z1 * Z2
+	t3 := c.add(p1.x, p1.y) // t3 := X1 + Y1
+	t4 := c.add(p2.x, p2.y) // t4 := X2 + Y2
+	t3.Mul(t4, c.p)         // t3 := t3 * t4
+	t4 = c.add(t0, t1)      // t4 := t0 + t1
+	t3.Sub(t4, c.p)         // t3 := t3 - t4
+	t4 = c.add(p1.y, p1.z)  // t4 := Y1 + Z1
+	x3 := c.add(p2.y, p2.z) // X3 := Y2 + Z2
+	t4.Mul(x3, c.p)         // t4 := t4 * X3
+	x3 = c.add(t1, t2)      // X3 := t1 + t2
+	t4.Sub(x3, c.p)         // t4 := t4 - X3
+	x3 = c.add(p1.x, p1.z)  // X3 := X1 + Z1
+	y3 := c.add(p2.x, p2.z) // Y3 := X2 + Z2
+	x3.Mul(y3, c.p)         // X3 := X3 * Y3
+	y3 = c.add(t0, t2)      // Y3 := t0 + t2
+	y3 = c.sub(x3, y3)      // Y3 := X3 - Y3
+	z3 := c.mul(c.b, t2)    // Z3 := b * t2
+	x3 = c.sub(y3, z3)      // X3 := Y3 - Z3
+	z3 = c.add(x3, x3)      // Z3 := X3 + X3
+	x3.Add(z3, c.p)         // X3 := X3 + Z3
+	z3 = c.sub(t1, x3)      // Z3 := t1 - X3
+	x3.Add(t1, c.p)         // X3 := t1 + X3
+	y3.Mul(c.b, c.p)        // Y3 := b * Y3
+	t1 = c.add(t2, t2)      // t1 := t2 + t2
+	t2.Add(t1, c.p)         // t2 := t1 + t2
+	y3.Sub(t2, c.p)         // Y3 := Y3 - t2
+	y3.Sub(t0, c.p)         // Y3 := Y3 - t0
+	t1 = c.add(y3, y3)      // t1 := Y3 + Y3
+	y3.Add(t1, c.p)         // Y3 := t1 + Y3
+	t1 = c.add(t0, t0)      // t1 := t0 + t0
+	t0.Add(t1, c.p)         // t0 := t1 + t0
+	t0.Sub(t2, c.p)         // t0 := t0 - t2
+	t1 = c.mul(t4, y3)      // t1 := t4 * Y3
+	t2 = c.mul(t0, y3)      // t2 := t0 * Y3
+	y3 = c.mul(x3, z3)      // Y3 := X3 * Z3
+	y3.Add(t2, c.p)         // Y3 := Y3 + t2
+	x3 = c.mul(t3, x3)      // X3 := t3 * X3
+	x3.Sub(t1, c.p)         // X3 := X3 - t1
+	z3 = c.mul(t4, z3)      // Z3 := t4 * Z3
+	t1 = c.mul(t3, t0)      // t1 := t3 * t0
+	z3.Add(t1, c.p)         // Z3 := Z3 + t1
+	return &point{x: x3, y: y3, z: z3}
+}
+
+// bytes returns the uncompressed SEC 1 encoding of q, or the single byte 0x00
+// for the point at infinity.
+func (c *curve) bytes(q *point) []byte {
+	if q.z.IsZero() == 1 {
+		return []byte{0}
+	}
+	zInv := c.inv0(q.z)
+	out := make([]byte, 1, 1+2*c.size)
+	out[0] = 4
+	out = append(out, c.mul(q.x, zInv).Bytes(c.p)...)
+	out = append(out, c.mul(q.y, zInv).Bytes(c.p)...)
+	return out
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package h2c
+
+import (
+	"errors"
+	"hash"
+)
+
+const maxDSTLength = 255
+
+// ExpandMessageXMD implements expand_message_xmd of RFC 9380, section 5.3.1,
+// which expands msg to lenInBytes uniformly random bytes with the
+// Merkle-Damgård hash function h and the domain separation tag dst.
+//
+// A dst longer than 255 bytes is hashed as described in section 5.3.3.
+func ExpandMessageXMD(h func() hash.Hash, msg, dst []byte, lenInBytes int) ([]byte, error) {
+	if len(dst) == 0 {
+		return nil, errors.New("h2c: empty domain separation tag")
+	}
+	md := h()
+	bInBytes, sInBytes := md.Size(), md.BlockSize()
+	ell := (lenInBytes + bInBytes - 1) / bInBytes
+	if lenInBytes <= 0 || lenInBytes > 65535 || ell > 255 {
+		return nil, errors.New("h2c: invalid requested length")
+	}
+	if len(dst) > maxDSTLength {
+		md.Write([]byte("H2C-OVERSIZE-DST-"))
+		md.Write(dst)
+		dst = md.Sum(nil)
+		md.Reset()
+	}
+	dstPrime := append(dst[:len(dst):len(dst)], byte(len(dst)))
+
+	// b_0 = H(Z_pad || msg || l_i_b_str || I2OSP(0, 1) || DST_prime)
+	md.Write(make([]byte, sInBytes))
+	md.Write(msg)
+	md.Write([]byte{byte(lenInBytes >> 8), byte(lenInBytes), 0})
+	md.Write(dstPrime)
+	b0 := md.Sum(nil)
+
+	// b_1 = H(b_0 || I2OSP(1, 1) || DST_prime)
+	md.Reset()
+	md.Write(b0)
+	md.Write([]byte{1})
+	md.Write(dstPrime)
+	bi := md.Sum(nil)
+
+	out := make([]byte, 0, ell*bInBytes)
+	out = append(out, bi...)
+	tmp := make([]byte, bInBytes)
+	for i := 2; i <= ell; i++ {
+		// b_i = H(strxor(b_0, b_(i - 1)) || I2OSP(i, 1) || DST_prime)
+		for j := range tmp {
+			tmp[j] = b0[j] ^ bi[j]
+		}
+		md.Reset()
+		md.Write(tmp)
+		md.Write([]byte{byte(i)})
+		md.Write(dstPrime)
+		bi = md.Sum(bi[:0])
+		out = append(out, bi...)
+	}
+	return out[:lenInBytes], nil
+}
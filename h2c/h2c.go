@@ -0,0 +1,165 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package h2c implements hashing to elliptic curves as specified in RFC 9380
+// for the SM2 curve with SM3, and for NIST P-256 with SHA-256.
+//
+// The SM2 suites follow the naming convention of RFC 9380, section 8.10: they
+// use expand_message_xmd with SM3, the simplified SWU map with Z = -9 (found
+// with find_z_sswu of appendix H.2) and the same parameters as the P-256
+// suites otherwise (L = 48, h_eff = 1, k = 128).
+//
+// The field arithmetic is constant time, the running time does not depend on
+// the message.
+package h2c
+
+import (
+	"crypto/sha256"
+	"errors"
+	"hash"
+
+	"github.com/emmansun/gmsm/internal/bigmod"
+	"github.com/emmansun/gmsm/sm3"
+)
+
+// The identifiers of the supported hash-to-curve suites.
+const (
+	SM2P256RO = "SM2P256_XMD:SM3_SSWU_RO_"  // hash_to_curve for the SM2 curve
+	SM2P256NU = "SM2P256_XMD:SM3_SSWU_NU_"  // encode_to_curve for the SM2 curve
+	P256RO    = "P256_XMD:SHA-256_SSWU_RO_" // hash_to_curve for NIST P-256
+	P256NU    = "P256_XMD:SHA-256_SSWU_NU_" // encode_to_curve for NIST P-256
+)
+
+// l is the length in bytes of the uniform bytes reduced to one field element
+// or one scalar, ceil((ceil(log2(p)) + k) / 8) with k = 128.
+const l = 48
+
+var (
+	sm2p256 = newCurve(
+		"fffffffeffffffffffffffffffffffffffffffff00000000ffffffffffffffff",
+		"fffffffeffffffffffffffffffffffff7203df6b21c6052b53bbf40939d54123",
+		"28e9fa9e9d9f5e344d5a9e4bcf6509a7f39789f515ab8f92ddbcbd414d940e93",
+		-9)
+	p256 = newCurve(
+		"ffffffff00000001000000000000000000000000ffffffffffffffffffffffff",
+		"ffffffff00000000ffffffffffffffffbce6faada7179e84f3b9cac2fc632551",
+		"5ac635d8aa3a93e7b3ebbd55769886bc651d06b0cc53b0f63bce3c3e27d2604b",
+		-10)
+)
+
+// Suite is a hash-to-curve suite.
+type Suite struct {
+	id    string
+	curve *curve
+	hash  func() hash.Hash
+	ro    bool
+}
+
+var suites = map[string]*Suite{
+	SM2P256RO: {id: SM2P256RO, curve: sm2p256, hash: sm3.New, ro: true},
+	SM2P256NU: {id: SM2P256NU, curve: sm2p256, hash: sm3.New},
+	P256RO:    {id: P256RO, curve: p256, hash: sha256.New, ro: true},
+	P256NU:    {id: P256NU, curve: p256, hash: sha256.New},
+}
+
+// NewSuite returns the hash-to-curve suite of the identifier id.
+func NewSuite(id string) (*Suite, error) {
+	s, ok := suites[id]
+	if !ok {
+		return nil, errors.New("h2c: unsupported suite " + id)
+	}
+	return s, nil
+}
+
+// ID returns the identifier of the suite.
+func (s *Suite) ID() string {
+	return s.id
+}
+
+// IsRandomOracle reports whether the suite is a random oracle encoding
+// (hash_to_curve) rather than a nonuniform encoding (encode_to_curve).
+func (s *Suite) IsRandomOracle() bool {
+	return s.ro
+}
+
+// HashToField implements hash_to_field of RFC 9380, section 5.2. It returns
+// count field elements, each encoded as a big-endian byte slice.
+func (s *Suite) HashToField(msg, dst []byte, count int) ([][]byte, error) {
+	u, err := s.hashToField(msg, dst, s.curve.p, count)
+	if err != nil {
+		return nil, err
+	}
+	out := make([][]byte, count)
+	for i := range u {
+		out[i] = u[i].Bytes(s.curve.p)
+	}
+	return out, nil
+}
+
+func (s *Suite) hashToField(msg, dst []byte, m *bigmod.Modulus, count int) ([]*bigmod.Nat, error) {
+	uniformBytes, err := ExpandMessageXMD(s.hash, msg, dst, count*l)
+	if err != nil {
+		return nil, err
+	}
+	u := make([]*bigmod.Nat, count)
+	for i := range u {
+		u[i] = bigmod.NewNat().SetReducedBytes(uniformBytes[i*l:(i+1)*l], m)
+	}
+	return u, nil
+}
+
+// Hash hashes msg with the domain separation tag dst to a point of the curve,
+// which is hash_to_curve for the random oracle suites and encode_to_curve for
+// the nonuniform suites. The point is returned in the uncompressed form of
+// SEC 1, Version 2.0, Section 2.3.3, or as the single byte 0x00 if it is the
+// point at infinity, which happens with negligible probability.
+func (s *Suite) Hash(msg, dst []byte) ([]byte, error) {
+	c := s.curve
+	if !s.ro {
+		u, err := s.hashToField(msg, dst, c.p, 1)
+		if err != nil {
+			return nil, err
+		}
+		return c.bytes(c.newAffinePoint(c.mapToCurve(u[0]))), nil
+	}
+	u, err := s.hashToField(msg, dst, c.p, 2)
+	if err != nil {
+		return nil, err
+	}
+	q0 := c.newAffinePoint(c.mapToCurve(u[0]))
+	q1 := c.newAffinePoint(c.mapToCurve(u[1]))
+	// the cofactor of both curves is 1, clear_cofactor is the identity.
+	return c.bytes(c.addPoints(q0, q1)), nil
+}
+
+// HashToScalar hashes msg with the domain separation tag dst to a scalar
+// modulo the order of the curve. It is hash_to_field of RFC 9380 with the
+// modulus replaced by the order, as the HashToScalar of RFC 9497. The scalar
+// is returned as a big-endian byte slice of the size of the order.
+func (s *Suite) HashToScalar(msg, dst []byte) ([]byte, error) {
+	k, err := s.hashToField(msg, dst, s.curve.n, 1)
+	if err != nil {
+		return nil, err
+	}
+	return k[0].Bytes(s.curve.n), nil
+}
+
+// HashToCurve hashes msg to a point of the SM2 curve with the suite
+// SM2P256_XMD:SM3_SSWU_RO_, see [Suite.Hash].
+func HashToCurve(msg, dst []byte) ([]byte, error) {
+	return suites[SM2P256RO].Hash(msg, dst)
+}
+
+// EncodeToCurve encodes msg to a point of the SM2 curve with the suite
+// SM2P256_XMD:SM3_SSWU_NU_, see [Suite.Hash]. Its output is not uniformly
+// distributed, use [HashToCurve] unless the protocol requires encode_to_curve.
+func EncodeToCurve(msg, dst []byte) ([]byte, error) {
+	return suites[SM2P256NU].Hash(msg, dst)
+}
+
+// HashToScalar hashes msg to a scalar modulo the order of the SM2 curve with
+// expand_message_xmd and SM3, see [Suite.HashToScalar].
+func HashToScalar(msg, dst []byte) ([]byte, error) {
+	return suites[SM2P256RO].HashToScalar(msg, dst)
+}
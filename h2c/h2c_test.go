@@ -0,0 +1,190 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package h2c_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/emmansun/gmsm/h2c"
+	"github.com/emmansun/gmsm/sm2/sm2ec"
+	"github.com/emmansun/gmsm/sm3"
+)
+
+var (
+	q128 = "q128_" + strings.Repeat("q", 128)
+	a512 = "a512_" + strings.Repeat("a", 512)
+)
+
+func decodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+var expandMessageXMDTests = []struct {
+	name       string
+	dst        string
+	msg        string
+	lenInBytes int
+	want       string
+}{
+	{"SHA-256", "QUUX-V01-CS02-with-expander-SHA256-128", "", 32, "68a985b87eb6b46952128911f2a4412bbc302a9d759667f87f7a21d803f07235"},
+	{"SHA-256", "QUUX-V01-CS02-with-expander-SHA256-128", "abc", 32, "d8ccab23b5985ccea865c6c97b6e5b8350e794e603b4b97902f53a8a0d605615"},
+	{"SHA-256", "QUUX-V01-CS02-with-expander-SHA256-128", "abcdef0123456789", 32, "eff31487c770a893cfb36f912fbfcbff40d5661771ca4b2cb4eafe524333f5c1"},
+	{"SHA-256", "QUUX-V01-CS02-with-expander-SHA256-128", q128, 32, "b23a1d2b4d97b2ef7785562a7e8bac7eed54ed6e97e29aa51bfe3f12ddad1ff9"},
+	{"SHA-256", "QUUX-V01-CS02-with-expander-SHA256-128", a512, 32, "4623227bcc01293b8c130bf771da8c298dede7383243dc0993d2d94823958c4c"},
+	{"SHA-256", "QUUX-V01-CS02-with-expander-SHA256-128", "", 128, "af84c27ccfd45d41914fdff5df25293e221afc53d8ad2ac06d5e3e29485dadbee0d121587713a3e0dd4d5e69e93eb7cd4f5df4cd103e188cf60cb02edc3edf18eda8576c412b18ffb658e3dd6ec849469b979d444cf7b26911a08e63cf31f9dcc541708d3491184472c2c29bb749d4286b004ceb5ee6b9a7fa5b646c993f0ced"},
+	{"SHA-256", "QUUX-V01-CS02-with-expander-SHA256-128", "abc", 128, "abba86a6129e366fc877aab32fc4ffc70120d8996c88aee2fe4b32d6c7b6437a647e6c3163d40b76a73cf6a5674ef1d890f95b664ee0afa5359a5c4e07985635bbecbac65d747d3d2da7ec2b8221b17b0ca9dc8a1ac1c07ea6a1e60583e2cb00058e77b7b72a298425cd1b941ad4ec65e8afc50303a22c0f99b0509b4c895f40"},
+	{"SHA-256", "QUUX-V01-CS02-with-expander-SHA256-128-long-DST-1111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111", "", 32, "e8dc0c8b686b7ef2074086fbdd2f30e3f8bfbd3bdf177f73f04b97ce618a3ed3"},
+	{"SHA-256", "QUUX-V01-CS02-with-expander-SHA256-128-long-DST-1111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111", "abc", 32, "52dbf4f36cf560fca57dedec2ad924ee9c266341d8f3d6afe5171733b16bbb12"},
+	{"SHA-256", "QUUX-V01-CS02-with-expander-SHA256-128-long-DST-1111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111", "abcdef0123456789", 32, "35387dcf22618f3728e6c686490f8b431f76550b0b2c61cbc1ce7001536f4521"},
+	{"SHA-256", "QUUX-V01-CS02-with-expander-SHA256-128-long-DST-1111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111", q128, 32, "01b637612bb18e840028be900a833a74414140dde0c4754c198532c3a0ba42bc"},
+	{"SHA-256", "QUUX-V01-CS02-with-expander-SHA256-128-long-DST-1111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111", a512, 32, "20cce7033cabc5460743180be6fa8aac5a103f56d481cf369a8accc0c374431b"},
+	{"SHA-256", "QUUX-V01-CS02-with-expander-SHA256-128-long-DST-1111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111", "", 128, "14604d85432c68b757e485c8894db3117992fc57e0e136f71ad987f789a0abc287c47876978e2388a02af86b1e8d1342e5ce4f7aaa07a87321e691f6fba7e0072eecc1218aebb89fb14a0662322d5edbd873f0eb35260145cd4e64f748c5dfe60567e126604bcab1a3ee2dc0778102ae8a5cfd1429ebc0fa6bf1a53c36f55dfc"},
+	{"SHA-256", "QUUX-V01-CS02-with-expander-SHA256-128-long-DST-1111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111111", "abc", 128, "1a30a5e36fbdb87077552b9d18b9f0aee16e80181d5b951d0471d55b66684914aef87dbb3626eaabf5ded8cd0686567e503853e5c84c259ba0efc37f71c839da2129fe81afdaec7fbdc0ccd4c794727a17c0d20ff0ea55e1389d6982d1241cb8d165762dbc39fb0cee4474d2cbbd468a835ae5b2f20e4f959f56ab24cd6fe267"},
+	{"SM3", "QUUX-V01-CS02-with-expander-SM3-256", "", 32, "97dfdf81ba6031a0d6224946ade18bcc28dd0fa1ab37863e2fd8491b579d930a"},
+	{"SM3", "QUUX-V01-CS02-with-expander-SM3-256", "abc", 32, "ef47155b8fdf8d40236929d10a204a644ff40e4d77bb1f9cc0d432b051978b80"},
+	{"SM3", "QUUX-V01-CS02-with-expander-SM3-256", "abcdef0123456789", 32, "0272d520cabdbddf86dc243573a99aeafb2e069fb707cdb03bee5e5cad5bf507"},
+	{"SM3", "QUUX-V01-CS02-with-expander-SM3-256", q128, 32, "83c0c3205628edf3153bdcd28c9577bbfa5e1e1d1288324299887ea4d060dd51"},
+	{"SM3", "QUUX-V01-CS02-with-expander-SM3-256", a512, 32, "0e9d3e35a329e7488949877ee05354245a0dc700b76d118443a593188ed50fe1"},
+	{"SM3", "QUUX-V01-CS02-with-expander-SM3-256", "", 128, "72cdfaa386428bff41bd99ef4c9bd1749b7b40d9be1f6d0e7c47bb931dc0c6b0c9cb16f4cba1841072785e10dfeaa4f9bcdbb32ea92b8da27e7080c2e833e1a7c62e9a594ac520d6e73a793f477a8352c214f89dc402a1db3bd00db79d7b5755b0300a910e90642c21fa820d5d75555df15c5966ee3eeb649b606d42b01750b5"},
+	{"SM3", "QUUX-V01-CS02-with-expander-SM3-256", "abc", 128, "1f957ca57ba2803c2605bde2b8fc6fb7dea55540e1df308b6b0b28e6dab9b12f3f911d1c9472150d6abee181ae3a3ac5278368aa857a127df4b1c7676f85f012ef99fee97f91e13f8d914f960d22ad859138e63274b799d83d5c66b625c3de8d9e72a1bafce8f6323c7bad0b9a902dbcc92a92f423e4aa7e02be5977a3e5629f"},
+}
+
+func TestExpandMessageXMD(t *testing.T) {
+	for i, tt := range expandMessageXMDTests {
+		h := sm3.New
+		if tt.name == "SHA-256" {
+			h = sha256.New
+		}
+		got, err := h2c.ExpandMessageXMD(h, []byte(tt.msg), []byte(tt.dst), tt.lenInBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != tt.want {
+			t.Errorf("case %d %s: got %x, want %s", i, tt.name, got, tt.want)
+		}
+	}
+	if _, err := h2c.ExpandMessageXMD(sm3.New, nil, nil, 32); err == nil {
+		t.Error("expected error for empty dst")
+	}
+	if _, err := h2c.ExpandMessageXMD(sm3.New, nil, []byte("dst"), 256*32); err == nil {
+		t.Error("expected error for too long output")
+	}
+}
+
+var hashToCurveTests = []struct {
+	suite string
+	dst   string
+	msg   string
+	x, y  string
+}{
+	{h2c.P256RO, "QUUX-V01-CS02-with-P256_XMD:SHA-256_SSWU_RO_", "", "2c15230b26dbc6fc9a37051158c95b79656e17a1a920b11394ca91c44247d3e4", "8a7a74985cc5c776cdfe4b1f19884970453912e9d31528c060be9ab5c43e8415"},
+	{h2c.P256RO, "QUUX-V01-CS02-with-P256_XMD:SHA-256_SSWU_RO_", "abc", "0bb8b87485551aa43ed54f009230450b492fead5f1cc91658775dac4a3388a0f", "5c41b3d0731a27a7b14bc0bf0ccded2d8751f83493404c84a88e71ffd424212e"},
+	{h2c.P256RO, "QUUX-V01-CS02-with-P256_XMD:SHA-256_SSWU_RO_", "abcdef0123456789", "65038ac8f2b1def042a5df0b33b1f4eca6bff7cb0f9c6c1526811864e544ed80", "cad44d40a656e7aff4002a8de287abc8ae0482b5ae825822bb870d6df9b56ca3"},
+	{h2c.P256RO, "QUUX-V01-CS02-with-P256_XMD:SHA-256_SSWU_RO_", q128, "4be61ee205094282ba8a2042bcb48d88dfbb609301c49aa8b078533dc65a0b5d", "98f8df449a072c4721d241a3b1236d3caccba603f916ca680f4539d2bfb3c29e"},
+	{h2c.P256RO, "QUUX-V01-CS02-with-P256_XMD:SHA-256_SSWU_RO_", a512, "457ae2981f70ca85d8e24c308b14db22f3e3862c5ea0f652ca38b5e49cd64bc5", "ecb9f0eadc9aeed232dabc53235368c1394c78de05dd96893eefa62b0f4757dc"},
+	{h2c.P256NU, "QUUX-V01-CS02-with-P256_XMD:SHA-256_SSWU_NU_", "", "f871caad25ea3b59c16cf87c1894902f7e7b2c822c3d3f73596c5ace8ddd14d1", "87b9ae23335bee057b99bac1e68588b18b5691af476234b8971bc4f011ddc99b"},
+	{h2c.P256NU, "QUUX-V01-CS02-with-P256_XMD:SHA-256_SSWU_NU_", "abc", "fc3f5d734e8dce41ddac49f47dd2b8a57257522a865c124ed02b92b5237befa4", "fe4d197ecf5a62645b9690599e1d80e82c500b22ac705a0b421fac7b47157866"},
+	{h2c.P256NU, "QUUX-V01-CS02-with-P256_XMD:SHA-256_SSWU_NU_", "abcdef0123456789", "f164c6674a02207e414c257ce759d35eddc7f55be6d7f415e2cc177e5d8faa84", "3aa274881d30db70485368c0467e97da0e73c18c1d00f34775d012b6fcee7f97"},
+	{h2c.P256NU, "QUUX-V01-CS02-with-P256_XMD:SHA-256_SSWU_NU_", q128, "324532006312be4f162614076460315f7a54a6f85544da773dc659aca0311853", "8d8197374bcd52de2acfefc8a54fe2c8d8bebd2a39f16be9b710e4b1af6ef883"},
+	{h2c.P256NU, "QUUX-V01-CS02-with-P256_XMD:SHA-256_SSWU_NU_", a512, "5c4bad52f81f39c8e8de1260e9a06d72b8b00a0829a8ea004a610b0691bea5d9", "c801e7c0782af1f74f24fc385a8555da0582032a3ce038de637ccdcb16f7ef7b"},
+	{h2c.SM2P256RO, "QUUX-V01-CS02-with-SM2P256_XMD:SM3_SSWU_RO_", "", "fb5300d97adbb3fe56f876b4d6f73e7e28e27ef32c583bf3c9989b69b3a3335e", "d3efb316dfcc8df3695c9c7f319a1e8e69b4100270a77f96cc1bb90546480e1a"},
+	{h2c.SM2P256RO, "QUUX-V01-CS02-with-SM2P256_XMD:SM3_SSWU_RO_", "abc", "9ad637b1251f901c8fa3ba4c933a01e810d1fa8ddbb0b575379ae426e28c9b56", "2bf16fbad7aece0c114cb50d28512cacaa2924c16b60099e9f051af83062bb17"},
+	{h2c.SM2P256RO, "QUUX-V01-CS02-with-SM2P256_XMD:SM3_SSWU_RO_", "abcdef0123456789", "b138cfd81c3e3923b2aa5ad7b5b0887efded8184fff3521c4d9bee61d80aea52", "a3cda8ff26f6c478fb7b676b8d178ec923c507e4aff622f9e43e2b4dd1a6ea23"},
+	{h2c.SM2P256RO, "QUUX-V01-CS02-with-SM2P256_XMD:SM3_SSWU_RO_", q128, "88d43623ae8d16c205edcb097fdbf4004cacaf433c1ab780bb09c47ad6fc87b7", "cc79ac44ffa57dd01bfc7288eb6a075d0ba65f4b34ea0750b4c2ff51c7b065a9"},
+	{h2c.SM2P256RO, "QUUX-V01-CS02-with-SM2P256_XMD:SM3_SSWU_RO_", a512, "feb68e5c2cf717679df076d610b8f0cc71c50569a5bf1d61e246406afae1c4ed", "ed1a4fb4848984da1765727fe5922b589129aa802c47c2b181e79203f2f08958"},
+	{h2c.SM2P256NU, "QUUX-V01-CS02-with-SM2P256_XMD:SM3_SSWU_NU_", "", "89719ba7689b4dc15ff82e9367edd3533a1b81bfc62c8433aa3546ecf4ddc8a9", "3f0e838ac245919287745c739b6962ff7fc8ff952ae096febaa12daaaea31533"},
+	{h2c.SM2P256NU, "QUUX-V01-CS02-with-SM2P256_XMD:SM3_SSWU_NU_", "abc", "2da27111fe5a8437ba77225516ac8666f7ce6d4c30c70742478bad122c701f2c", "68e9f2a939f8ad46508896290c3193d5b6b724247beff73db60f3397797bdf10"},
+	{h2c.SM2P256NU, "QUUX-V01-CS02-with-SM2P256_XMD:SM3_SSWU_NU_", "abcdef0123456789", "c94245e6a953303ce6405b861a9d6c27815385d5904ce9503bf3d65cf3e5924f", "4ab76205bc80a3bf5637d9b7cc1dc9dedda1e7c71ffce4d566f309e14f70be73"},
+	{h2c.SM2P256NU, "QUUX-V01-CS02-with-SM2P256_XMD:SM3_SSWU_NU_", q128, "d259b34dbc9fc8c4a6a384f6d8924ff7cabc7c0dbcf001c3c06b2abaee1ef9aa", "2cefe98e26f3cce8d51a46c03e816db52ca50d4517e92ae6659433c0b4ba668f"},
+	{h2c.SM2P256NU, "QUUX-V01-CS02-with-SM2P256_XMD:SM3_SSWU_NU_", a512, "30ad688ebec0d382297a3a6c47924c48ae4ae79605fce4bdd75ebfc221cf4652", "bd175b94239d5569e43f2d3e0973abd7791858b6eb63a0d2ca9c8910f107e003"},
+}
+
+func TestHashToCurve(t *testing.T) {
+	for i, tt := range hashToCurveTests {
+		s, err := h2c.NewSuite(tt.suite)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := s.Hash([]byte(tt.msg), []byte(tt.dst))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := decodeHex("04" + tt.x + tt.y)
+		if !bytes.Equal(got, want) {
+			t.Errorf("case %d %s: got %x, want %x", i, tt.suite, got, want)
+		}
+	}
+}
+
+func TestSM2Shortcuts(t *testing.T) {
+	msg, dst := []byte("abc"), []byte("QUUX-V01-CS02-with-SM2P256_XMD:SM3_SSWU_RO_")
+	p, err := h2c.HashToCurve(msg, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	curve := sm2ec.P256()
+	x, y := new(big.Int).SetBytes(p[1:33]), new(big.Int).SetBytes(p[33:])
+	if !curve.IsOnCurve(x, y) {
+		t.Fatal("point is not on the SM2 curve")
+	}
+	s, _ := h2c.NewSuite(h2c.SM2P256RO)
+	if want, _ := s.Hash(msg, dst); !bytes.Equal(p, want) {
+		t.Error("HashToCurve does not match the SM2P256RO suite")
+	}
+	p, err = h2c.EncodeToCurve(msg, []byte("QUUX-V01-CS02-with-SM2P256_XMD:SM3_SSWU_NU_"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x, y := new(big.Int).SetBytes(p[1:33]), new(big.Int).SetBytes(p[33:]); !curve.IsOnCurve(x, y) {
+		t.Fatal("point is not on the SM2 curve")
+	}
+	if _, err := h2c.HashToCurve(msg, nil); err == nil {
+		t.Error("expected error for empty dst")
+	}
+	if _, err := h2c.NewSuite("P384_XMD:SHA-384_SSWU_RO_"); err == nil {
+		t.Error("expected error for unsupported suite")
+	}
+}
+
+var hashToScalarTests = []struct {
+	msg  string
+	want string
+}{
+	{"", "610e9cc7209b191322e02ccfa265a305a1fd462884538bd2d71fcf541bb0f896"},
+	{"abc", "d11ce78fb10a64dff66cec75a50fdae458dd4a1c3254dfb0f125e048338d8e38"},
+	{"abcdef0123456789", "f79966b1f74fd9bb883f28bcd9100ad3bb48990b18aebd8f338f73dcc9ef3a22"},
+}
+
+func TestHashToScalar(t *testing.T) {
+	dst := []byte("QUUX-V01-CS02-with-SM2P256_XMD:SM3_HashToScalar_")
+	for i, tt := range hashToScalarTests {
+		got, err := h2c.HashToScalar([]byte(tt.msg), dst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(got) != tt.want {
+			t.Errorf("case %d: got %x, want %s", i, got, tt.want)
+		}
+	}
+}
+
+func BenchmarkHashToCurve(b *testing.B) {
+	msg, dst := []byte("abc"), []byte("QUUX-V01-CS02-with-SM2P256_XMD:SM3_SSWU_RO_")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := h2c.HashToCurve(msg, dst); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
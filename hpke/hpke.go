@@ -0,0 +1,419 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package hpke implements the Hybrid Public Key Encryption of RFC 9180, with
+// the Base, PSK, Auth and AuthPSK modes and the secret export.
+//
+// Besides the KEMs, KDFs and AEADs of RFC 9180, the package supports the
+// ShangMi algorithms and post-quantum KEMs:
+//
+//   - [KEMSM2HKDFSM3], DHKEM(SM2, HKDF-SM3): the DHKEM of RFC 9180, section
+//     4.1, over the SM2 curve with HKDF-SM3, with the parameters of
+//     DHKEM(P-256, HKDF-SHA256);
+//   - [KEMMLKEM768], ML-KEM-768 of FIPS 203 used directly as a KEM;
+//   - [KEMSM2MLKEM768], the hybrid of the ECDH over the SM2 curve and
+//     ML-KEM-768 of the TLS 1.3 group SM2MLKEM768, see package tls13;
+//   - [KDFHKDFSM3], HKDF with SM3;
+//   - [AEADSM4GCM], SM4-GCM with a 16-byte key.
+//
+// The identifiers of the SM algorithms and of the SM2 hybrid KEM are not
+// registered with IANA, they only interoperate with the implementations which
+// use the same private values.
+package hpke
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+	"math"
+
+	"github.com/emmansun/gmsm/sm3"
+	"github.com/emmansun/gmsm/sm4"
+)
+
+// Mode is an HPKE mode, RFC 9180, section 5.
+type Mode byte
+
+const (
+	ModeBase    Mode = 0x00
+	ModePSK     Mode = 0x01
+	ModeAuth    Mode = 0x02
+	ModeAuthPSK Mode = 0x03
+)
+
+// KDF is the identifier of a key derivation function.
+type KDF uint16
+
+const (
+	KDFHKDFSHA256 KDF = 0x0001
+	KDFHKDFSM3    KDF = 0xFF01 // not registered with IANA
+)
+
+// AEAD is the identifier of an authenticated encryption algorithm.
+type AEAD uint16
+
+const (
+	AEADAES128GCM  AEAD = 0x0001
+	AEADAES256GCM  AEAD = 0x0002
+	AEADSM4GCM     AEAD = 0xFF01 // not registered with IANA
+	AEADExportOnly AEAD = 0xFFFF // no encryption, only the secret export
+)
+
+const (
+	versionLabel = "HPKE-v1"
+	nonceSize    = 12
+)
+
+// labeledKDF implements LabeledExtract and LabeledExpand of RFC 9180,
+// section 4, for a hash function and a suite_id.
+type labeledKDF struct {
+	hash    func() hash.Hash
+	suiteID []byte
+}
+
+func (k *labeledKDF) size() int {
+	return k.hash().Size()
+}
+
+func (k *labeledKDF) extract(salt []byte, label string, ikm []byte) []byte {
+	labeled := make([]byte, 0, len(versionLabel)+len(k.suiteID)+len(label)+len(ikm))
+	labeled = append(labeled, versionLabel...)
+	labeled = append(labeled, k.suiteID...)
+	labeled = append(labeled, label...)
+	labeled = append(labeled, ikm...)
+	prk, err := hkdf.Extract(k.hash, labeled, salt)
+	if err != nil {
+		panic("hpke: internal error: " + err.Error())
+	}
+	return prk
+}
+
+func (k *labeledKDF) expand(prk []byte, label string, info []byte, length int) ([]byte, error) {
+	if length > math.MaxUint16 || length > 255*k.size() {
+		return nil, errors.New("hpke: requested length too large")
+	}
+	labeled := make([]byte, 0, 2+len(versionLabel)+len(k.suiteID)+len(label)+len(info))
+	labeled = binary.BigEndian.AppendUint16(labeled, uint16(length))
+	labeled = append(labeled, versionLabel...)
+	labeled = append(labeled, k.suiteID...)
+	labeled = append(labeled, label...)
+	labeled = append(labeled, info...)
+	return hkdf.Expand(k.hash, prk, string(labeled), length)
+}
+
+func kdfHash(id KDF) func() hash.Hash {
+	switch id {
+	case KDFHKDFSHA256:
+		return sha256.New
+	case KDFHKDFSM3:
+		return sm3.New
+	default:
+		return nil
+	}
+}
+
+func newGCM(newCipher func([]byte) (cipher.Block, error)) func([]byte) (cipher.AEAD, error) {
+	return func(key []byte) (cipher.AEAD, error) {
+		block, err := newCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+	}
+}
+
+// Suite is an HPKE ciphersuite, the combination of a KEM, a KDF and an AEAD.
+type Suite struct {
+	kem     kemScheme
+	kdf     labeledKDF
+	aeadID  AEAD
+	keySize int
+	newAEAD func(key []byte) (cipher.AEAD, error)
+}
+
+// NewSuite returns the ciphersuite of the identifiers.
+func NewSuite(kem KEM, kdf KDF, aead AEAD) (*Suite, error) {
+	k, err := kem.scheme()
+	if err != nil {
+		return nil, err
+	}
+	h := kdfHash(kdf)
+	if h == nil {
+		return nil, errors.New("hpke: unsupported KDF")
+	}
+	s := &Suite{kem: k, aeadID: aead}
+	s.kdf.hash = h
+	s.kdf.suiteID = append([]byte("HPKE"), 0, 0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint16(s.kdf.suiteID[4:], uint16(kem))
+	binary.BigEndian.PutUint16(s.kdf.suiteID[6:], uint16(kdf))
+	binary.BigEndian.PutUint16(s.kdf.suiteID[8:], uint16(aead))
+	switch aead {
+	case AEADAES128GCM:
+		s.keySize, s.newAEAD = 16, newGCM(aes.NewCipher)
+	case AEADAES256GCM:
+		s.keySize, s.newAEAD = 32, newGCM(aes.NewCipher)
+	case AEADSM4GCM:
+		s.keySize, s.newAEAD = 16, newGCM(sm4.NewCipher)
+	case AEADExportOnly:
+	default:
+		return nil, errors.New("hpke: unsupported AEAD")
+	}
+	return s, nil
+}
+
+// KEM returns the identifier of the KEM of the suite.
+func (s *Suite) KEM() KEM {
+	return s.kem.id()
+}
+
+// context is the encryption context of RFC 9180, section 5.2.
+type context struct {
+	suite          *Suite
+	aead           cipher.AEAD // nil for the export-only AEAD
+	key            []byte
+	baseNonce      []byte
+	seq            uint64
+	exporterSecret []byte
+}
+
+// keySchedule implements KeySchedule<ROLE> of RFC 9180, section 5.1.
+func (s *Suite) keySchedule(mode Mode, sharedSecret, info, psk, pskID []byte) (*context, error) {
+	if (len(psk) == 0) != (len(pskID) == 0) {
+		return nil, errors.New("hpke: inconsistent PSK inputs")
+	}
+	withPSK := mode == ModePSK || mode == ModeAuthPSK
+	if withPSK && len(psk) == 0 {
+		return nil, errors.New("hpke: missing PSK input")
+	}
+	if !withPSK && len(psk) != 0 {
+		return nil, errors.New("hpke: unnecessary PSK input")
+	}
+	pskIDHash := s.kdf.extract(nil, "psk_id_hash", pskID)
+	infoHash := s.kdf.extract(nil, "info_hash", info)
+	keyScheduleContext := append([]byte{byte(mode)}, pskIDHash...)
+	keyScheduleContext = append(keyScheduleContext, infoHash...)
+
+	secret := s.kdf.extract(sharedSecret, "secret", psk)
+	c := &context{suite: s}
+	var err error
+	if s.newAEAD != nil {
+		if c.key, err = s.kdf.expand(secret, "key", keyScheduleContext, s.keySize); err != nil {
+			return nil, err
+		}
+		if c.baseNonce, err = s.kdf.expand(secret, "base_nonce", keyScheduleContext, nonceSize); err != nil {
+			return nil, err
+		}
+		if c.aead, err = s.newAEAD(c.key); err != nil {
+			return nil, err
+		}
+	}
+	if c.exporterSecret, err = s.kdf.expand(secret, "exp", keyScheduleContext, s.kdf.size()); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// nextNonce returns the nonce of the current sequence number and increments
+// it.
+func (c *context) nextNonce() ([]byte, error) {
+	if c.aead == nil {
+		return nil, errors.New("hpke: encryption with the export-only AEAD")
+	}
+	if c.seq == math.MaxUint64 {
+		return nil, errors.New("hpke: message limit reached")
+	}
+	nonce := make([]byte, nonceSize)
+	binary.BigEndian.PutUint64(nonce[nonceSize-8:], c.seq)
+	for i := range nonce {
+		nonce[i] ^= c.baseNonce[i]
+	}
+	c.seq++
+	return nonce, nil
+}
+
+// Export derives a secret of length bytes from the exporter context, RFC
+// 9180, section 5.3.
+func (c *context) Export(exporterContext []byte, length int) ([]byte, error) {
+	return c.suite.kdf.expand(c.exporterSecret, "sec", exporterContext, length)
+}
+
+// Sender is the encryption context of the sender.
+type Sender struct {
+	context
+}
+
+// Seal encrypts and authenticates plaintext with the additional data aad and
+// the next nonce.
+func (c *Sender) Seal(aad, plaintext []byte) ([]byte, error) {
+	nonce, err := c.nextNonce()
+	if err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nil, nonce, plaintext, aad), nil
+}
+
+// Receiver is the encryption context of the recipient.
+type Receiver struct {
+	context
+}
+
+// Open decrypts and authenticates ciphertext with the additional data aad and
+// the next nonce. The sequence number is only incremented on success, so the
+// messages must be opened in the order of their encryption.
+func (c *Receiver) Open(aad, ciphertext []byte) ([]byte, error) {
+	if c.aead == nil {
+		return nil, errors.New("hpke: encryption with the export-only AEAD")
+	}
+	nonce, err := c.nextNonce()
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := c.aead.Open(nil, nonce, ciphertext, aad)
+	if err != nil {
+		c.seq--
+		return nil, errors.New("hpke: decryption error")
+	}
+	return plaintext, nil
+}
+
+func (s *Suite) checkKey(kem KEM) error {
+	if kem != s.kem.id() {
+		return errors.New("hpke: key of another KEM")
+	}
+	return nil
+}
+
+func (s *Suite) setupS(rand io.Reader, mode Mode, pkR *PublicKey, info, psk, pskID []byte, skS *PrivateKey) ([]byte, *Sender, error) {
+	if err := s.checkKey(pkR.kem); err != nil {
+		return nil, nil, err
+	}
+	var sharedSecret, enc []byte
+	var err error
+	if skS != nil {
+		if err := s.checkKey(skS.kem); err != nil {
+			return nil, nil, err
+		}
+		sharedSecret, enc, err = s.kem.authEncap(rand, pkR, skS)
+	} else {
+		sharedSecret, enc, err = s.kem.encap(rand, pkR)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	c, err := s.keySchedule(mode, sharedSecret, info, psk, pskID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return enc, &Sender{*c}, nil
+}
+
+func (s *Suite) setupR(mode Mode, enc []byte, skR *PrivateKey, info, psk, pskID []byte, pkS *PublicKey) (*Receiver, error) {
+	if err := s.checkKey(skR.kem); err != nil {
+		return nil, err
+	}
+	var sharedSecret []byte
+	var err error
+	if pkS != nil {
+		if err := s.checkKey(pkS.kem); err != nil {
+			return nil, err
+		}
+		sharedSecret, err = s.kem.authDecap(enc, skR, pkS)
+	} else {
+		sharedSecret, err = s.kem.decap(enc, skR)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c, err := s.keySchedule(mode, sharedSecret, info, psk, pskID)
+	if err != nil {
+		return nil, err
+	}
+	return &Receiver{*c}, nil
+}
+
+// SetupBaseS sets up the context of the sender in the Base mode, and returns
+// the encapsulated key to send to the recipient.
+func (s *Suite) SetupBaseS(rand io.Reader, pkR *PublicKey, info []byte) (enc []byte, sender *Sender, err error) {
+	return s.setupS(rand, ModeBase, pkR, info, nil, nil, nil)
+}
+
+// SetupBaseR sets up the context of the recipient in the Base mode.
+func (s *Suite) SetupBaseR(enc []byte, skR *PrivateKey, info []byte) (*Receiver, error) {
+	return s.setupR(ModeBase, enc, skR, info, nil, nil, nil)
+}
+
+// SetupPSKS sets up the context of the sender in the PSK mode, authenticated
+// by the pre-shared key psk of identifier pskID.
+func (s *Suite) SetupPSKS(rand io.Reader, pkR *PublicKey, info, psk, pskID []byte) (enc []byte, sender *Sender, err error) {
+	return s.setupS(rand, ModePSK, pkR, info, psk, pskID, nil)
+}
+
+// SetupPSKR sets up the context of the recipient in the PSK mode.
+func (s *Suite) SetupPSKR(enc []byte, skR *PrivateKey, info, psk, pskID []byte) (*Receiver, error) {
+	return s.setupR(ModePSK, enc, skR, info, psk, pskID, nil)
+}
+
+// SetupAuthS sets up the context of the sender in the Auth mode,
+// authenticated by the private key skS of the sender. Only the DHKEMs support
+// the Auth modes.
+func (s *Suite) SetupAuthS(rand io.Reader, pkR *PublicKey, info []byte, skS *PrivateKey) (enc []byte, sender *Sender, err error) {
+	if skS == nil {
+		return nil, nil, errors.New("hpke: missing sender private key")
+	}
+	return s.setupS(rand, ModeAuth, pkR, info, nil, nil, skS)
+}
+
+// SetupAuthR sets up the context of the recipient in the Auth mode, with the
+// public key pkS of the sender.
+func (s *Suite) SetupAuthR(enc []byte, skR *PrivateKey, info []byte, pkS *PublicKey) (*Receiver, error) {
+	if pkS == nil {
+		return nil, errors.New("hpke: missing sender public key")
+	}
+	return s.setupR(ModeAuth, enc, skR, info, nil, nil, pkS)
+}
+
+// SetupAuthPSKS sets up the context of the sender in the AuthPSK mode, which
+// combines the PSK and the Auth modes.
+func (s *Suite) SetupAuthPSKS(rand io.Reader, pkR *PublicKey, info, psk, pskID []byte, skS *PrivateKey) (enc []byte, sender *Sender, err error) {
+	if skS == nil {
+		return nil, nil, errors.New("hpke: missing sender private key")
+	}
+	return s.setupS(rand, ModeAuthPSK, pkR, info, psk, pskID, skS)
+}
+
+// SetupAuthPSKR sets up the context of the recipient in the AuthPSK mode.
+func (s *Suite) SetupAuthPSKR(enc []byte, skR *PrivateKey, info, psk, pskID []byte, pkS *PublicKey) (*Receiver, error) {
+	if pkS == nil {
+		return nil, errors.New("hpke: missing sender public key")
+	}
+	return s.setupR(ModeAuthPSK, enc, skR, info, psk, pskID, pkS)
+}
+
+// Seal is the single-shot encryption of the Base mode: it encrypts one
+// message to pkR and returns the encapsulated key and the ciphertext.
+func (s *Suite) Seal(rand io.Reader, pkR *PublicKey, info, aad, plaintext []byte) (enc, ciphertext []byte, err error) {
+	enc, sender, err := s.SetupBaseS(rand, pkR, info)
+	if err != nil {
+		return nil, nil, err
+	}
+	ciphertext, err = sender.Seal(aad, plaintext)
+	if err != nil {
+		return nil, nil, err
+	}
+	return enc, ciphertext, nil
+}
+
+// Open is the single-shot decryption of the Base mode.
+func (s *Suite) Open(enc []byte, skR *PrivateKey, info, aad, ciphertext []byte) ([]byte, error) {
+	receiver, err := s.SetupBaseR(enc, skR, info)
+	if err != nil {
+		return nil, err
+	}
+	return receiver.Open(aad, ciphertext)
+}
@@ -0,0 +1,393 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package hpke
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testdata/rfc9180.json holds the vectors of RFC 9180, appendix A, of the
+// DHKEM(P-256, HKDF-SHA256) and DHKEM(X25519, HKDF-SHA256) suites with
+// HKDF-SHA256, with a few of the encryptions of each. testdata/sm2.json uses
+// the same format for DHKEM(SM2, HKDF-SM3), HKDF-SM3 and SM4-GCM, whose
+// identifiers are private to this package, so its values come from it.
+type vector struct {
+	Mode           Mode   `json:"mode"`
+	KEM            KEM    `json:"kem_id"`
+	KDF            KDF    `json:"kdf_id"`
+	AEAD           AEAD   `json:"aead_id"`
+	Info           string `json:"info"`
+	IkmR           string `json:"ikmR"`
+	IkmE           string `json:"ikmE"`
+	IkmS           string `json:"ikmS"`
+	SkRm           string `json:"skRm"`
+	PkRm           string `json:"pkRm"`
+	SkSm           string `json:"skSm"`
+	PkSm           string `json:"pkSm"`
+	PSK            string `json:"psk"`
+	PSKID          string `json:"psk_id"`
+	Enc            string `json:"enc"`
+	SharedSecret   string `json:"shared_secret"`
+	Key            string `json:"key"`
+	BaseNonce      string `json:"base_nonce"`
+	ExporterSecret string `json:"exporter_secret"`
+	Encryptions    []struct {
+		AAD   string `json:"aad"`
+		CT    string `json:"ct"`
+		Nonce string `json:"nonce"`
+		PT    string `json:"pt"`
+	} `json:"encryptions"`
+	Exports []struct {
+		Context string `json:"exporter_context"`
+		L       int    `json:"L"`
+		Value   string `json:"exported_value"`
+	} `json:"exports"`
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func deriveKeyPair(t *testing.T, kem KEM, ikm, sk, pk string) *PrivateKey {
+	t.Helper()
+	key, err := kem.DeriveKeyPair(decodeHex(t, ikm))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(key.Bytes()); got != sk {
+		t.Errorf("sk = %s, want %s", got, sk)
+	}
+	if got := hex.EncodeToString(key.PublicKey().Bytes()); got != pk {
+		t.Errorf("pk = %s, want %s", got, pk)
+	}
+	return key
+}
+
+func testVector(t *testing.T, v *vector) {
+	s, err := NewSuite(v.KEM, v.KDF, v.AEAD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skR := deriveKeyPair(t, v.KEM, v.IkmR, v.SkRm, v.PkRm)
+	var skS *PrivateKey
+	var pkS *PublicKey
+	if v.IkmS != "" {
+		skS = deriveKeyPair(t, v.KEM, v.IkmS, v.SkSm, v.PkSm)
+		if pkS, err = v.KEM.NewPublicKey(decodeHex(t, v.PkSm)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pkR, err := v.KEM.NewPublicKey(decodeHex(t, v.PkRm))
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, psk, pskID := decodeHex(t, v.Info), decodeHex(t, v.PSK), decodeHex(t, v.PSKID)
+
+	// the key generation of the ephemeral key reads ikmE
+	random := bytes.NewReader(decodeHex(t, v.IkmE))
+	var enc []byte
+	var sender *Sender
+	var receiver *Receiver
+	switch v.Mode {
+	case ModeBase:
+		if enc, sender, err = s.SetupBaseS(random, pkR, info); err == nil {
+			receiver, err = s.SetupBaseR(enc, skR, info)
+		}
+	case ModePSK:
+		if enc, sender, err = s.SetupPSKS(random, pkR, info, psk, pskID); err == nil {
+			receiver, err = s.SetupPSKR(enc, skR, info, psk, pskID)
+		}
+	case ModeAuth:
+		if enc, sender, err = s.SetupAuthS(random, pkR, info, skS); err == nil {
+			receiver, err = s.SetupAuthR(enc, skR, info, pkS)
+		}
+	case ModeAuthPSK:
+		if enc, sender, err = s.SetupAuthPSKS(random, pkR, info, psk, pskID, skS); err == nil {
+			receiver, err = s.SetupAuthPSKR(enc, skR, info, psk, pskID, pkS)
+		}
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := hex.EncodeToString(enc); got != v.Enc {
+		t.Errorf("enc = %s, want %s", got, v.Enc)
+	}
+	for _, c := range []*context{&sender.context, &receiver.context} {
+		if got := hex.EncodeToString(c.key); got != v.Key {
+			t.Errorf("key = %s, want %s", got, v.Key)
+		}
+		if got := hex.EncodeToString(c.baseNonce); got != v.BaseNonce {
+			t.Errorf("base_nonce = %s, want %s", got, v.BaseNonce)
+		}
+		if got := hex.EncodeToString(c.exporterSecret); got != v.ExporterSecret {
+			t.Errorf("exporter_secret = %s, want %s", got, v.ExporterSecret)
+		}
+	}
+
+	for _, e := range v.Encryptions {
+		// the sequence number of the encryption is nonce XOR base_nonce
+		nonce := decodeHex(t, e.Nonce)
+		seq := binary.BigEndian.Uint64(nonce[4:]) ^ binary.BigEndian.Uint64(sender.baseNonce[4:])
+		sender.seq, receiver.seq = seq, seq
+		aad, pt := decodeHex(t, e.AAD), decodeHex(t, e.PT)
+		ct, err := sender.Seal(aad, pt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(ct); got != e.CT {
+			t.Errorf("seq %d: ct = %s, want %s", seq, got, e.CT)
+		}
+		got, err := receiver.Open(aad, ct)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, pt) {
+			t.Errorf("seq %d: pt = %x, want %x", seq, got, pt)
+		}
+	}
+	for _, e := range v.Exports {
+		for _, c := range []*context{&sender.context, &receiver.context} {
+			got, err := c.Export(decodeHex(t, e.Context), e.L)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hex.EncodeToString(got) != e.Value {
+				t.Errorf("export %s = %x, want %s", e.Context, got, e.Value)
+			}
+		}
+	}
+}
+
+func TestVectors(t *testing.T) {
+	for _, name := range []string{"rfc9180.json", "sm2.json"} {
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var vectors []vector
+		if err := json.Unmarshal(data, &vectors); err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range vectors {
+			t.Run(name, func(t *testing.T) {
+				testVector(t, &v)
+			})
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	info, aad := []byte("info"), []byte("aad")
+	psk, pskID := bytes.Repeat([]byte{0x42}, 32), []byte("psk id")
+	for _, kem := range []KEM{KEMP256HKDFSHA256, KEMX25519HKDFSHA256, KEMSM2HKDFSM3, KEMMLKEM768, KEMSM2MLKEM768} {
+		for _, kdf := range []KDF{KDFHKDFSHA256, KDFHKDFSM3} {
+			for _, aead := range []AEAD{AEADAES128GCM, AEADAES256GCM, AEADSM4GCM} {
+				s, err := NewSuite(kem, kdf, aead)
+				if err != nil {
+					t.Fatal(err)
+				}
+				skR, err := kem.GenerateKey(rand.Reader)
+				if err != nil {
+					t.Fatal(err)
+				}
+				// the keys go through their serializations
+				if skR, err = kem.NewPrivateKey(skR.Bytes()); err != nil {
+					t.Fatal(err)
+				}
+				pkR, err := kem.NewPublicKey(skR.PublicKey().Bytes())
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				enc, ct, err := s.Seal(rand.Reader, pkR, info, aad, []byte("hello"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				pt, err := s.Open(enc, skR, info, aad, ct)
+				if err != nil {
+					t.Fatalf("%#x %#x %#x: %v", kem, kdf, aead, err)
+				}
+				if string(pt) != "hello" {
+					t.Errorf("%#x %#x %#x: pt = %q", kem, kdf, aead, pt)
+				}
+				if _, err := s.Open(enc, skR, []byte("other info"), aad, ct); err == nil {
+					t.Errorf("%#x %#x %#x: expected error with another info", kem, kdf, aead)
+				}
+
+				enc, sender, err := s.SetupPSKS(rand.Reader, pkR, info, psk, pskID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				receiver, err := s.SetupPSKR(enc, skR, info, psk, pskID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for i := 0; i < 3; i++ {
+					ct, err := sender.Seal(aad, []byte{byte(i)})
+					if err != nil {
+						t.Fatal(err)
+					}
+					if _, err := receiver.Open(nil, ct); err == nil {
+						t.Fatal("expected error with another aad")
+					}
+					pt, err := receiver.Open(aad, ct)
+					if err != nil || !bytes.Equal(pt, []byte{byte(i)}) {
+						t.Fatalf("%#x %#x %#x: message %d: %x, %v", kem, kdf, aead, i, pt, err)
+					}
+				}
+				e1, _ := sender.Export([]byte("context"), 48)
+				e2, _ := receiver.Export([]byte("context"), 48)
+				if len(e1) != 48 || !bytes.Equal(e1, e2) {
+					t.Errorf("%#x %#x %#x: exported secrets mismatch", kem, kdf, aead)
+				}
+			}
+		}
+	}
+}
+
+func TestAuth(t *testing.T) {
+	s, err := NewSuite(KEMSM2HKDFSM3, KDFHKDFSM3, AEADSM4GCM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skR, _ := KEMSM2HKDFSM3.GenerateKey(rand.Reader)
+	skS, _ := KEMSM2HKDFSM3.GenerateKey(rand.Reader)
+	other, _ := KEMSM2HKDFSM3.GenerateKey(rand.Reader)
+	enc, sender, err := s.SetupAuthS(rand.Reader, skR.PublicKey(), nil, skS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct, _ := sender.Seal(nil, []byte("hello"))
+	receiver, err := s.SetupAuthR(enc, skR, nil, other.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := receiver.Open(nil, ct); err == nil {
+		t.Error("expected error with another sender")
+	}
+	receiver, err = s.SetupAuthR(enc, skR, nil, skS.PublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pt, err := receiver.Open(nil, ct); err != nil || string(pt) != "hello" {
+		t.Errorf("pt = %q, %v", pt, err)
+	}
+
+	for _, kem := range []KEM{KEMMLKEM768, KEMSM2MLKEM768} {
+		s, _ := NewSuite(kem, KDFHKDFSM3, AEADSM4GCM)
+		sk, _ := kem.GenerateKey(rand.Reader)
+		if _, _, err := s.SetupAuthS(rand.Reader, sk.PublicKey(), nil, sk); err == nil {
+			t.Errorf("%#x: expected error with the Auth mode", kem)
+		}
+		if _, err := s.SetupAuthPSKR(make([]byte, 10), sk, nil, []byte("psk"), []byte("id"), sk.PublicKey()); err == nil {
+			t.Errorf("%#x: expected error with the AuthPSK mode", kem)
+		}
+	}
+}
+
+func TestExportOnly(t *testing.T) {
+	s, err := NewSuite(KEMSM2HKDFSM3, KDFHKDFSM3, AEADExportOnly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	skR, _ := KEMSM2HKDFSM3.GenerateKey(rand.Reader)
+	enc, sender, err := s.SetupBaseS(rand.Reader, skR.PublicKey(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sender.Seal(nil, []byte("hello")); err == nil {
+		t.Error("expected error with the export-only AEAD")
+	}
+	receiver, err := s.SetupBaseR(enc, skR, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := receiver.Open(nil, []byte("hello")); err == nil {
+		t.Error("expected error with the export-only AEAD")
+	}
+	e1, _ := sender.Export(nil, 32)
+	e2, _ := receiver.Export(nil, 32)
+	if !bytes.Equal(e1, e2) {
+		t.Error("exported secrets mismatch")
+	}
+	if _, err := sender.Export(nil, 255*32+1); err == nil {
+		t.Error("expected error with a too long export")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, err := NewSuite(0x9999, KDFHKDFSM3, AEADSM4GCM); err == nil {
+		t.Error("expected error with unknown KEM")
+	}
+	if _, err := NewSuite(KEMSM2HKDFSM3, 0x9999, AEADSM4GCM); err == nil {
+		t.Error("expected error with unknown KDF")
+	}
+	if _, err := NewSuite(KEMSM2HKDFSM3, KDFHKDFSM3, 0x9999); err == nil {
+		t.Error("expected error with unknown AEAD")
+	}
+	s, _ := NewSuite(KEMSM2HKDFSM3, KDFHKDFSM3, AEADSM4GCM)
+	skR, _ := KEMSM2HKDFSM3.GenerateKey(rand.Reader)
+	pkR := skR.PublicKey()
+	if _, _, err := s.SetupPSKS(rand.Reader, pkR, nil, []byte("psk"), nil); err == nil {
+		t.Error("expected error with a PSK without identifier")
+	}
+	if _, _, err := s.SetupPSKS(rand.Reader, pkR, nil, nil, nil); err == nil {
+		t.Error("expected error with the PSK mode without PSK")
+	}
+	if _, err := s.setupR(ModeBase, make([]byte, 65), skR, nil, []byte("psk"), []byte("id"), nil); err == nil {
+		t.Error("expected error with the Base mode with PSK")
+	}
+	if _, err := s.SetupBaseR(make([]byte, 65), skR, nil); err == nil {
+		t.Error("expected error with invalid encapsulated key")
+	}
+	p256, _ := KEMP256HKDFSHA256.GenerateKey(rand.Reader)
+	if _, _, err := s.SetupBaseS(rand.Reader, p256.PublicKey(), nil); err == nil {
+		t.Error("expected error with a key of another KEM")
+	}
+	if _, err := KEMSM2HKDFSM3.NewPrivateKey(make([]byte, 32)); err == nil {
+		t.Error("expected error with a zero private key")
+	}
+	if _, err := KEMSM2HKDFSM3.NewPublicKey(make([]byte, 65)); err == nil {
+		t.Error("expected error with an invalid public key")
+	}
+	if _, err := KEMSM2MLKEM768.NewPublicKey(pkR.Bytes()); err == nil {
+		t.Error("expected error with a short hybrid public key")
+	}
+	if _, err := KEMMLKEM768.NewPrivateKey(make([]byte, 32)); err == nil {
+		t.Error("expected error with a short ML-KEM seed")
+	}
+}
+
+func BenchmarkSealOpen(b *testing.B) {
+	for _, kem := range []KEM{KEMSM2HKDFSM3, KEMSM2MLKEM768} {
+		s, _ := NewSuite(kem, KDFHKDFSM3, AEADSM4GCM)
+		skR, _ := kem.GenerateKey(rand.Reader)
+		pkR := skR.PublicKey()
+		b.Run(hex.EncodeToString(binary.BigEndian.AppendUint16(nil, uint16(kem))), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				enc, ct, err := s.Seal(rand.Reader, pkR, nil, nil, []byte("hello"))
+				if err != nil {
+					b.Fatal(err)
+				}
+				if _, err := s.Open(enc, skR, nil, nil, ct); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
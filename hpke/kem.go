@@ -0,0 +1,519 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package hpke
+
+import (
+	"crypto/ecdh"
+	"crypto/sha256"
+	"crypto/sha3"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"io"
+
+	gmecdh "github.com/emmansun/gmsm/ecdh"
+	"github.com/emmansun/gmsm/mlkem"
+	"github.com/emmansun/gmsm/sm3"
+	"github.com/emmansun/gmsm/tls13"
+)
+
+// KEM is the identifier of a key encapsulation mechanism.
+type KEM uint16
+
+const (
+	KEMP256HKDFSHA256   KEM = 0x0010
+	KEMX25519HKDFSHA256 KEM = 0x0020
+	KEMMLKEM768         KEM = 0x0041
+	KEMSM2HKDFSM3       KEM = 0xFF10 // not registered with IANA
+	KEMSM2MLKEM768      KEM = 0xFF41 // not registered with IANA
+)
+
+var errAuthNotSupported = errors.New("hpke: KEM doesn't support the Auth modes")
+
+// kemScheme is the interface of the KEMs, RFC 9180, section 4. The Auth
+// methods return errAuthNotSupported for the KEMs without authentication.
+type kemScheme interface {
+	id() KEM
+	// deriveKeyPair returns the deterministic key pair of the input keying
+	// material, and the key generation reads privateKeySeedSize bytes of
+	// randomness into it.
+	deriveKeyPair(ikm []byte) (*PrivateKey, error)
+	privateKeySeedSize() int
+	newPrivateKey(b []byte) (*PrivateKey, error)
+	newPublicKey(b []byte) (*PublicKey, error)
+	encap(rand io.Reader, pkR *PublicKey) (sharedSecret, enc []byte, err error)
+	decap(enc []byte, skR *PrivateKey) ([]byte, error)
+	authEncap(rand io.Reader, pkR *PublicKey, skS *PrivateKey) (sharedSecret, enc []byte, err error)
+	authDecap(enc []byte, skR *PrivateKey, pkS *PublicKey) ([]byte, error)
+}
+
+func (k KEM) scheme() (kemScheme, error) {
+	switch k {
+	case KEMP256HKDFSHA256:
+		return newDHKEM(k, sha256.New, 32, 0xff, stdlibGroup{ecdh.P256()}), nil
+	case KEMX25519HKDFSHA256:
+		return newDHKEM(k, sha256.New, 32, 0, stdlibGroup{ecdh.X25519()}), nil
+	case KEMSM2HKDFSM3:
+		return newDHKEM(k, sm3.New, 32, 0xff, sm2Group{}), nil
+	case KEMMLKEM768:
+		return mlkemKEM{}, nil
+	case KEMSM2MLKEM768:
+		return newHybridKEM(), nil
+	default:
+		return nil, errors.New("hpke: unsupported KEM")
+	}
+}
+
+// GenerateKey generates a key pair of the KEM.
+func (k KEM) GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	s, err := k.scheme()
+	if err != nil {
+		return nil, err
+	}
+	return generateKeyPair(s, rand)
+}
+
+// DeriveKeyPair derives a key pair of the KEM from the input keying material
+// ikm, which should have as much entropy as the private key.
+func (k KEM) DeriveKeyPair(ikm []byte) (*PrivateKey, error) {
+	s, err := k.scheme()
+	if err != nil {
+		return nil, err
+	}
+	return s.deriveKeyPair(ikm)
+}
+
+// NewPrivateKey parses the serialization of a private key of the KEM.
+func (k KEM) NewPrivateKey(b []byte) (*PrivateKey, error) {
+	s, err := k.scheme()
+	if err != nil {
+		return nil, err
+	}
+	return s.newPrivateKey(b)
+}
+
+// NewPublicKey parses the serialization of a public key of the KEM.
+func (k KEM) NewPublicKey(b []byte) (*PublicKey, error) {
+	s, err := k.scheme()
+	if err != nil {
+		return nil, err
+	}
+	return s.newPublicKey(b)
+}
+
+func generateKeyPair(s kemScheme, rand io.Reader) (*PrivateKey, error) {
+	ikm := make([]byte, s.privateKeySeedSize())
+	if _, err := io.ReadFull(rand, ikm); err != nil {
+		return nil, err
+	}
+	return s.deriveKeyPair(ikm)
+}
+
+// PublicKey is a public key of a KEM.
+type PublicKey struct {
+	kem KEM
+	b   []byte
+}
+
+// KEM returns the identifier of the KEM of the key.
+func (pk *PublicKey) KEM() KEM {
+	return pk.kem
+}
+
+// Bytes returns the serialization of the public key, SerializePublicKey of
+// RFC 9180.
+func (pk *PublicKey) Bytes() []byte {
+	return append([]byte{}, pk.b...)
+}
+
+// PrivateKey is a private key of a KEM.
+type PrivateKey struct {
+	kem KEM
+	b   []byte
+	pub *PublicKey
+}
+
+// KEM returns the identifier of the KEM of the key.
+func (sk *PrivateKey) KEM() KEM {
+	return sk.kem
+}
+
+// Bytes returns the serialization of the private key, SerializePrivateKey of
+// RFC 9180.
+func (sk *PrivateKey) Bytes() []byte {
+	return append([]byte{}, sk.b...)
+}
+
+// PublicKey returns the public key of the private key.
+func (sk *PrivateKey) PublicKey() *PublicKey {
+	return sk.pub
+}
+
+func kemKDF(id KEM, h func() hash.Hash) labeledKDF {
+	return labeledKDF{hash: h, suiteID: binary.BigEndian.AppendUint16([]byte("KEM"), uint16(id))}
+}
+
+// dhGroup is the Diffie-Hellman group of a DHKEM, the public keys and the
+// private keys are serialized as in RFC 9180, section 7.1.1.
+type dhGroup interface {
+	// publicKey returns the public key of the private key, or an error if
+	// the private key is invalid.
+	publicKey(sk []byte) ([]byte, error)
+	checkPublicKey(pk []byte) error
+	dh(sk, pk []byte) ([]byte, error)
+}
+
+type stdlibGroup struct {
+	curve ecdh.Curve
+}
+
+func (g stdlibGroup) publicKey(sk []byte) ([]byte, error) {
+	k, err := g.curve.NewPrivateKey(sk)
+	if err != nil {
+		return nil, err
+	}
+	return k.PublicKey().Bytes(), nil
+}
+
+func (g stdlibGroup) checkPublicKey(pk []byte) error {
+	_, err := g.curve.NewPublicKey(pk)
+	return err
+}
+
+func (g stdlibGroup) dh(sk, pk []byte) ([]byte, error) {
+	k, err := g.curve.NewPrivateKey(sk)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := g.curve.NewPublicKey(pk)
+	if err != nil {
+		return nil, err
+	}
+	return k.ECDH(pub)
+}
+
+// sm2Group is the SM2 curve with the encodings of P-256.
+type sm2Group struct{}
+
+func (sm2Group) publicKey(sk []byte) ([]byte, error) {
+	k, err := gmecdh.P256().NewPrivateKey(sk)
+	if err != nil {
+		return nil, err
+	}
+	return k.PublicKey().Bytes(), nil
+}
+
+func (sm2Group) checkPublicKey(pk []byte) error {
+	_, err := gmecdh.P256().NewPublicKey(pk)
+	return err
+}
+
+func (sm2Group) dh(sk, pk []byte) ([]byte, error) {
+	k, err := gmecdh.P256().NewPrivateKey(sk)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := gmecdh.P256().NewPublicKey(pk)
+	if err != nil {
+		return nil, err
+	}
+	return k.ECDH(pub)
+}
+
+// dhKEM is DHKEM(Group, KDF) of RFC 9180, section 4.1.
+type dhKEM struct {
+	kemID   KEM
+	kdf     labeledKDF
+	group   dhGroup
+	nsk     int
+	bitmask byte // 0 for X25519, which derives the private key with "sk"
+}
+
+func newDHKEM(id KEM, h func() hash.Hash, nsk int, bitmask byte, group dhGroup) *dhKEM {
+	return &dhKEM{kemID: id, kdf: kemKDF(id, h), group: group, nsk: nsk, bitmask: bitmask}
+}
+
+func (k *dhKEM) id() KEM { return k.kemID }
+
+func (k *dhKEM) privateKeySeedSize() int { return k.nsk }
+
+func (k *dhKEM) deriveKeyPair(ikm []byte) (*PrivateKey, error) {
+	prk := k.kdf.extract(nil, "dkp_prk", ikm)
+	if k.bitmask == 0 {
+		sk, err := k.kdf.expand(prk, "sk", nil, k.nsk)
+		if err != nil {
+			return nil, err
+		}
+		return k.newPrivateKey(sk)
+	}
+	return deriveCandidate(&k.kdf, prk, k.nsk, k.bitmask, k.newPrivateKey)
+}
+
+// deriveCandidate implements the rejection sampling of DeriveKeyPair for the
+// NIST curves, RFC 9180, section 7.1.3.
+func deriveCandidate(kdf *labeledKDF, prk []byte, nsk int, bitmask byte, newPrivateKey func([]byte) (*PrivateKey, error)) (*PrivateKey, error) {
+	for counter := 0; counter < 256; counter++ {
+		sk, err := kdf.expand(prk, "candidate", []byte{byte(counter)}, nsk)
+		if err != nil {
+			return nil, err
+		}
+		sk[0] &= bitmask
+		if key, err := newPrivateKey(sk); err == nil {
+			return key, nil
+		}
+	}
+	return nil, errors.New("hpke: key derivation failed")
+}
+
+func (k *dhKEM) newPrivateKey(b []byte) (*PrivateKey, error) {
+	if len(b) != k.nsk {
+		return nil, errors.New("hpke: invalid private key size")
+	}
+	pk, err := k.group.publicKey(b)
+	if err != nil {
+		return nil, errors.New("hpke: invalid private key")
+	}
+	return &PrivateKey{kem: k.kemID, b: append([]byte{}, b...), pub: &PublicKey{kem: k.kemID, b: pk}}, nil
+}
+
+func (k *dhKEM) newPublicKey(b []byte) (*PublicKey, error) {
+	if err := k.group.checkPublicKey(b); err != nil {
+		return nil, errors.New("hpke: invalid public key")
+	}
+	return &PublicKey{kem: k.kemID, b: append([]byte{}, b...)}, nil
+}
+
+func (k *dhKEM) extractAndExpand(dh, kemContext []byte) ([]byte, error) {
+	prk := k.kdf.extract(nil, "eae_prk", dh)
+	return k.kdf.expand(prk, "shared_secret", kemContext, k.kdf.size())
+}
+
+func (k *dhKEM) encap(rand io.Reader, pkR *PublicKey) ([]byte, []byte, error) {
+	return k.authEncap(rand, pkR, nil)
+}
+
+func (k *dhKEM) decap(enc []byte, skR *PrivateKey) ([]byte, error) {
+	return k.authDecap(enc, skR, nil)
+}
+
+// authEncap implements AuthEncap, or Encap if skS is nil.
+func (k *dhKEM) authEncap(rand io.Reader, pkR *PublicKey, skS *PrivateKey) ([]byte, []byte, error) {
+	skE, err := generateKeyPair(k, rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	dh, err := k.group.dh(skE.b, pkR.b)
+	if err != nil {
+		return nil, nil, err
+	}
+	enc := skE.pub.b
+	kemContext := append(append([]byte{}, enc...), pkR.b...)
+	if skS != nil {
+		dhS, err := k.group.dh(skS.b, pkR.b)
+		if err != nil {
+			return nil, nil, err
+		}
+		dh = append(dh, dhS...)
+		kemContext = append(kemContext, skS.pub.b...)
+	}
+	sharedSecret, err := k.extractAndExpand(dh, kemContext)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sharedSecret, append([]byte{}, enc...), nil
+}
+
+// authDecap implements AuthDecap, or Decap if pkS is nil.
+func (k *dhKEM) authDecap(enc []byte, skR *PrivateKey, pkS *PublicKey) ([]byte, error) {
+	dh, err := k.group.dh(skR.b, enc)
+	if err != nil {
+		return nil, errors.New("hpke: invalid encapsulated key")
+	}
+	kemContext := append(append([]byte{}, enc...), skR.pub.b...)
+	if pkS != nil {
+		dhS, err := k.group.dh(skR.b, pkS.b)
+		if err != nil {
+			return nil, err
+		}
+		dh = append(dh, dhS...)
+		kemContext = append(kemContext, pkS.b...)
+	}
+	return k.extractAndExpand(dh, kemContext)
+}
+
+// mlkemKEM is ML-KEM-768 used directly as a KEM: the public key is the
+// encapsulation key, the private key the 64-byte seed, enc the ciphertext and
+// the shared secret the shared key. DeriveKeyPair takes the seed from
+// SHAKE256(ikm).
+type mlkemKEM struct{}
+
+func (mlkemKEM) id() KEM { return KEMMLKEM768 }
+
+func (mlkemKEM) privateKeySeedSize() int { return mlkem.SeedSize }
+
+func (k mlkemKEM) deriveKeyPair(ikm []byte) (*PrivateKey, error) {
+	return k.newPrivateKey(sha3.SumSHAKE256(ikm, mlkem.SeedSize))
+}
+
+func (mlkemKEM) newPrivateKey(b []byte) (*PrivateKey, error) {
+	dk, err := mlkem.NewDecapsulationKeyFromSeed768(b)
+	if err != nil {
+		return nil, errors.New("hpke: invalid private key")
+	}
+	pub := &PublicKey{kem: KEMMLKEM768, b: dk.EncapsulationKey().Bytes()}
+	return &PrivateKey{kem: KEMMLKEM768, b: append([]byte{}, b...), pub: pub}, nil
+}
+
+func (mlkemKEM) newPublicKey(b []byte) (*PublicKey, error) {
+	if _, err := mlkem.NewEncapsulationKey768(b); err != nil {
+		return nil, errors.New("hpke: invalid public key")
+	}
+	return &PublicKey{kem: KEMMLKEM768, b: append([]byte{}, b...)}, nil
+}
+
+func (mlkemKEM) encap(rand io.Reader, pkR *PublicKey) ([]byte, []byte, error) {
+	ek, err := mlkem.NewEncapsulationKey768(pkR.b)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ek.Encapsulate(rand)
+}
+
+func (mlkemKEM) decap(enc []byte, skR *PrivateKey) ([]byte, error) {
+	dk, err := mlkem.NewDecapsulationKeyFromSeed768(skR.b)
+	if err != nil {
+		return nil, err
+	}
+	sharedSecret, err := dk.Decapsulate(enc)
+	if err != nil {
+		return nil, errors.New("hpke: invalid encapsulated key")
+	}
+	return sharedSecret, nil
+}
+
+func (mlkemKEM) authEncap(io.Reader, *PublicKey, *PrivateKey) ([]byte, []byte, error) {
+	return nil, nil, errAuthNotSupported
+}
+
+func (mlkemKEM) authDecap([]byte, *PrivateKey, *PublicKey) ([]byte, error) {
+	return nil, errAuthNotSupported
+}
+
+const (
+	sm2PrivateKeySize = 32
+	sm2PublicKeySize  = 65
+)
+
+// hybridKEM is the hybrid of the ECDH over the SM2 curve and ML-KEM-768 with
+// the key exchange of the TLS 1.3 group SM2MLKEM768: the public key and enc
+// are the client and the server key shares, SM2 public key || ML-KEM-768
+// encapsulation key and SM2 public key || ML-KEM-768 ciphertext. The private
+// key is the SM2 private key || the ML-KEM-768 seed. The shared secret is
+//
+//	LabeledExpand(LabeledExtract("", "eae_prk", ss), "shared_secret", enc || pkR, 32)
+//
+// with HKDF-SM3, where ss is the shared secret of the key exchange, the SM2
+// ECDH shared secret || the ML-KEM-768 shared key.
+type hybridKEM struct {
+	kdf labeledKDF
+	kex tls13.KeyExchange
+}
+
+func newHybridKEM() *hybridKEM {
+	kex, err := tls13.NewKeyExchange(tls13.SM2MLKEM768)
+	if err != nil {
+		panic("hpke: internal error: " + err.Error())
+	}
+	return &hybridKEM{kdf: kemKDF(KEMSM2MLKEM768, sm3.New), kex: kex}
+}
+
+func (k *hybridKEM) id() KEM { return KEMSM2MLKEM768 }
+
+func (k *hybridKEM) privateKeySeedSize() int { return sm2PrivateKeySize + mlkem.SeedSize }
+
+// deriveKeyPair derives the SM2 private key as DHKEM(SM2, HKDF-SM3) and the
+// ML-KEM-768 seed with the label "sk".
+func (k *hybridKEM) deriveKeyPair(ikm []byte) (*PrivateKey, error) {
+	prk := k.kdf.extract(nil, "dkp_prk", ikm)
+	seed, err := k.kdf.expand(prk, "sk", nil, mlkem.SeedSize)
+	if err != nil {
+		return nil, err
+	}
+	return deriveCandidate(&k.kdf, prk, sm2PrivateKeySize, 0xff, func(sk []byte) (*PrivateKey, error) {
+		return k.newPrivateKey(append(sk, seed...))
+	})
+}
+
+// decapKeys returns the private keys of the key exchange.
+func (k *hybridKEM) decapKeys(b []byte) (*tls13.KeySharePrivateKeys, error) {
+	if len(b) != k.privateKeySeedSize() {
+		return nil, errors.New("hpke: invalid private key size")
+	}
+	keys, err := tls13.NewKeySharePrivateKeys(tls13.SM2MLKEM768, b[:sm2PrivateKeySize], b[sm2PrivateKeySize:])
+	if err != nil {
+		return nil, errors.New("hpke: invalid private key")
+	}
+	return keys, nil
+}
+
+func (k *hybridKEM) newPrivateKey(b []byte) (*PrivateKey, error) {
+	keys, err := k.decapKeys(b)
+	if err != nil {
+		return nil, err
+	}
+	pk := append(keys.ECDHE.PublicKeyBytes(), keys.MLKEM.EncapsulationKeyBytes()...)
+	return &PrivateKey{kem: KEMSM2MLKEM768, b: append([]byte{}, b...), pub: &PublicKey{kem: KEMSM2MLKEM768, b: pk}}, nil
+}
+
+func (k *hybridKEM) newPublicKey(b []byte) (*PublicKey, error) {
+	if len(b) != sm2PublicKeySize+mlkem.EncapsulationKeySize768 {
+		return nil, errors.New("hpke: invalid public key size")
+	}
+	if _, err := gmecdh.P256().NewPublicKey(b[:sm2PublicKeySize]); err != nil {
+		return nil, errors.New("hpke: invalid public key")
+	}
+	if _, err := mlkem.NewEncapsulationKey768(b[sm2PublicKeySize:]); err != nil {
+		return nil, errors.New("hpke: invalid public key")
+	}
+	return &PublicKey{kem: KEMSM2MLKEM768, b: append([]byte{}, b...)}, nil
+}
+
+func (k *hybridKEM) sharedSecret(ss, enc, pkR []byte) ([]byte, error) {
+	prk := k.kdf.extract(nil, "eae_prk", ss)
+	kemContext := append(append([]byte{}, enc...), pkR...)
+	return k.kdf.expand(prk, "shared_secret", kemContext, k.kdf.size())
+}
+
+func (k *hybridKEM) encap(rand io.Reader, pkR *PublicKey) ([]byte, []byte, error) {
+	ss, share, err := k.kex.ServerSharedSecret(rand, pkR.b)
+	if err != nil {
+		return nil, nil, err
+	}
+	sharedSecret, err := k.sharedSecret(ss, share.Data, pkR.b)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sharedSecret, share.Data, nil
+}
+
+func (k *hybridKEM) decap(enc []byte, skR *PrivateKey) ([]byte, error) {
+	keys, err := k.decapKeys(skR.b)
+	if err != nil {
+		return nil, err
+	}
+	ss, err := k.kex.ClientSharedSecret(keys, enc)
+	if err != nil {
+		return nil, errors.New("hpke: invalid encapsulated key")
+	}
+	return k.sharedSecret(ss, enc, skR.pub.b)
+}
+
+func (k *hybridKEM) authEncap(io.Reader, *PublicKey, *PrivateKey) ([]byte, []byte, error) {
+	return nil, nil, errAuthNotSupported
+}
+
+func (k *hybridKEM) authDecap([]byte, *PrivateKey, *PublicKey) ([]byte, error) {
+	return nil, errAuthNotSupported
+}
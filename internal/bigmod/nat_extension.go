@@ -29,3 +29,23 @@ func (x *Nat) SetOverflowedBytes(b []byte, m *Modulus) *Nat {
 func (x *Nat) CmpGeq(y *Nat) choice {
 	return x.cmpGeq(y)
 }
+
+// SetReducedBytes assigns x = b mod m, where b is a slice of big-endian bytes
+// of any length, for example the output of a hash to be reduced uniformly.
+//
+// The output will be resized to the size of m and overwritten.
+//
+//go:norace
+func (x *Nat) SetReducedBytes(b []byte, m *Modulus) *Nat {
+	return x.Mod(NewNat().resetToBytes(b), m)
+}
+
+// Select sets x <- y if on == 1, and does nothing if on == 0. It is the
+// exported form of assign for the callers which can't name the choice type.
+//
+// Both operands must have the same announced length.
+//
+//go:norace
+func (x *Nat) Select(on int, y *Nat) *Nat {
+	return x.assign(choice(on&1), y)
+}
@@ -282,6 +282,23 @@ func TestMod(t *testing.T) {
 	}
 }
 
+func TestSetReducedBytes(t *testing.T) {
+	m := modulusFromBytes([]byte{0x06, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0d})
+	b := []byte{0x40, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}
+	out := NewNat().SetReducedBytes(b, m)
+	expected := natFromBytes([]byte{0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x09}).ExpandFor(m)
+	if out.Equal(expected) != 1 {
+		t.Errorf("%+v != %+v", out, expected)
+	}
+	one := NewNat().SetUint(1, m)
+	if out.Select(0, one).Equal(expected) != 1 {
+		t.Errorf("Select(0) modified x")
+	}
+	if out.Select(1, one).Equal(one) != 1 {
+		t.Errorf("Select(1) did not assign y")
+	}
+}
+
 func TestModSub(t *testing.T) {
 	m := modulusFromBytes([]byte{13})
 	x := &Nat{[]uint{6}}
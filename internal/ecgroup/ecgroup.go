@@ -0,0 +1,274 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package ecgroup implements the prime-order elliptic curve groups of the
+// protocols written over an abstract group, such as VRFs, OPRFs and PAKEs.
+//
+// The SM2 group is backed by the constant-time [sm2ec.Point], the P-256 group
+// by the P-256 curve of package crypto/elliptic. Scalars are [bigmod.Nat]
+// values modulo the group order, their operations are constant time.
+package ecgroup
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/emmansun/gmsm/internal/bigmod"
+	"github.com/emmansun/gmsm/sm2/sm2ec"
+)
+
+// Group is a prime-order elliptic curve group.
+type Group struct {
+	n        *bigmod.Modulus
+	nMinus2  []byte
+	newPoint func() point
+}
+
+// point is the common interface of the point implementations, the arguments
+// are always points of the same implementation.
+type point interface {
+	setGenerator()
+	setBytes(b []byte) error
+	bytes() []byte
+	bytesCompressed() []byte
+	add(p1, p2 point)
+	scalarMult(q point, k []byte)
+	scalarBaseMult(k []byte)
+}
+
+var (
+	// SM2 is the group of the SM2 curve.
+	SM2 = newGroup(sm2ec.P256(), newSM2Point)
+	// P256 is the group of the NIST P-256 curve.
+	P256 = newGroup(elliptic.P256(), newP256Point)
+)
+
+func newGroup(curve elliptic.Curve, newPoint func() point) *Group {
+	params := curve.Params()
+	n, err := bigmod.NewModulus(params.N.Bytes())
+	if err != nil {
+		panic(err)
+	}
+	nMinus2 := new(big.Int).Sub(params.N, big.NewInt(2))
+	return &Group{n: n, nMinus2: nMinus2.Bytes(), newPoint: newPoint}
+}
+
+// Order returns the order of the group.
+func (g *Group) Order() *bigmod.Modulus {
+	return g.n
+}
+
+// ScalarSize returns the size in bytes of the encoding of a scalar.
+func (g *Group) ScalarSize() int {
+	return g.n.Size()
+}
+
+// Identity returns a new element set to the identity.
+func (g *Group) Identity() *Element {
+	return &Element{g: g, p: g.newPoint()}
+}
+
+// Generator returns a new element set to the generator.
+func (g *Group) Generator() *Element {
+	e := g.Identity()
+	e.p.setGenerator()
+	return e
+}
+
+// ScalarBaseMult returns k * G, where G is the generator.
+func (g *Group) ScalarBaseMult(k *bigmod.Nat) *Element {
+	r := g.Identity()
+	r.p.scalarBaseMult(k.Bytes(g.n))
+	return r
+}
+
+// DecodeCompressed decodes a compressed element, it rejects the identity and
+// the points which are not on the curve.
+func (g *Group) DecodeCompressed(b []byte) (*Element, error) {
+	if len(b) != 1+g.n.Size() || (b[0] != 2 && b[0] != 3) {
+		return nil, errors.New("ecgroup: invalid element encoding")
+	}
+	return g.decode(b)
+}
+
+// DecodeUncompressed decodes an uncompressed element, it rejects the identity
+// and the points which are not on the curve.
+func (g *Group) DecodeUncompressed(b []byte) (*Element, error) {
+	if len(b) != 1+2*g.n.Size() || b[0] != 4 {
+		return nil, errors.New("ecgroup: invalid element encoding")
+	}
+	return g.decode(b)
+}
+
+func (g *Group) decode(b []byte) (*Element, error) {
+	e := g.Identity()
+	if err := e.p.setBytes(b); err != nil {
+		return nil, errors.New("ecgroup: invalid element")
+	}
+	return e, nil
+}
+
+// RandomScalar returns a uniformly random non-zero scalar, by rejection
+// sampling.
+func (g *Group) RandomScalar(rand io.Reader) (*bigmod.Nat, error) {
+	b := make([]byte, g.n.Size())
+	k := bigmod.NewNat()
+	for {
+		if _, err := io.ReadFull(rand, b); err != nil {
+			return nil, err
+		}
+		if _, err := k.SetBytes(b, g.n); err == nil && k.IsZero() == 0 {
+			return k, nil
+		}
+	}
+}
+
+// Invert returns 1/k mod n, by exponentiation with n - 2 in constant time.
+func (g *Group) Invert(k *bigmod.Nat) *bigmod.Nat {
+	return bigmod.NewNat().Exp(k, g.nMinus2, g.n)
+}
+
+// Element is an element of a Group. The operations return new elements and
+// leave their arguments unchanged.
+type Element struct {
+	g *Group
+	p point
+}
+
+// IsIdentity reports whether e is the identity.
+func (e *Element) IsIdentity() bool {
+	return len(e.p.bytes()) == 1
+}
+
+// Bytes returns the uncompressed encoding of e, or the single byte 0 if e is
+// the identity.
+func (e *Element) Bytes() []byte {
+	return e.p.bytes()
+}
+
+// BytesCompressed returns the compressed encoding of e, or the single byte 0
+// if e is the identity.
+func (e *Element) BytesCompressed() []byte {
+	return e.p.bytesCompressed()
+}
+
+// Add returns e + q.
+func (e *Element) Add(q *Element) *Element {
+	r := e.g.Identity()
+	r.p.add(e.p, q.p)
+	return r
+}
+
+// ScalarMult returns k * e.
+func (e *Element) ScalarMult(k *bigmod.Nat) *Element {
+	r := e.g.Identity()
+	r.p.scalarMult(e.p, k.Bytes(e.g.n))
+	return r
+}
+
+type sm2Point struct {
+	p *sm2ec.Point
+}
+
+func newSM2Point() point {
+	return &sm2Point{sm2ec.NewPoint()}
+}
+
+func (p *sm2Point) setGenerator() {
+	p.p.SetGenerator()
+}
+
+func (p *sm2Point) setBytes(b []byte) error {
+	_, err := p.p.SetBytes(b)
+	return err
+}
+
+func (p *sm2Point) bytes() []byte {
+	return p.p.Bytes()
+}
+
+func (p *sm2Point) bytesCompressed() []byte {
+	return p.p.BytesCompressed()
+}
+
+func (p *sm2Point) add(p1, p2 point) {
+	p.p.Add(p1.(*sm2Point).p, p2.(*sm2Point).p)
+}
+
+func (p *sm2Point) scalarMult(q point, k []byte) {
+	if _, err := p.p.ScalarMult(q.(*sm2Point).p, k); err != nil {
+		// the scalar is always 32 bytes
+		panic("ecgroup: internal error: " + err.Error())
+	}
+}
+
+func (p *sm2Point) scalarBaseMult(k []byte) {
+	if _, err := p.p.ScalarBaseMult(k); err != nil {
+		panic("ecgroup: internal error: " + err.Error())
+	}
+}
+
+// p256Point is a point of the P-256 curve in affine coordinates, (0, 0) is
+// the point at infinity, as in package crypto/elliptic.
+type p256Point struct {
+	x, y *big.Int
+}
+
+func newP256Point() point {
+	return &p256Point{new(big.Int), new(big.Int)}
+}
+
+func (p *p256Point) setGenerator() {
+	params := elliptic.P256().Params()
+	p.x.Set(params.Gx)
+	p.y.Set(params.Gy)
+}
+
+func (p *p256Point) setBytes(b []byte) error {
+	var x, y *big.Int
+	if b[0] == 4 {
+		x, y = elliptic.Unmarshal(elliptic.P256(), b)
+	} else {
+		x, y = elliptic.UnmarshalCompressed(elliptic.P256(), b)
+	}
+	if x == nil {
+		return errors.New("ecgroup: invalid P-256 point")
+	}
+	p.x, p.y = x, y
+	return nil
+}
+
+func (p *p256Point) isInfinity() bool {
+	return p.x.Sign() == 0 && p.y.Sign() == 0
+}
+
+func (p *p256Point) bytes() []byte {
+	if p.isInfinity() {
+		return []byte{0}
+	}
+	return elliptic.Marshal(elliptic.P256(), p.x, p.y)
+}
+
+func (p *p256Point) bytesCompressed() []byte {
+	if p.isInfinity() {
+		return []byte{0}
+	}
+	return elliptic.MarshalCompressed(elliptic.P256(), p.x, p.y)
+}
+
+func (p *p256Point) add(p1, p2 point) {
+	q1, q2 := p1.(*p256Point), p2.(*p256Point)
+	p.x, p.y = elliptic.P256().Add(q1.x, q1.y, q2.x, q2.y)
+}
+
+func (p *p256Point) scalarMult(q point, k []byte) {
+	q1 := q.(*p256Point)
+	p.x, p.y = elliptic.P256().ScalarMult(q1.x, q1.y, k)
+}
+
+func (p *p256Point) scalarBaseMult(k []byte) {
+	p.x, p.y = elliptic.P256().ScalarBaseMult(k)
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ecgroup
+
+import (
+	"bytes"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/emmansun/gmsm/internal/bigmod"
+	"github.com/emmansun/gmsm/sm2/sm2ec"
+)
+
+var groups = []struct {
+	name  string
+	g     *Group
+	curve elliptic.Curve
+}{
+	{"SM2", SM2, sm2ec.P256()},
+	{"P256", P256, elliptic.P256()},
+}
+
+// TestGroup checks the operations against the elliptic.Curve of the group.
+func TestGroup(t *testing.T) {
+	for _, tc := range groups {
+		g, curve := tc.g, tc.curve
+		a, err := g.RandomScalar(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := g.RandomScalar(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		aBytes, bBytes := a.Bytes(g.Order()), b.Bytes(g.Order())
+		x1, y1 := curve.ScalarBaseMult(aBytes)
+		x2, y2 := curve.ScalarMult(x1, y1, bBytes)
+		x3, y3 := curve.Add(x1, y1, x2, y2)
+
+		A := g.ScalarBaseMult(a)
+		if !bytes.Equal(A.Bytes(), elliptic.Marshal(curve, x1, y1)) {
+			t.Errorf("%s: ScalarBaseMult mismatch", tc.name)
+		}
+		if !bytes.Equal(A.BytesCompressed(), elliptic.MarshalCompressed(curve, x1, y1)) {
+			t.Errorf("%s: BytesCompressed mismatch", tc.name)
+		}
+		B := A.ScalarMult(b)
+		if !bytes.Equal(B.Bytes(), elliptic.Marshal(curve, x2, y2)) {
+			t.Errorf("%s: ScalarMult mismatch", tc.name)
+		}
+		if !bytes.Equal(A.Add(B).Bytes(), elliptic.Marshal(curve, x3, y3)) {
+			t.Errorf("%s: Add mismatch", tc.name)
+		}
+		if !bytes.Equal(g.Generator().ScalarMult(a).Bytes(), A.Bytes()) {
+			t.Errorf("%s: Generator mismatch", tc.name)
+		}
+		// a * (1/a) * G = G
+		if !bytes.Equal(A.ScalarMult(g.Invert(a)).Bytes(), g.Generator().Bytes()) {
+			t.Errorf("%s: Invert mismatch", tc.name)
+		}
+		if A.IsIdentity() || !g.Identity().IsIdentity() {
+			t.Errorf("%s: IsIdentity mismatch", tc.name)
+		}
+		if !bytes.Equal(A.Add(g.Identity()).Bytes(), A.Bytes()) {
+			t.Errorf("%s: adding the identity changed the element", tc.name)
+		}
+		// a * G + (n - a) * G = O
+		negA := bigmod.NewNat().ExpandFor(g.Order()).Sub(a, g.Order())
+		if !A.Add(g.ScalarBaseMult(negA)).IsIdentity() {
+			t.Errorf("%s: A - A is not the identity", tc.name)
+		}
+
+		for _, enc := range [][]byte{A.Bytes(), A.BytesCompressed()} {
+			decode := g.DecodeUncompressed
+			if len(enc) != 1+2*g.ScalarSize() {
+				decode = g.DecodeCompressed
+			}
+			e, err := decode(enc)
+			if err != nil {
+				t.Fatalf("%s: %v", tc.name, err)
+			}
+			if !bytes.Equal(e.Bytes(), A.Bytes()) {
+				t.Errorf("%s: decoding mismatch", tc.name)
+			}
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	for _, tc := range groups {
+		g := tc.g
+		uncompressed := g.Generator().Bytes()
+		compressed := g.Generator().BytesCompressed()
+		notOnCurve := bytes.Clone(uncompressed)
+		notOnCurve[len(notOnCurve)-1] ^= 1
+		for _, b := range [][]byte{{0}, compressed, notOnCurve, uncompressed[:len(uncompressed)-1]} {
+			if _, err := g.DecodeUncompressed(b); err == nil {
+				t.Errorf("%s: expected error decoding %x", tc.name, b)
+			}
+		}
+		for _, b := range [][]byte{{0}, uncompressed, compressed[:len(compressed)-1], append([]byte{4}, compressed[1:]...)} {
+			if _, err := g.DecodeCompressed(b); err == nil {
+				t.Errorf("%s: expected error decoding %x", tc.name, b)
+			}
+		}
+	}
+}
@@ -606,6 +606,12 @@ func (q *SM2P256Point) Select(p1, p2 *SM2P256Point, cond int) *SM2P256Point {
 	return q
 }
 
+// Negate sets p to -p, if cond == 1, and to p if cond == 0.
+func (p *SM2P256Point) Negate(cond int) *SM2P256Point {
+	p256NegCond(&p.y, cond)
+	return p
+}
+
 // p256Inverse sets out to in⁻¹ mod p. If in is zero, out will be zero.
 func p256Inverse(out, in *p256Element) {
 	// Inversion is calculated through exponentiation by p - 2, per Fermat's
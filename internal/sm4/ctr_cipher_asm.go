@@ -5,18 +5,21 @@ package sm4
 import (
 	"crypto/cipher"
 	"crypto/subtle"
+	"math/bits"
 
 	"github.com/emmansun/gmsm/internal/alias"
+	"github.com/emmansun/gmsm/internal/byteorder"
 )
 
 // Assert that sm4CipherAsm implements the ctrAble interface.
 var _ ctrAble = (*sm4CipherAsm)(nil)
 
 type ctr struct {
-	b       *sm4CipherAsm
-	ctr     []byte
-	out     []byte
-	outUsed int
+	b          *sm4CipherAsm
+	ivlo, ivhi uint64 // start counter as 64-bit limbs
+	ctr        []byte
+	out        []byte
+	outUsed    int
 }
 
 const streamBufferSize = 512
@@ -33,6 +36,8 @@ func (c *sm4CipherAsm) NewCTR(iv []byte) cipher.Stream {
 	}
 	s := &ctr{
 		b:       c,
+		ivlo:    byteorder.BEUint64(iv[8:16]),
+		ivhi:    byteorder.BEUint64(iv[0:8]),
 		ctr:     make([]byte, c.blocksSize),
 		out:     make([]byte, 0, bufSize),
 		outUsed: 0,
@@ -44,7 +49,6 @@ func (c *sm4CipherAsm) NewCTR(iv []byte) cipher.Stream {
 	return s
 }
 
-
 func (x *ctr) genCtr(start int) {
 	if start >= BlockSize {
 		copy(x.ctr[start:], x.ctr[start-BlockSize:start])
@@ -96,3 +100,36 @@ func (x *ctr) XORKeyStream(dst, src []byte) {
 		x.outUsed += n
 	}
 }
+
+// XORKeyStreamAt behaves like XORKeyStream but keeps no state, and instead
+// seeks into the keystream by the given bytes offset from the start (ignoring
+// any XORKeyStream calls). The 128-bit counter wraps around silently.
+func (x *ctr) XORKeyStreamAt(dst, src []byte, offset uint64) {
+	if len(dst) < len(src) {
+		panic("cipher: output smaller than input")
+	}
+	dst = dst[:len(src)]
+	if alias.InexactOverlap(dst, src) {
+		panic("cipher: invalid buffer overlap")
+	}
+
+	ivlo, carry := bits.Add64(x.ivlo, offset/BlockSize, 0)
+	ivhi := x.ivhi + carry
+	blockOffset := int(offset % BlockSize)
+
+	ctrs := make([]byte, x.b.blocksSize)
+	keyStream := make([]byte, x.b.blocksSize)
+	for len(src) > 0 {
+		for i := 0; i < len(ctrs); i += BlockSize {
+			byteorder.BEPutUint64(ctrs[i:], ivhi)
+			byteorder.BEPutUint64(ctrs[i+8:], ivlo)
+			ivlo, carry = bits.Add64(ivlo, 1, 0)
+			ivhi += carry
+		}
+		encryptBlocksAsm(&x.b.enc[0], keyStream, ctrs, INST_AES)
+		n := subtle.XORBytes(dst, src, keyStream[blockOffset:])
+		blockOffset = 0
+		dst = dst[n:]
+		src = src[n:]
+	}
+}
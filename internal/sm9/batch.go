@@ -0,0 +1,46 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package sm9
+
+import "github.com/emmansun/gmsm/internal/sm9/bn256"
+
+// BatchVerifier verifies many signatures under the same signature master public key.
+//
+// A SM9 signature (h, S) is valid if h = H2(M || w) with w = e(S, P) * g^h, the
+// verifier has to compute every w exactly, so the pairings can NOT be merged with
+// random exponents like ECDSA/Schnorr batch verification with the commitment in
+// the signature. BatchVerifier instead shares the work which does not depend on
+// the signature:
+//   - the fixed-base tables of g, see [PreparedSignMasterPublicKey],
+//   - the user public keys P = H1(ID||hid)*P2 + Ppub-s and the line functions
+//     of the Miller loop of e(., P), which are computed once per identity, so
+//     the pairing of every further signature of the identity only evaluates
+//     the precomputed lines.
+//
+// Each identity holds about 15 KB of precomputed values. A BatchVerifier is not
+// safe for concurrent use.
+type BatchVerifier struct {
+	pub      *SignMasterPublicKey
+	userKeys map[string]*bn256.G2Precomputed
+}
+
+// NewBatchVerifier creates a BatchVerifier for signatures under pub.
+func (pub *SignMasterPublicKey) NewBatchVerifier() *BatchVerifier {
+	return &BatchVerifier{pub: pub, userKeys: make(map[string]*bn256.G2Precomputed)}
+}
+
+// Verify checks the validity of a signature, it is the same as
+// [SignMasterPublicKey.Verify] but reuses the precomputed user public keys.
+func (v *BatchVerifier) Verify(uid []byte, hid byte, hash, h, S []byte) bool {
+	return v.pub.verify(func(s *bn256.G1) *bn256.GT {
+		key := string(append(uid[:len(uid):len(uid)], hid))
+		p, ok := v.userKeys[key]
+		if !ok {
+			p = v.pub.GenerateUserPublicKey(uid, hid).Precompute()
+			v.userKeys[key] = p
+		}
+		return bn256.PairPrecomp(s, p)
+	}, hash, h, S)
+}
@@ -32,55 +32,30 @@ var g1GeneratorTableOnce sync.Once
 func (g *G1) generatorTable() *[32 * 2]curvePointTable {
 	g1GeneratorTableOnce.Do(func() {
 		g1GeneratorTable = new([32 * 2]curvePointTable)
-		base := NewCurveGenerator()
-		for i := 0; i < 32*2; i++ {
-			g1GeneratorTable[i][0] = &curvePoint{}
-			g1GeneratorTable[i][0].Set(base)
-
-			g1GeneratorTable[i][1] = &curvePoint{}
-			g1GeneratorTable[i][1].Double(g1GeneratorTable[i][0])
-			g1GeneratorTable[i][2] = &curvePoint{}
-			g1GeneratorTable[i][2].Add(g1GeneratorTable[i][1], base)
-
-			g1GeneratorTable[i][3] = &curvePoint{}
-			g1GeneratorTable[i][3].Double(g1GeneratorTable[i][1])
-			g1GeneratorTable[i][4] = &curvePoint{}
-			g1GeneratorTable[i][4].Add(g1GeneratorTable[i][3], base)
-
-			g1GeneratorTable[i][5] = &curvePoint{}
-			g1GeneratorTable[i][5].Double(g1GeneratorTable[i][2])
-			g1GeneratorTable[i][6] = &curvePoint{}
-			g1GeneratorTable[i][6].Add(g1GeneratorTable[i][5], base)
-
-			g1GeneratorTable[i][7] = &curvePoint{}
-			g1GeneratorTable[i][7].Double(g1GeneratorTable[i][3])
-			g1GeneratorTable[i][8] = &curvePoint{}
-			g1GeneratorTable[i][8].Add(g1GeneratorTable[i][7], base)
-
-			g1GeneratorTable[i][9] = &curvePoint{}
-			g1GeneratorTable[i][9].Double(g1GeneratorTable[i][4])
-			g1GeneratorTable[i][10] = &curvePoint{}
-			g1GeneratorTable[i][10].Add(g1GeneratorTable[i][9], base)
-
-			g1GeneratorTable[i][11] = &curvePoint{}
-			g1GeneratorTable[i][11].Double(g1GeneratorTable[i][5])
-			g1GeneratorTable[i][12] = &curvePoint{}
-			g1GeneratorTable[i][12].Add(g1GeneratorTable[i][11], base)
-
-			g1GeneratorTable[i][13] = &curvePoint{}
-			g1GeneratorTable[i][13].Double(g1GeneratorTable[i][6])
-			g1GeneratorTable[i][14] = &curvePoint{}
-			g1GeneratorTable[i][14].Add(g1GeneratorTable[i][13], base)
-
-			base.Double(base)
-			base.Double(base)
-			base.Double(base)
-			base.Double(base)
-		}
+		fillCurvePointTables(g1GeneratorTable, NewCurveGenerator())
 	})
 	return g1GeneratorTable
 }
 
+// fillCurvePointTables fills tables with the multiples of base, tables[i]
+// holds [1..15]*(2^(4i))*base. base is modified.
+func fillCurvePointTables(tables *[32 * 2]curvePointTable, base *curvePoint) {
+	for i := 0; i < 32*2; i++ {
+		tables[i][0] = &curvePoint{}
+		tables[i][0].Set(base)
+		for j := 1; j < 15; j += 2 {
+			tables[i][j] = &curvePoint{}
+			tables[i][j].Double(tables[i][j/2])
+			tables[i][j+1] = &curvePoint{}
+			tables[i][j+1].Add(tables[i][j], base)
+		}
+		base.Double(base)
+		base.Double(base)
+		base.Double(base)
+		base.Double(base)
+	}
+}
+
 // RandomG1 returns x and g₁ˣ where x is a random, non-zero number read from r.
 func RandomG1(r io.Reader) (*big.Int, *G1, error) {
 	k, err := randomK(r)
@@ -120,7 +95,12 @@ func (e *G1) ScalarBaseMult(scalar []byte) (*G1, error) {
 
 	//e.p.Mul(curveGen, k)
 
-	tables := e.generatorTable()
+	return e.scalarMultTables(e.generatorTable(), scalar), nil
+}
+
+// scalarMultTables sets e to scalar*P where tables are the fixed-base tables
+// of P and then returns e. scalar must be 32 bytes long.
+func (e *G1) scalarMultTables(tables *[32 * 2]curvePointTable, scalar []byte) *G1 {
 	// This is also a scalar multiplication with a four-bit window like in
 	// ScalarMult, but in this case the doublings are precomputed. The value
 	// [windowValue]G added at iteration k would normally get doubled
@@ -140,7 +120,7 @@ func (e *G1) ScalarBaseMult(scalar []byte) (*G1, error) {
 		e.p.Add(e.p, t)
 		tableIndex--
 	}
-	return e, nil
+	return e
 }
 
 // ScalarMult sets e to a*k and then returns e.
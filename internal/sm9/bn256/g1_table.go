@@ -0,0 +1,31 @@
+package bn256
+
+import "errors"
+
+// G1Table is a precomputed table for fixed-base scalar multiplication of
+// an arbitrary G1 point, e.g. a long-lived public or private key.
+// It trades about 90KB of memory for avoiding all doublings.
+type G1Table struct {
+	tables [32 * 2]curvePointTable
+}
+
+// NewG1Table computes the fixed-base table of p.
+func NewG1Table(p *G1) *G1Table {
+	t := new(G1Table)
+	base := &curvePoint{}
+	base.Set(p.p)
+	fillCurvePointTables(&t.tables, base)
+	return t
+}
+
+// ScalarMultTable sets e to scalar*P, where table is the fixed-base table of P,
+// and then returns e.
+func (e *G1) ScalarMultTable(table *G1Table, scalar []byte) (*G1, error) {
+	if len(scalar) != 32 {
+		return nil, errors.New("invalid scalar length")
+	}
+	if e.p == nil {
+		e.p = &curvePoint{}
+	}
+	return e.scalarMultTables(&table.tables, scalar), nil
+}
@@ -0,0 +1,46 @@
+package bn256
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+)
+
+func TestG1Table(t *testing.T) {
+	_, p, err := RandomG1(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := NewG1Table(p)
+	for i := 0; i < 10; i++ {
+		k, _, _ := RandomG1(rand.Reader)
+		scalar := NormalizeScalar(k.Bytes())
+		want, err := new(G1).ScalarMult(p, scalar)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := new(G1).ScalarMultTable(table, scalar)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got.Marshal(), want.Marshal()) {
+			t.Errorf("ScalarMultTable mismatch")
+		}
+	}
+	if _, err := new(G1).ScalarMultTable(table, []byte{1}); err == nil {
+		t.Error("expected error for invalid scalar length")
+	}
+}
+
+func BenchmarkG1ScalarMultTable(b *testing.B) {
+	_, p, _ := RandomG1(rand.Reader)
+	k, _, _ := RandomG1(rand.Reader)
+	scalar := NormalizeScalar(k.Bytes())
+	table := NewG1Table(p)
+	e := new(G1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		e.ScalarMultTable(table, scalar)
+	}
+}
@@ -0,0 +1,140 @@
+package bn256
+
+import (
+	"math/big"
+
+	"github.com/emmansun/gmsm/internal/sm3"
+)
+
+// twistCofactor is the cofactor of G2 in the twist group E'(Fp²): 2p-N.
+var twistCofactor = new(big.Int).Sub(new(big.Int).Lsh(p, 1), Order)
+
+const maxDSTLength = 255
+
+// expandMessage expands msg to n bytes with SM3, ctr is the try-and-increment
+// counter. The output is
+//
+//	SM3(1 || ctr || len(dst) || dst || msg) || SM3(2 || ctr || len(dst) || dst || msg) || ...
+//
+// where the integers are encoded in one byte.
+func expandMessage(msg, dst []byte, ctr byte, n int) []byte {
+	if len(dst) > maxDSTLength {
+		h := sm3.New()
+		h.Write([]byte("H2C-OVERSIZE-DST-"))
+		h.Write(dst)
+		dst = h.Sum(nil)
+	}
+	out := make([]byte, 0, n+sm3.Size)
+	h := sm3.New()
+	for i := 1; len(out) < n; i++ {
+		h.Reset()
+		h.Write([]byte{byte(i), ctr, byte(len(dst))})
+		h.Write(dst)
+		h.Write(msg)
+		out = h.Sum(out)
+	}
+	return out[:n]
+}
+
+// fieldElement reduces the 64 bytes b modulo p, the bias is negligible.
+func fieldElement(b []byte) *gfP {
+	var buf [32]byte
+	k := new(big.Int).SetBytes(b)
+	k.Mod(k, p)
+	return newGFpFromBytes(k.FillBytes(buf[:]))
+}
+
+// HashToG1 hashes msg with the domain separation tag dst to a point of G1.
+//
+// It uses try-and-increment with SM3. It is not one of the hash-to-curve
+// suites of RFC 9380, which defines none for the SM9 curve, although its
+// Shallue-van de Woestijne map (section 6.6.1) applies to it. The number of
+// iterations, and so the running time, depends on msg and dst, which must
+// not be secret.
+func HashToG1(msg, dst []byte) *G1 {
+	const numBytes = 256 / 8
+	for ctr := 0; ; ctr++ {
+		u := expandMessage(msg, dst, byte(ctr), 2*numBytes+1)
+		c := &curvePoint{}
+		c.x.Set(fieldElement(u[:2*numBytes]))
+		if !Sqrt(&c.y, c.polynomial(&c.x)) {
+			continue
+		}
+		y := &gfP{}
+		montDecode(y, &c.y)
+		if byte(y[0]&1) != u[2*numBytes]&1 {
+			gfpNeg(&c.y, &c.y)
+		}
+		c.z.Set(one)
+		c.t.Set(one)
+		// the order of E(Fp) is N, no cofactor clearing is needed.
+		return &G1{c}
+	}
+}
+
+// HashToG2 hashes msg with the domain separation tag dst to a point of G2.
+//
+// It uses try-and-increment with SM3 and clears the cofactor 2p-N of the
+// twist. As for [HashToG1], the running time depends on msg and dst, which
+// must not be secret.
+func HashToG2(msg, dst []byte) *G2 {
+	const numBytes = 256 / 8
+	for ctr := 0; ; ctr++ {
+		u := expandMessage(msg, dst, byte(ctr), 4*numBytes+1)
+		c := &twistPoint{}
+		c.x.x.Set(fieldElement(u[:2*numBytes]))
+		c.x.y.Set(fieldElement(u[2*numBytes : 4*numBytes]))
+		y2 := c.polynomial(&c.x)
+		c.y.Sqrt(y2)
+		if (&gfP2{}).Square(&c.y).Equal(y2) != 1 {
+			continue
+		}
+		y := &gfP{}
+		montDecode(y, &c.y.y)
+		if byte(y[0]&1) != u[4*numBytes]&1 {
+			c.y.Neg(&c.y)
+		}
+		c.z.SetOne()
+		c.t.SetOne()
+		c.Mul(c, twistCofactor)
+		if c.IsInfinity() {
+			continue
+		}
+		return &G2{c}
+	}
+}
+
+// IsInSubgroup reports whether e is in the subgroup of order N. Points read by
+// Unmarshal and UnmarshalCompressed are only checked to be on the twist.
+func (e *G2) IsInSubgroup() bool {
+	if e.p == nil {
+		return true
+	}
+	c := &twistPoint{}
+	c.Mul(e.p, Order)
+	return c.IsInfinity()
+}
+
+// IsInSubgroup reports whether e is in the subgroup of order N of Fp¹². It
+// uses a generic exponentiation, because the cyclotomic squarings used by
+// ScalarMult are only valid for the elements of the subgroup.
+func (e *GT) IsInSubgroup() bool {
+	if e.p == nil {
+		return false
+	}
+	return (&gfP12{}).Exp(e.p, Order).IsOne()
+}
+
+// Invert sets e to the inverse of a and then returns e.
+func (e *GT) Invert(a *GT) *GT {
+	if e.p == nil {
+		e.p = &gfP12{}
+	}
+	e.p.Invert(a.p)
+	return e
+}
+
+// IsOne returns true if e is the identity of GT.
+func (e *GT) IsOne() bool {
+	return e.p != nil && e.p.IsOne()
+}
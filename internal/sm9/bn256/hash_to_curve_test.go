@@ -0,0 +1,78 @@
+package bn256
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHashToG1(t *testing.T) {
+	dst := []byte("SM9-BN256-H2G1-TEST")
+	p1 := HashToG1([]byte("abc"), dst)
+	if !p1.IsOnCurve() || p1.IsInfinity() {
+		t.Fatal("hash is not a point of G1")
+	}
+	if p2 := HashToG1([]byte("abc"), dst); !bytes.Equal(p1.Marshal(), p2.Marshal()) {
+		t.Fatal("hash is not deterministic")
+	}
+	if p2 := HashToG1([]byte("abd"), dst); bytes.Equal(p1.Marshal(), p2.Marshal()) {
+		t.Fatal("different messages hash to the same point")
+	}
+	if p2 := HashToG1([]byte("abc"), bytes.Repeat(dst, 20)); bytes.Equal(p1.Marshal(), p2.Marshal()) {
+		t.Fatal("different tags hash to the same point")
+	}
+	c := &curvePoint{}
+	c.Mul(p1.p, Order)
+	if !c.IsInfinity() {
+		t.Fatal("hash is not in the subgroup")
+	}
+}
+
+func TestHashToG2(t *testing.T) {
+	dst := []byte("SM9-BN256-H2G2-TEST")
+	for _, msg := range []string{"", "abc", "abcdef0123456789"} {
+		q := HashToG2([]byte(msg), dst)
+		if !q.IsOnCurve() || q.IsInfinity() || !q.IsInSubgroup() {
+			t.Fatalf("hash of %q is not a point of G2", msg)
+		}
+		if q2 := HashToG2([]byte(msg), dst); !bytes.Equal(q.Marshal(), q2.Marshal()) {
+			t.Fatal("hash is not deterministic")
+		}
+	}
+}
+
+func TestSubgroupChecks(t *testing.T) {
+	// a point of the twist out of G2
+	c := &twistPoint{}
+	for ctr := byte(0); ; ctr++ {
+		u := expandMessage([]byte("twist"), nil, ctr, 128)
+		c.x.x.Set(fieldElement(u[:64]))
+		c.x.y.Set(fieldElement(u[64:]))
+		y2 := c.polynomial(&c.x)
+		c.y.Sqrt(y2)
+		if (&gfP2{}).Square(&c.y).Equal(y2) == 1 {
+			break
+		}
+	}
+	c.z.SetOne()
+	c.t.SetOne()
+	if (&G2{c}).IsInSubgroup() {
+		t.Fatal("point out of G2 reported in the subgroup")
+	}
+	if !Gen2.IsInSubgroup() {
+		t.Fatal("generator reported out of the subgroup")
+	}
+
+	g := Pair(Gen1, Gen2)
+	if !g.IsInSubgroup() {
+		t.Fatal("pairing output reported out of the subgroup")
+	}
+	inv := new(GT).Invert(g)
+	if !new(GT).Add(g, inv).IsOne() {
+		t.Fatal("g * g^-1 != 1")
+	}
+	two := (&gfP12{}).SetOne()
+	two.z.x.y.Set(newGFp(2))
+	if (&GT{two}).IsInSubgroup() {
+		t.Fatal("2 reported in the subgroup")
+	}
+}
@@ -169,6 +169,7 @@ func (c *twistPoint) Double(p *twistPoint) {
 
 func (c *twistPoint) Mul(a *twistPoint, scalar *big.Int) {
 	sum, t := &twistPoint{}, &twistPoint{}
+	sum.SetInfinity()
 
 	for i := scalar.BitLen(); i >= 0; i-- {
 		t.Double(sum)
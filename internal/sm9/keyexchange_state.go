@@ -0,0 +1,143 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package sm9
+
+import (
+	"errors"
+	"time"
+
+	"github.com/emmansun/gmsm/internal/bigmod"
+	"github.com/emmansun/gmsm/internal/sm9/bn256"
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/cryptobyte/asn1"
+)
+
+const keyExchangeStateVersion = 1
+
+var errKeyExchangeState = errors.New("sm9: invalid key exchange state")
+
+// MarshalState encodes the intermediate state of an initiator waiting for RB,
+// or a responder waiting for SA, so that the key exchange can be completed by
+// another instance. The state expires after notAfter.
+//
+// The state contains the ephemeral private key and must be kept confidential,
+// the owner's private key is not included.
+//
+//	SM9KeyExchangeState ::= SEQUENCE {
+//	    version      INTEGER,     -- 1
+//	    step         INTEGER,     -- 1 initiated, 2 responded
+//	    notAfter     INTEGER,     -- seconds since the Unix epoch
+//	    genSignature BOOLEAN,
+//	    keyLength    INTEGER,
+//	    uid          OCTET STRING,
+//	    peerUID      OCTET STRING,
+//	    r            OCTET STRING,
+//	    secret       OCTET STRING,
+//	    -- the responder's state only
+//	    peerSecret   OCTET STRING,
+//	    g1           OCTET STRING,
+//	    g2           OCTET STRING,
+//	    g3           OCTET STRING
+//	}
+func (ke *KeyExchange) MarshalState(notAfter time.Time) ([]byte, error) {
+	if ke.step != keyExchangeInitiated && ke.step != keyExchangeResponded {
+		return nil, ErrKeyExchangeState
+	}
+	var b cryptobyte.Builder
+	b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1Int64(keyExchangeStateVersion)
+		b.AddASN1Int64(int64(ke.step))
+		b.AddASN1Int64(notAfter.Unix())
+		b.AddASN1Boolean(ke.genSignature)
+		b.AddASN1Int64(int64(ke.keyLength))
+		b.AddASN1OctetString(ke.uid)
+		b.AddASN1OctetString(ke.peerUID)
+		b.AddASN1OctetString(ke.r.Bytes(orderNat))
+		b.AddASN1OctetString(ke.secret)
+		if ke.step == keyExchangeResponded {
+			b.AddASN1OctetString(ke.peerSecret)
+			b.AddASN1OctetString(ke.g1.Marshal())
+			b.AddASN1OctetString(ke.g2.Marshal())
+			b.AddASN1OctetString(ke.g3.Marshal())
+		}
+	})
+	return b.Bytes()
+}
+
+// UnmarshalKeyExchangeState restores a key exchange of priv from the state
+// encoded by [KeyExchange.MarshalState], it returns [ErrKeyExchangeExpired]
+// if the state has expired at now.
+func (priv *EncryptPrivateKey) UnmarshalKeyExchangeState(state []byte, now time.Time) (*KeyExchange, error) {
+	var (
+		inner                   cryptobyte.String
+		version, step, notAfter int64
+		keyLength               int64
+		genSignature            bool
+		uid, peerUID, r, secret []byte
+		peerSecret, g1, g2, g3  []byte
+	)
+	input := cryptobyte.String(state)
+	if !input.ReadASN1(&inner, asn1.SEQUENCE) ||
+		!input.Empty() ||
+		!inner.ReadASN1Int64WithTag(&version, asn1.INTEGER) ||
+		version != keyExchangeStateVersion ||
+		!inner.ReadASN1Int64WithTag(&step, asn1.INTEGER) ||
+		!inner.ReadASN1Int64WithTag(&notAfter, asn1.INTEGER) ||
+		!inner.ReadASN1Boolean(&genSignature) ||
+		!inner.ReadASN1Int64WithTag(&keyLength, asn1.INTEGER) ||
+		!inner.ReadASN1Bytes(&uid, asn1.OCTET_STRING) ||
+		!inner.ReadASN1Bytes(&peerUID, asn1.OCTET_STRING) ||
+		!inner.ReadASN1Bytes(&r, asn1.OCTET_STRING) ||
+		!inner.ReadASN1Bytes(&secret, asn1.OCTET_STRING) {
+		return nil, errKeyExchangeState
+	}
+	ke := priv.NewKeyExchange(uid, peerUID, int(keyLength), genSignature)
+	ke.step = keyExchangeStep(step)
+	switch ke.step {
+	case keyExchangeInitiated:
+	case keyExchangeResponded:
+		if !inner.ReadASN1Bytes(&peerSecret, asn1.OCTET_STRING) ||
+			!inner.ReadASN1Bytes(&g1, asn1.OCTET_STRING) ||
+			!inner.ReadASN1Bytes(&g2, asn1.OCTET_STRING) ||
+			!inner.ReadASN1Bytes(&g3, asn1.OCTET_STRING) {
+			return nil, errKeyExchangeState
+		}
+		ke.peerSecret = peerSecret
+		var err error
+		if ke.g1, err = unmarshalGT(g1); err != nil {
+			return nil, err
+		}
+		if ke.g2, err = unmarshalGT(g2); err != nil {
+			return nil, err
+		}
+		if ke.g3, err = unmarshalGT(g3); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errKeyExchangeState
+	}
+	if !inner.Empty() || keyLength <= 0 {
+		return nil, errKeyExchangeState
+	}
+	if !now.Before(time.Unix(notAfter, 0)) {
+		return nil, ErrKeyExchangeExpired
+	}
+	k, err := bigmod.NewNat().SetBytes(r, orderNat)
+	if err != nil || k.IsZero() == 1 {
+		return nil, errKeyExchangeState
+	}
+	ke.r = k
+	ke.secret = secret
+	return ke, nil
+}
+
+func unmarshalGT(b []byte) (*bn256.GT, error) {
+	gt := new(bn256.GT)
+	rest, err := gt.Unmarshal(b)
+	if err != nil || len(rest) != 0 {
+		return nil, errKeyExchangeState
+	}
+	return gt, nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package sm9
+
+import (
+	"bytes"
+	"runtime"
+	"sync"
+	"weak"
+
+	"github.com/emmansun/gmsm/internal/cache"
+	"github.com/emmansun/gmsm/internal/sm9/bn256"
+)
+
+// PreparedSignMasterPublicKey holds the values which only depend on a signature
+// master public key and are expensive to compute:
+//   - g = e(P1, Ppub-s), used by every signature generation and verification,
+//   - the fixed-base tables of g, which turn g^r into table lookups and multiplications.
+//
+// The values are computed on first use, and shared by all the keys with the same
+// master public key.
+type PreparedSignMasterPublicKey struct {
+	q         []byte
+	pub       *bn256.G2
+	pairOnce  sync.Once
+	basePoint *bn256.GT
+	tableOnce sync.Once
+	table     *[32 * 2]bn256.GTFieldTable
+}
+
+// PreparedEncryptMasterPublicKey holds the values which only depend on an
+// encryption master public key and are expensive to compute:
+//   - g = e(Ppub-e, P2), used by every key encapsulation,
+//   - the fixed-base tables of g,
+//   - the fixed-base table of Ppub-e, so that r*(H1(ID||hid)*P1 + Ppub-e)
+//     is computed with two fixed-base scalar multiplications.
+//
+// The values are computed on first use, and shared by all the keys with the same
+// master public key.
+type PreparedEncryptMasterPublicKey struct {
+	q            []byte
+	pub          *bn256.G1
+	pairOnce     sync.Once
+	basePoint    *bn256.GT
+	tableOnce    sync.Once
+	table        *[32 * 2]bn256.GTFieldTable
+	pubTableOnce sync.Once
+	pubTable     *bn256.G1Table
+}
+
+// Pair returns g = e(P1, Ppub-s).
+func (p *PreparedSignMasterPublicKey) Pair() *bn256.GT {
+	p.pairOnce.Do(func() {
+		p.basePoint = bn256.PairPrecomp(bn256.Gen1, p.pub.Precompute())
+	})
+	return p.basePoint
+}
+
+// ScalarBaseMult computes g^scalar with the precomputed tables.
+func (p *PreparedSignMasterPublicKey) ScalarBaseMult(scalar []byte) (*bn256.GT, error) {
+	p.tableOnce.Do(func() {
+		p.table = bn256.GenerateGTFieldTable(p.Pair())
+	})
+	return bn256.ScalarBaseMultGT(p.table, scalar)
+}
+
+// Precompute computes all the cached values now instead of on first use.
+func (p *PreparedSignMasterPublicKey) Precompute() {
+	p.ScalarBaseMult(make([]byte, len(bn256.OrderBytes)))
+}
+
+// Pair returns g = e(Ppub-e, P2).
+func (p *PreparedEncryptMasterPublicKey) Pair() *bn256.GT {
+	p.pairOnce.Do(func() {
+		p.basePoint = bn256.PairPrecomp(p.pub, gen2Precomp)
+	})
+	return p.basePoint
+}
+
+// ScalarBaseMult computes g^scalar with the precomputed tables.
+func (p *PreparedEncryptMasterPublicKey) ScalarBaseMult(scalar []byte) (*bn256.GT, error) {
+	p.tableOnce.Do(func() {
+		p.table = bn256.GenerateGTFieldTable(p.Pair())
+	})
+	return bn256.ScalarBaseMultGT(p.table, scalar)
+}
+
+// ScalarMultPublic computes scalar*Ppub-e with the precomputed table.
+func (p *PreparedEncryptMasterPublicKey) ScalarMultPublic(scalar []byte) (*bn256.G1, error) {
+	p.pubTableOnce.Do(func() {
+		p.pubTable = bn256.NewG1Table(p.pub)
+	})
+	return new(bn256.G1).ScalarMultTable(p.pubTable, scalar)
+}
+
+// Precompute computes all the cached values now instead of on first use.
+func (p *PreparedEncryptMasterPublicKey) Precompute() {
+	p.ScalarBaseMult(make([]byte, len(bn256.OrderBytes)))
+	p.ScalarMultPublic(make([]byte, len(bn256.OrderBytes)))
+}
+
+var (
+	signMasterCache    cache.Cache[bn256.G2, PreparedSignMasterPublicKey]
+	encryptMasterCache cache.Cache[bn256.G1, PreparedEncryptMasterPublicKey]
+
+	signMasterPoints    internTable[bn256.G2]
+	encryptMasterPoints internTable[bn256.G1]
+)
+
+// Prepared returns the prepared form of the signature master public key.
+//
+// The prepared key holds a copy of the point: a reference to the point, which
+// is the key of the cache, would keep it reachable and the entry would never be
+// evicted.
+func (pub *SignMasterPublicKey) Prepared() *PreparedSignMasterPublicKey {
+	p, _ := signMasterCache.Get(pub.MasterPublicKey, func() (*PreparedSignMasterPublicKey, error) {
+		return &PreparedSignMasterPublicKey{q: pub.q, pub: new(bn256.G2).Set(pub.MasterPublicKey)}, nil
+	}, func(p *PreparedSignMasterPublicKey) bool {
+		return bytes.Equal(p.q, pub.q)
+	})
+	return p
+}
+
+// Prepared returns the prepared form of the encryption master public key, see
+// [SignMasterPublicKey.Prepared].
+func (pub *EncryptMasterPublicKey) Prepared() *PreparedEncryptMasterPublicKey {
+	p, _ := encryptMasterCache.Get(pub.MasterPublicKey, func() (*PreparedEncryptMasterPublicKey, error) {
+		return &PreparedEncryptMasterPublicKey{q: pub.q, pub: new(bn256.G1).Set(pub.MasterPublicKey)}, nil
+	}, func(p *PreparedEncryptMasterPublicKey) bool {
+		return bytes.Equal(p.q, pub.q)
+	})
+	return p
+}
+
+// internTable maps the encoding of a master public key to the live point
+// object with that value, so that keys which are parsed or created separately
+// share the same cache entry.
+type internTable[T any] struct {
+	m sync.Map // string -> weak.Pointer[T]
+}
+
+// intern returns the live point with encoding q if any, otherwise it
+// registers and returns v.
+func (t *internTable[T]) intern(q []byte, v *T) *T {
+	key := string(q)
+	wp := weak.Make(v)
+	for {
+		actual, loaded := t.m.LoadOrStore(key, wp)
+		if !loaded {
+			runtime.AddCleanup(v, t.evict, internEntry[T]{key, wp})
+			return v
+		}
+		if old := actual.(weak.Pointer[T]).Value(); old != nil {
+			return old
+		}
+		// The previous point has been collected, replace the stale entry.
+		t.m.CompareAndDelete(key, actual)
+	}
+}
+
+type internEntry[T any] struct {
+	key string
+	wp  weak.Pointer[T]
+}
+
+func (t *internTable[T]) evict(e internEntry[T]) {
+	t.m.CompareAndDelete(e.key, e.wp)
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package sm9
+
+import (
+	"bytes"
+	"crypto/rand"
+	"runtime"
+	"testing"
+	"weak"
+
+	"github.com/emmansun/gmsm/internal/sm9/bn256"
+)
+
+func TestPreparedSharedByValue(t *testing.T) {
+	signMaster, err := GenerateSignMasterKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub1 := new(SignMasterPublicKey)
+	if err := pub1.UnmarshalRaw(bytes.Clone(signMaster.PublicKey().Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	pub2 := new(SignMasterPublicKey)
+	if err := pub2.UnmarshalRaw(signMaster.PublicKey().Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if pub1.Prepared() != signMaster.PublicKey().Prepared() || pub2.Prepared() != pub1.Prepared() {
+		t.Error("signature master public keys with the same value do not share the prepared key")
+	}
+	want := bn256.Pair(bn256.Gen1, signMaster.MasterPublicKey)
+	if !bytes.Equal(pub1.Prepared().Pair().Marshal(), want.Marshal()) {
+		t.Error("unexpected pairing value")
+	}
+
+	encMaster, err := GenerateEncryptMasterKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub3 := new(EncryptMasterPublicKey)
+	if err := pub3.UnmarshalRaw(encMaster.PublicKey().Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if pub3.Prepared() != encMaster.PublicKey().Prepared() {
+		t.Error("encryption master public keys with the same value do not share the prepared key")
+	}
+	want = bn256.Pair(encMaster.MasterPublicKey, bn256.Gen2)
+	if !bytes.Equal(pub3.Prepared().Pair().Marshal(), want.Marshal()) {
+		t.Error("unexpected pairing value")
+	}
+}
+
+// preparedOfNewKeys returns weak pointers to the prepared forms of new master
+// public keys, which are unreachable when it returns.
+func preparedOfNewKeys(t *testing.T) (weak.Pointer[PreparedSignMasterPublicKey], weak.Pointer[PreparedEncryptMasterPublicKey]) {
+	signMaster, err := GenerateSignMasterKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encMaster, err := GenerateEncryptMasterKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return weak.Make(signMaster.PublicKey().Prepared()), weak.Make(encMaster.PublicKey().Prepared())
+}
+
+func TestPreparedEvicted(t *testing.T) {
+	signPrepared, encPrepared := preparedOfNewKeys(t)
+	// the first cycle collects the keys and runs the cleanups which delete
+	// the entries, the next one collects the prepared keys
+	for i := 0; i < 10 && (signPrepared.Value() != nil || encPrepared.Value() != nil); i++ {
+		runtime.GC()
+	}
+	if signPrepared.Value() != nil {
+		t.Error("the prepared signature master public key is not evicted")
+	}
+	if encPrepared.Value() != nil {
+		t.Error("the prepared encryption master public key is not evicted")
+	}
+}
+
+func TestScalarMultUserPublicKey(t *testing.T) {
+	master, err := GenerateEncryptMasterKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uid := []byte("emmansun")
+	r, err := randomScalar(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := master.scalarMultUserPublicKey(master.hashUserID(uid, 0x03), r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := new(bn256.G1).ScalarMult(master.GenerateUserPublicKey(uid, 0x03), r.Bytes(orderNat))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Marshal(), want.Marshal()) {
+		t.Error("scalarMultUserPublicKey mismatch")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"crypto"
 	goSubtle "crypto/subtle"
 	"errors"
+	"fmt"
 	"io"
 
 	"github.com/emmansun/gmsm/internal/bigmod"
@@ -139,6 +140,15 @@ func (priv *SignPrivateKey) Sign(rand io.Reader, hash []byte, opts crypto.Signer
 
 // Verify checks the validity of a signature using the provided parameters.
 func (pub *SignMasterPublicKey) Verify(uid []byte, hid byte, hash, h, S []byte) bool {
+	return pub.verify(func(s *bn256.G1) *bn256.GT {
+		return bn256.Pair(s, pub.GenerateUserPublicKey(uid, hid))
+	}, hash, h, S)
+}
+
+// verify checks the validity of a signature, pair computes e(S, P) with the
+// user public key P, it is only called once the signature has been parsed
+// successfully.
+func (pub *SignMasterPublicKey) verify(pair func(s *bn256.G1) *bn256.GT, hash, h, S []byte) bool {
 	sPoint := new(bn256.G1)
 	numBytes := 2 * len(bn256.OrderBytes)
 	if len(S) != numBytes+1 || S[0] != 4 {
@@ -157,10 +167,7 @@ func (pub *SignMasterPublicKey) Verify(uid []byte, hid byte, hash, h, S []byte)
 		return false
 	}
 
-	// user sign public key p generation
-	p := pub.GenerateUserPublicKey(uid, hid)
-
-	u := bn256.Pair(sPoint, p)
+	u := pair(sPoint)
 	w := new(bn256.GT).Add(u, t)
 
 	var buffer []byte
@@ -183,7 +190,7 @@ func (pub *SignMasterPublicKey) Verify(uid []byte, hid byte, hash, h, S []byte)
 // - A byte slice containing the uncompressed ciphertext.
 // - An error if any occurs during the key wrapping process.
 func (pub *EncryptMasterPublicKey) WrapKey(rand io.Reader, uid []byte, hid byte, kLen int) (key []byte, cipher []byte, err error) {
-	q := pub.GenerateUserPublicKey(uid, hid)
+	h1 := pub.hashUserID(uid, hid)
 	var (
 		r *bigmod.Nat
 		w *bn256.GT
@@ -195,13 +202,12 @@ func (pub *EncryptMasterPublicKey) WrapKey(rand io.Reader, uid []byte, hid byte,
 			return nil, nil, err
 		}
 
-		rBytes := r.Bytes(orderNat)
-		c, err = new(bn256.G1).ScalarMult(q, rBytes)
+		c, err = pub.scalarMultUserPublicKey(h1, r)
 		if err != nil {
 			return nil, nil, err
 		}
 
-		w, err = pub.ScalarBaseMult(rBytes)
+		w, err = pub.ScalarBaseMult(r.Bytes(orderNat))
 		if err != nil {
 			return nil, nil, err
 		}
@@ -252,10 +258,32 @@ func (priv *EncryptPrivateKey) UnwrapKey(uid, cipher []byte, kLen int) (key []by
 // It is deliberately vague to avoid adaptive attacks.
 var ErrDecryption = errors.New("sm9: decryption error")
 
+var (
+	// ErrKeyExchangeMessage is returned when a key exchange message of the peer is malformed.
+	ErrKeyExchangeMessage = errors.New("sm9: invalid key exchange message")
+	// ErrKeyExchangeConfirmation is returned when the confirmation of the peer does not match.
+	ErrKeyExchangeConfirmation = errors.New("sm9: key exchange confirmation failed")
+	// ErrKeyExchangeState is returned when a key exchange step is called out of order.
+	ErrKeyExchangeState = errors.New("sm9: key exchange step out of order")
+	// ErrKeyExchangeExpired is returned when a saved key exchange state has expired.
+	ErrKeyExchangeExpired = errors.New("sm9: key exchange state expired")
+)
+
+// keyExchangeStep is the progress of a key exchange.
+type keyExchangeStep int
+
+const (
+	keyExchangeNew       keyExchangeStep = iota
+	keyExchangeInitiated                 // initiator, waiting for RB
+	keyExchangeResponded                 // responder, waiting for SA
+	keyExchangeCompleted
+)
+
 // KeyExchange represents key exchange struct, include internal stat in whole key exchange flow.
 // Initiator's flow will be: NewKeyExchange -> InitKeyExchange -> transmission -> ConfirmResponder
 // Responder's flow will be: NewKeyExchange -> waiting ... -> RepondKeyExchange -> transmission -> ConfirmInitiator
 type KeyExchange struct {
+	step         keyExchangeStep    // progress of the key exchange
 	genSignature bool               // control the optional sign/verify step triggered by responsder
 	keyLength    int                // key length
 	privateKey   *EncryptPrivateKey // owner's encryption private key
@@ -297,17 +325,21 @@ func (ke *KeyExchange) Destroy() {
 }
 
 func initKeyExchange(ke *KeyExchange, hid byte, r *bigmod.Nat) {
-	pubB := ke.privateKey.GenerateUserPublicKey(ke.peerUID, hid)
+	master := ke.privateKey.EncryptMasterPublicKey
 	ke.r = r
-	rA, err := new(bn256.G1).ScalarMult(pubB, ke.r.Bytes(orderNat))
+	rA, err := master.scalarMultUserPublicKey(master.hashUserID(ke.peerUID, hid), r)
 	if err != nil {
 		panic(err)
 	}
 	ke.secret = rA.MarshalUncompressed()
+	ke.step = keyExchangeInitiated
 }
 
 // InitKeyExchange generates random with responder uid, for initiator's step A1-A4
 func (ke *KeyExchange) InitKeyExchange(rand io.Reader, hid byte) ([]byte, error) {
+	if ke.step != keyExchangeNew {
+		return nil, ErrKeyExchangeState
+	}
 	r, err := randomScalar(rand)
 	if err != nil {
 		return nil, err
@@ -363,18 +395,18 @@ func (ke *KeyExchange) generateSharedKey(isResponder bool) ([]byte, error) {
 func respondKeyExchange(ke *KeyExchange, hid byte, r *bigmod.Nat, rA []byte) ([]byte, []byte, error) {
 	numBytes := 2 * len(bn256.OrderBytes)
 	if len(rA) != numBytes+1 || rA[0] != 4 {
-		return nil, nil, errors.New("sm9: invalid initiator's ephemeral public key")
+		return nil, nil, fmt.Errorf("%w: invalid initiator's ephemeral public key", ErrKeyExchangeMessage)
 	}
 	rP := new(bn256.G1)
 	_, err := rP.Unmarshal(rA[1:])
 	if err != nil || !rP.IsOnCurve() || rP.IsInfinity() {
-		return nil, nil, errors.New("sm9: invalid initiator's ephemeral public key")
+		return nil, nil, fmt.Errorf("%w: invalid initiator's ephemeral public key", ErrKeyExchangeMessage)
 	}
 	ke.peerSecret = rA
-	pubA := ke.privateKey.GenerateUserPublicKey(ke.peerUID, hid)
+	master := ke.privateKey.EncryptMasterPublicKey
 	ke.r = r
 	rBytes := r.Bytes(orderNat)
-	rB, err := new(bn256.G1).ScalarMult(pubA, rBytes)
+	rB, err := master.scalarMultUserPublicKey(master.hashUserID(ke.peerUID, hid), r)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -393,6 +425,7 @@ func respondKeyExchange(ke *KeyExchange, hid byte, r *bigmod.Nat, rA []byte) ([]
 		return nil, nil, err
 	}
 	ke.g2 = g2
+	ke.step = keyExchangeResponded
 
 	if !ke.genSignature {
 		return ke.secret, nil, nil
@@ -403,6 +436,9 @@ func respondKeyExchange(ke *KeyExchange, hid byte, r *bigmod.Nat, rA []byte) ([]
 
 // RespondKeyExchange when responder receive rA, for responder's step B1-B7
 func (ke *KeyExchange) RespondKeyExchange(rand io.Reader, hid byte, rA []byte) ([]byte, []byte, error) {
+	if ke.step != keyExchangeNew {
+		return nil, nil, ErrKeyExchangeState
+	}
 	r, err := randomScalar(rand)
 	if err != nil {
 		return nil, nil, err
@@ -412,14 +448,17 @@ func (ke *KeyExchange) RespondKeyExchange(rand io.Reader, hid byte, rA []byte) (
 
 // ConfirmResponder for initiator's step A5-A7
 func (ke *KeyExchange) ConfirmResponder(rB, sB []byte) ([]byte, []byte, error) {
+	if ke.step != keyExchangeInitiated {
+		return nil, nil, ErrKeyExchangeState
+	}
 	numBytes := 2 * len(bn256.OrderBytes)
 	if len(rB) != numBytes+1 || rB[0] != 4 {
-		return nil, nil, errors.New("sm9: invalid responder's ephemeral public key")
+		return nil, nil, fmt.Errorf("%w: invalid responder's ephemeral public key", ErrKeyExchangeMessage)
 	}
 	pB := new(bn256.G1)
 	_, err := pB.Unmarshal(rB[1:])
 	if err != nil || !pB.IsOnCurve() || pB.IsInfinity() {
-		return nil, nil, errors.New("sm9: invalid responder's ephemeral public key")
+		return nil, nil, fmt.Errorf("%w: invalid responder's ephemeral public key", ErrKeyExchangeMessage)
 	}
 	// step 5
 	ke.peerSecret = rB
@@ -439,13 +478,14 @@ func (ke *KeyExchange) ConfirmResponder(rB, sB []byte) ([]byte, []byte, error) {
 	if len(sB) > 0 {
 		signature := ke.sign(false, 0x82)
 		if goSubtle.ConstantTimeCompare(signature, sB) != 1 {
-			return nil, nil, errors.New("sm9: invalid responder's signature")
+			return nil, nil, fmt.Errorf("%w: invalid responder's signature", ErrKeyExchangeConfirmation)
 		}
 	}
 	key, err := ke.generateSharedKey(false)
 	if err != nil {
 		return nil, nil, err
 	}
+	ke.step = keyExchangeCompleted
 	if !ke.genSignature {
 		return key, nil, nil
 	}
@@ -454,11 +494,15 @@ func (ke *KeyExchange) ConfirmResponder(rB, sB []byte) ([]byte, []byte, error) {
 
 // ConfirmInitiator for responder's step B8
 func (ke *KeyExchange) ConfirmInitiator(s1 []byte) ([]byte, error) {
+	if ke.step != keyExchangeResponded {
+		return nil, ErrKeyExchangeState
+	}
 	if s1 != nil {
 		buffer := ke.sign(true, 0x83)
 		if goSubtle.ConstantTimeCompare(buffer, s1) != 1 {
-			return nil, errors.New("sm9: invalid initiator's signature")
+			return nil, fmt.Errorf("%w: invalid initiator's signature", ErrKeyExchangeConfirmation)
 		}
 	}
+	ke.step = keyExchangeCompleted
 	return ke.generateSharedKey(true)
 }
@@ -25,10 +25,6 @@ type SignMasterPrivateKey struct {
 type SignMasterPublicKey struct {
 	MasterPublicKey *bn256.G2 // master public key
 	q               []byte    // uncompressed public key point output
-	pairOnce        sync.Once
-	basePoint       *bn256.GT // the result of Pair(Gen1, pub.MasterPublicKey)
-	tableGenOnce    sync.Once
-	table           *[32 * 2]bn256.GTFieldTable // precomputed basePoint^n
 }
 
 // SignPrivateKey is a signature private key, generated by KGC
@@ -48,10 +44,6 @@ type EncryptMasterPrivateKey struct {
 type EncryptMasterPublicKey struct {
 	MasterPublicKey *bn256.G1 // public key
 	q               []byte    // uncompressed public key point output
-	pairOnce        sync.Once
-	basePoint       *bn256.GT // the result of Pair(pub.MasterPublicKey, Gen2)
-	tableGenOnce    sync.Once
-	table           *[32 * 2]bn256.GTFieldTable // precomputed basePoint^n
 }
 
 // EncryptPrivateKey is an encryption private key, generated by KGC
@@ -73,7 +65,7 @@ func (priv *EncryptPrivateKey) getPrecomp() *bn256.G2Precomputed {
 }
 
 // gen2Precomp holds precomputed Miller loop line evaluations for the fixed generator Gen2.
-// Initialized once at package startup and reused in EncryptMasterPublicKey.pair().
+// Initialized once at package startup and reused in PreparedEncryptMasterPublicKey.Pair().
 var gen2Precomp = bn256.Gen2.Precompute()
 
 // GenerateSignMasterKey generates a signature master key pair for DSA usage.
@@ -123,8 +115,8 @@ func NewSignMasterPrivateKey(key []byte) (*SignMasterPrivateKey, error) {
 	priv := new(SignMasterPrivateKey)
 	priv.d = slices.Clone(key)
 	priv.SignMasterPublicKey = new(SignMasterPublicKey)
-	priv.MasterPublicKey = p
 	priv.q = p.MarshalUncompressed()
+	priv.MasterPublicKey = signMasterPoints.intern(priv.q, p)
 	return priv, nil
 }
 
@@ -178,26 +170,10 @@ func (pub *SignMasterPublicKey) Bytes() []byte {
 	return pub.q
 }
 
-// pair generate the basepoint once
-func (pub *SignMasterPublicKey) pair() *bn256.GT {
-	pub.pairOnce.Do(func() {
-		pub.basePoint = bn256.PairPrecomp(bn256.Gen1, pub.MasterPublicKey.Precompute())
-	})
-	return pub.basePoint
-}
-
-func (pub *SignMasterPublicKey) generatorTable() *[32 * 2]bn256.GTFieldTable {
-	pub.tableGenOnce.Do(func() {
-		pub.table = bn256.GenerateGTFieldTable(pub.pair())
-	})
-	return pub.table
-}
-
 // ScalarBaseMult compute basepoint^r with precomputed table
 // The base point = pair(Gen1, <master public key>)
 func (pub *SignMasterPublicKey) ScalarBaseMult(scalar []byte) (*bn256.GT, error) {
-	tables := pub.generatorTable()
-	return bn256.ScalarBaseMultGT(tables, scalar)
+	return pub.Prepared().ScalarBaseMult(scalar)
 }
 
 // GenerateUserPublicKey generate a signature public key for given user.
@@ -256,8 +232,8 @@ func (pub *SignMasterPublicKey) UnmarshalRaw(bytes []byte) error {
 	if err != nil {
 		return err
 	}
-	pub.MasterPublicKey = g2
 	pub.q = g2.MarshalUncompressed()
+	pub.MasterPublicKey = signMasterPoints.intern(pub.q, g2)
 	return nil
 }
 
@@ -339,8 +315,8 @@ func NewEncryptMasterPrivateKey(key []byte) (*EncryptMasterPrivateKey, error) {
 	priv := new(EncryptMasterPrivateKey)
 	priv.d = slices.Clone(key)
 	priv.EncryptMasterPublicKey = new(EncryptMasterPublicKey)
-	priv.MasterPublicKey = p
 	priv.q = p.MarshalUncompressed()
+	priv.MasterPublicKey = encryptMasterPoints.intern(priv.q, p)
 	return priv, nil
 }
 
@@ -392,26 +368,42 @@ func (pub *EncryptMasterPublicKey) Bytes() []byte {
 	return pub.q
 }
 
-// pair generate the basepoint once
-func (pub *EncryptMasterPublicKey) pair() *bn256.GT {
-	pub.pairOnce.Do(func() {
-		pub.basePoint = bn256.PairPrecomp(pub.MasterPublicKey, gen2Precomp)
-	})
-	return pub.basePoint
+// ScalarBaseMult compute basepoint^r with precomputed table.
+// The base point = pair(<master public key>, Gen2)
+func (pub *EncryptMasterPublicKey) ScalarBaseMult(scalar []byte) (*bn256.GT, error) {
+	return pub.Prepared().ScalarBaseMult(scalar)
 }
 
-func (pub *EncryptMasterPublicKey) generatorTable() *[32 * 2]bn256.GTFieldTable {
-	pub.tableGenOnce.Do(func() {
-		pub.table = bn256.GenerateGTFieldTable(pub.pair())
-	})
-	return pub.table
+// hashUserID returns H1(uid||hid, N).
+func (pub *EncryptMasterPublicKey) hashUserID(uid []byte, hid byte) *bigmod.Nat {
+	return hashUserID(uid, hid)
 }
 
-// ScalarBaseMult compute basepoint^r with precomputed table.
-// The base point = pair(<master public key>, Gen2)
-func (pub *EncryptMasterPublicKey) ScalarBaseMult(scalar []byte) (*bn256.GT, error) {
-	tables := pub.generatorTable()
-	return bn256.ScalarBaseMultGT(tables, scalar)
+func hashUserID(uid []byte, hid byte) *bigmod.Nat {
+	var buffer []byte
+	buffer = append(append(buffer, uid...), hid)
+	return hashH1(buffer)
+}
+
+// HashUserID returns H1(uid||hid, N) in 32 bytes big-endian, it is the scalar
+// which binds the user keys to the identity.
+func HashUserID(uid []byte, hid byte) []byte {
+	return hashUserID(uid, hid).Bytes(orderNat)
+}
+
+// scalarMultUserPublicKey computes r*(h1*P1 + Ppub-e) as (r*h1)*P1 + r*Ppub-e,
+// both are fixed-base scalar multiplications.
+func (pub *EncryptMasterPublicKey) scalarMultUserPublicKey(h1, r *bigmod.Nat) (*bn256.G1, error) {
+	rh := bigmod.NewNat().Set(h1).Mul(r, orderNat)
+	p, err := new(bn256.G1).ScalarBaseMult(rh.Bytes(orderNat))
+	if err != nil {
+		return nil, err
+	}
+	q, err := pub.Prepared().ScalarMultPublic(r.Bytes(orderNat))
+	if err != nil {
+		return nil, err
+	}
+	return p.Add(p, q), nil
 }
 
 // GenerateUserPublicKey generate an encryption public key for the given user.
@@ -451,8 +443,8 @@ func (pub *EncryptMasterPublicKey) UnmarshalRaw(bytes []byte) error {
 	if err != nil {
 		return err
 	}
-	pub.MasterPublicKey = g
 	pub.q = g.MarshalUncompressed()
+	pub.MasterPublicKey = encryptMasterPoints.intern(pub.q, g)
 	return nil
 }
 
@@ -5,65 +5,65 @@
 
 #include "textflag.h"
 
-DATA Top3_bits_of_the_byte<>+0x00(SB)/8, $0xe0e0e0e0e0e0e0e0
-DATA Top3_bits_of_the_byte<>+0x08(SB)/8, $0xe0e0e0e0e0e0e0e0
-GLOBL Top3_bits_of_the_byte<>(SB), RODATA, $16
+DATA ·Top3_bits_of_the_byte+0x00(SB)/8, $0xe0e0e0e0e0e0e0e0
+DATA ·Top3_bits_of_the_byte+0x08(SB)/8, $0xe0e0e0e0e0e0e0e0
+GLOBL ·Top3_bits_of_the_byte(SB), RODATA, $16
 
-DATA Bottom5_bits_of_the_byte<>+0x00(SB)/8, $0x1f1f1f1f1f1f1f1f
-DATA Bottom5_bits_of_the_byte<>+0x08(SB)/8, $0x1f1f1f1f1f1f1f1f
-GLOBL Bottom5_bits_of_the_byte<>(SB), RODATA, $16
+DATA ·Bottom5_bits_of_the_byte+0x00(SB)/8, $0x1f1f1f1f1f1f1f1f
+DATA ·Bottom5_bits_of_the_byte+0x08(SB)/8, $0x1f1f1f1f1f1f1f1f
+GLOBL ·Bottom5_bits_of_the_byte(SB), RODATA, $16
 
-DATA Low_nibble_mask<>+0x00(SB)/8, $0x0F0F0F0F0F0F0F0F
-DATA Low_nibble_mask<>+0x08(SB)/8, $0x0F0F0F0F0F0F0F0F
-GLOBL Low_nibble_mask<>(SB), RODATA, $16
+DATA ·Low_nibble_mask+0x00(SB)/8, $0x0F0F0F0F0F0F0F0F
+DATA ·Low_nibble_mask+0x08(SB)/8, $0x0F0F0F0F0F0F0F0F
+GLOBL ·Low_nibble_mask(SB), RODATA, $16
 
-DATA P1<>+0x00(SB)/8, $0x0A020F0F0E000F09
-DATA P1<>+0x08(SB)/8, $0x090305070C000400
-GLOBL P1<>(SB), RODATA, $16
+DATA ·P1+0x00(SB)/8, $0x0A020F0F0E000F09
+DATA ·P1+0x08(SB)/8, $0x090305070C000400
+GLOBL ·P1(SB), RODATA, $16
 
-DATA P2<>+0x00(SB)/8, $0x040C000705060D08
-DATA P2<>+0x08(SB)/8, $0x0209030F0A0E010B
-GLOBL P2<>(SB), RODATA, $16
+DATA ·P2+0x00(SB)/8, $0x040C000705060D08
+DATA ·P2+0x08(SB)/8, $0x0209030F0A0E010B
+GLOBL ·P2(SB), RODATA, $16
 
-DATA P3<>+0x00(SB)/8, $0x0F0A0D00060A0602
-DATA P3<>+0x08(SB)/8, $0x0D0C0900050D0303
-GLOBL P3<>(SB), RODATA, $16
+DATA ·P3+0x00(SB)/8, $0x0F0A0D00060A0602
+DATA ·P3+0x08(SB)/8, $0x0D0C0900050D0303
+GLOBL ·P3(SB), RODATA, $16
 
-DATA Aes_to_Zuc_mul_low_nibble<>+0x00(SB)/8, $0x1D1C9F9E83820100
-DATA Aes_to_Zuc_mul_low_nibble<>+0x08(SB)/8, $0x3938BBBAA7A62524
-GLOBL Aes_to_Zuc_mul_low_nibble<>(SB), RODATA, $16
+DATA ·Aes_to_Zuc_mul_low_nibble+0x00(SB)/8, $0x1D1C9F9E83820100
+DATA ·Aes_to_Zuc_mul_low_nibble+0x08(SB)/8, $0x3938BBBAA7A62524
+GLOBL ·Aes_to_Zuc_mul_low_nibble(SB), RODATA, $16
 
-DATA Aes_to_Zuc_mul_high_nibble<>+0x00(SB)/8, $0xA174A97CDD08D500
-DATA Aes_to_Zuc_mul_high_nibble<>+0x08(SB)/8, $0x3DE835E04194499C
-GLOBL Aes_to_Zuc_mul_high_nibble<>(SB), RODATA, $16
+DATA ·Aes_to_Zuc_mul_high_nibble+0x00(SB)/8, $0xA174A97CDD08D500
+DATA ·Aes_to_Zuc_mul_high_nibble+0x08(SB)/8, $0x3DE835E04194499C
+GLOBL ·Aes_to_Zuc_mul_high_nibble(SB), RODATA, $16
 
-DATA Comb_matrix_mul_low_nibble<>+0x00(SB)/8, $0xCFDB6571BEAA1400
-DATA Comb_matrix_mul_low_nibble<>+0x08(SB)/8, $0x786CD2C6091DA3B7
-GLOBL Comb_matrix_mul_low_nibble<>(SB), RODATA, $16
+DATA ·Comb_matrix_mul_low_nibble+0x00(SB)/8, $0xCFDB6571BEAA1400
+DATA ·Comb_matrix_mul_low_nibble+0x08(SB)/8, $0x786CD2C6091DA3B7
+GLOBL ·Comb_matrix_mul_low_nibble(SB), RODATA, $16
 
-DATA Comb_matrix_mul_high_nibble<>+0x00(SB)/8, $0x638CFA1523CCBA55
-DATA Comb_matrix_mul_high_nibble<>+0x08(SB)/8, $0x3FD0A6497F90E609
-GLOBL Comb_matrix_mul_high_nibble<>(SB), RODATA, $16
+DATA ·Comb_matrix_mul_high_nibble+0x00(SB)/8, $0x638CFA1523CCBA55
+DATA ·Comb_matrix_mul_high_nibble+0x08(SB)/8, $0x3FD0A6497F90E609
+GLOBL ·Comb_matrix_mul_high_nibble(SB), RODATA, $16
 
-DATA Shuf_mask<>+0x00(SB)/8, $0x0B0E0104070A0D00
-DATA Shuf_mask<>+0x08(SB)/8, $0x0306090C0F020508
-GLOBL Shuf_mask<>(SB), RODATA, $16
+DATA ·Shuf_mask+0x00(SB)/8, $0x0B0E0104070A0D00
+DATA ·Shuf_mask+0x08(SB)/8, $0x0306090C0F020508
+GLOBL ·Shuf_mask(SB), RODATA, $16
 
-DATA Cancel_aes<>+0x00(SB)/8, $0x6363636363636363
-DATA Cancel_aes<>+0x08(SB)/8, $0x6363636363636363
-GLOBL Cancel_aes<>(SB), RODATA, $16
+DATA ·Cancel_aes+0x00(SB)/8, $0x6363636363636363
+DATA ·Cancel_aes+0x08(SB)/8, $0x6363636363636363
+GLOBL ·Cancel_aes(SB), RODATA, $16
 
 DATA CombMatrix<>+0x00(SB)/8, $0x3C1A99B2AD1ED43A
 DATA CombMatrix<>+0x08(SB)/8, $0x3C1A99B2AD1ED43A
 GLOBL CombMatrix<>(SB), RODATA, $16
 
-DATA mask_S0<>+0x00(SB)/8, $0xff00ff00ff00ff00
-DATA mask_S0<>+0x08(SB)/8, $0xff00ff00ff00ff00
-GLOBL mask_S0<>(SB), RODATA, $16
+DATA ·mask_S0+0x00(SB)/8, $0xff00ff00ff00ff00
+DATA ·mask_S0+0x08(SB)/8, $0xff00ff00ff00ff00
+GLOBL ·mask_S0(SB), RODATA, $16
 
-DATA mask_S1<>+0x00(SB)/8, $0x00ff00ff00ff00ff
-DATA mask_S1<>+0x08(SB)/8, $0x00ff00ff00ff00ff
-GLOBL mask_S1<>(SB), RODATA, $16
+DATA ·mask_S1+0x00(SB)/8, $0x00ff00ff00ff00ff
+DATA ·mask_S1+0x08(SB)/8, $0x00ff00ff00ff00ff
+GLOBL ·mask_S1(SB), RODATA, $16
 
 // shuffle byte order from LE to BE
 DATA flip_mask<>+0x00(SB)/8, $0x0405060700010203
@@ -112,8 +112,8 @@ GLOBL zuc_gfni_s1_m2<>(SB), RODATA, $16
 	MOVOU XDATA, XTMP0                         \
 	PSLLL $5, XTMP0                            \
 	PSRLL $3, XDATA                            \
-	PAND Top3_bits_of_the_byte<>(SB), XTMP0    \
-	PAND Bottom5_bits_of_the_byte<>(SB), XDATA \
+	PAND ·Top3_bits_of_the_byte(SB), XTMP0     \
+	PAND ·Bottom5_bits_of_the_byte(SB), XDATA  \
 	POR XTMP0, XDATA
 
 // Compute 16 S0 box values from 16 bytes, SSE version.
@@ -121,18 +121,18 @@ GLOBL zuc_gfni_s1_m2<>(SB), RODATA, $16
 	MOVOU IN_OUT, XTMP1                        \
 	\
 	PSRLQ $4, XTMP1                            \  // x1
-	PAND Low_nibble_mask<>(SB), XTMP1          \ 
-	PAND Low_nibble_mask<>(SB), IN_OUT         \  // x2
+	PAND ·Low_nibble_mask(SB), XTMP1           \
+	PAND ·Low_nibble_mask(SB), IN_OUT         \  // x2
 	\
-	MOVOU P1<>(SB), XTMP2                      \
+	MOVOU ·P1(SB), XTMP2                       \
 	PSHUFB IN_OUT, XTMP2                       \ // P1[x2]
 	PXOR XTMP1, XTMP2                          \ // q = x1 ^ P1[x2], XTMP1 free
 	\
-	MOVOU P2<>(SB), XTMP1                      \
+	MOVOU ·P2(SB), XTMP1                       \
 	PSHUFB XTMP2, XTMP1                        \ // P2[q]
 	PXOR IN_OUT, XTMP1                         \ // r = x2 ^ P2[q]; IN_OUT free
 	\
-	MOVOU P3<>(SB), IN_OUT                     \
+	MOVOU ·P3(SB), IN_OUT                      \
 	PSHUFB XTMP1, IN_OUT                       \ // P3[r]
 	PXOR XTMP2, IN_OUT                         \ // s = q ^ P3[r], XTMP2 free
 	\ // s << 4 (since high nibble of each byte is 0, no masking is required)
@@ -145,12 +145,12 @@ GLOBL zuc_gfni_s1_m2<>(SB), RODATA, $16
 #define MUL_PSHUFB_SSE(XIN, XLO, XHI_OUT, XTMP)        \
 	\ // Get low nibble of input data
 	MOVOU XIN, XTMP                                    \
-	PAND Low_nibble_mask<>(SB), XTMP                   \
+	PAND ·Low_nibble_mask(SB), XTMP                    \
 	\ // Get low nibble of output
 	PSHUFB XTMP, XLO                                   \
 	\ // Get high nibble of input data
 	PSRLQ $4, XIN                                      \
-	PAND Low_nibble_mask<>(SB), XIN                    \
+	PAND ·Low_nibble_mask(SB), XIN                     \
 	\ // Get high nibble of output
 	PSHUFB XIN, XHI_OUT                                \
 	\ // XOR high and low nibbles to get full bytes
@@ -158,40 +158,40 @@ GLOBL zuc_gfni_s1_m2<>(SB), RODATA, $16
 
 // Compute 16 S1 box values from 16 bytes, stored in XMM register
 #define S1_comput_SSE(XIN_OUT, XTMP1, XTMP2, XTMP3)    \
-	MOVOU Aes_to_Zuc_mul_low_nibble<>(SB), XTMP1       \
-	MOVOU Aes_to_Zuc_mul_high_nibble<>(SB), XTMP2      \
+	MOVOU ·Aes_to_Zuc_mul_low_nibble(SB), XTMP1        \
+	MOVOU ·Aes_to_Zuc_mul_high_nibble(SB), XTMP2       \
 	MUL_PSHUFB_SSE(XIN_OUT, XTMP1, XTMP2, XTMP3)       \
 	\
-	PSHUFB Shuf_mask<>(SB), XTMP2                      \
-	AESENCLAST Cancel_aes<>(SB), XTMP2                 \
+	PSHUFB ·Shuf_mask(SB), XTMP2                       \
+	AESENCLAST ·Cancel_aes(SB), XTMP2                  \
 	\
-	MOVOU Comb_matrix_mul_low_nibble<>(SB), XTMP1      \
-	MOVOU Comb_matrix_mul_high_nibble<>(SB), XIN_OUT   \
+	MOVOU ·Comb_matrix_mul_low_nibble(SB), XTMP1       \
+	MOVOU ·Comb_matrix_mul_high_nibble(SB), XIN_OUT    \
 	MUL_PSHUFB_SSE(XTMP2, XTMP1, XIN_OUT, XTMP3)
 
 // Rotate left 5 bits in each byte, within an XMM register, AVX version.
 #define Rotl_5_AVX(XDATA, XTMP0)                       \
 	VPSLLD $5, XDATA, XTMP0                            \
 	VPSRLD $3, XDATA, XDATA                            \
-	VPAND Top3_bits_of_the_byte<>(SB), XTMP0, XTMP0    \
-	VPAND Bottom5_bits_of_the_byte<>(SB), XDATA, XDATA \
+	VPAND ·Top3_bits_of_the_byte(SB), XTMP0, XTMP0     \
+	VPAND ·Bottom5_bits_of_the_byte(SB), XDATA, XDATA  \
 	VPOR XTMP0, XDATA, XDATA
 
 // Compute 16 S0 box values from 16 bytes, AVX version.
 #define S0_comput_AVX(IN_OUT, XTMP1, XTMP2)      \
 	VPSRLQ $4, IN_OUT, XTMP1                     \ // x1
-	VPAND Low_nibble_mask<>(SB), XTMP1, XTMP1    \
-	VPAND Low_nibble_mask<>(SB), IN_OUT, IN_OUT  \ // x2
+	VPAND ·Low_nibble_mask(SB), XTMP1, XTMP1     \
+	VPAND ·Low_nibble_mask(SB), IN_OUT, IN_OUT  \ // x2
 	\
-	VMOVDQU P1<>(SB), XTMP2                      \
+	VMOVDQU ·P1(SB), XTMP2                       \
 	VPSHUFB IN_OUT, XTMP2, XTMP2                 \ // P1[x2]
 	VPXOR XTMP1, XTMP2, XTMP2                    \ // q = x1 ^ P1[x2] ; XTMP1 free
 	\
-	VMOVDQU P2<>(SB), XTMP1                      \
+	VMOVDQU ·P2(SB), XTMP1                       \
 	VPSHUFB XTMP2, XTMP1, XTMP1                  \ // P2[q]
 	VPXOR IN_OUT, XTMP1, XTMP1                   \ // r = x2 ^ P2[q] ; IN_OUT free
 	\
-	VMOVDQU P3<>(SB), IN_OUT                     \
+	VMOVDQU ·P3(SB), IN_OUT                      \
 	VPSHUFB XTMP1, IN_OUT, IN_OUT                \ // P3[r]
 	VPXOR XTMP2, IN_OUT, IN_OUT                  \ // s = q ^ P3[r] ; XTMP2 free
 	\ // s << 4 (since high nibble of each byte is 0, no masking is required)
@@ -203,12 +203,12 @@ GLOBL zuc_gfni_s1_m2<>(SB), RODATA, $16
 // for high and low nible of each input byte, AVX version.
 #define MUL_PSHUFB_AVX(XIN, XLO, XHI_OUT, XTMP)        \
 	\ // Get low nibble of input data
-	VPAND Low_nibble_mask<>(SB), XIN, XTMP             \
+	VPAND ·Low_nibble_mask(SB), XIN, XTMP              \
 	\ // Get low nibble of output
 	VPSHUFB XTMP, XLO, XLO                             \
 	\ // Get high nibble of input data
 	VPSRLQ $4, XIN, XTMP                               \
-	VPAND Low_nibble_mask<>(SB), XTMP, XTMP            \
+	VPAND ·Low_nibble_mask(SB), XTMP, XTMP             \
 	\ // Get high nibble of output
 	VPSHUFB XTMP, XHI_OUT, XHI_OUT                     \
 	\ // XOR high and low nibbles to get full bytes
@@ -217,15 +217,15 @@ GLOBL zuc_gfni_s1_m2<>(SB), RODATA, $16
 // Compute 16 S1 box values from 16 bytes, stored in XMM register
 #define S1_comput_AVX(XIN_OUT, XTMP1, XTMP2, XTMP3)       \
 	\ // gf2p8affineqb  XIN_OUT, [rel Aes_to_Zuc], 0x00
-	VMOVDQU Aes_to_Zuc_mul_low_nibble<>(SB), XTMP1        \
-	VMOVDQU Aes_to_Zuc_mul_high_nibble<>(SB), XTMP2       \
+	VMOVDQU ·Aes_to_Zuc_mul_low_nibble(SB), XTMP1         \
+	VMOVDQU ·Aes_to_Zuc_mul_high_nibble(SB), XTMP2        \
 	MUL_PSHUFB_AVX(XIN_OUT, XTMP1, XTMP2, XTMP3)          \
 	\
-	VPSHUFB Shuf_mask<>(SB), XTMP2, XTMP2                 \
-	VAESENCLAST Cancel_aes<>(SB), XTMP2, XTMP2            \
+	VPSHUFB ·Shuf_mask(SB), XTMP2, XTMP2                  \
+	VAESENCLAST ·Cancel_aes(SB), XTMP2, XTMP2             \
 	\ // gf2p8affineqb  XIN_OUT, [rel CombMatrix], 0x55
-	VMOVDQU Comb_matrix_mul_low_nibble<>(SB), XTMP1       \
-	VMOVDQU Comb_matrix_mul_high_nibble<>(SB), XIN_OUT    \
+	VMOVDQU ·Comb_matrix_mul_low_nibble(SB), XTMP1        \
+	VMOVDQU ·Comb_matrix_mul_high_nibble(SB), XIN_OUT     \
 	MUL_PSHUFB_AVX(XTMP2, XTMP1, XIN_OUT, XTMP3)
 
 // Compute 16 S1 box values from 16 bytes using GFNI (2 instructions).
@@ -252,8 +252,8 @@ GLOBL zuc_gfni_s1_m2<>(SB), RODATA, $16
 	S0_comput_AVX(X1, X2, X3)                        \
 	S1_comput_GFNI_PRE(X0, XM1, XM2)                \
 	\
-	VPAND mask_S1<>(SB), X0, X0                      \
-	VPAND mask_S0<>(SB), X1, X1                      \
+	VPAND ·mask_S1(SB), X0, X0                       \
+	VPAND ·mask_S0(SB), X1, X1                       \
 	VPXOR X1, X0, X0                                 \
 	\
 	MOVL X0, F_R1                                    \ // F_R1 = X0[31:0]
@@ -292,8 +292,8 @@ GLOBL zuc_gfni_s1_m2<>(SB), RODATA, $16
 	S0_comput_AVX(X1, X2, X3)                \
 	S1_comput_GFNI(X0, X2)                   \
 	\
-	VPAND mask_S1<>(SB), X0, X0              \
-	VPAND mask_S0<>(SB), X1, X1              \ 
+	VPAND ·mask_S1(SB), X0, X0               \
+	VPAND ·mask_S0(SB), X1, X1               \
 	VPXOR X1, X0, X0                         \ 
 	\
 	MOVL X0, F_R1                            \ // F_R1 = X0[31:0]
@@ -433,8 +433,8 @@ GLOBL zuc_gfni_s1_m2<>(SB), RODATA, $16
 	S0_comput_SSE(X1, X2, X3)                \
 	S1_comput_SSE(X0, X2, X3, X4)            \
 	\
-	PAND mask_S1<>(SB), X0                   \
-	PAND mask_S0<>(SB), X1                   \ 
+	PAND ·mask_S1(SB), X0                    \
+	PAND ·mask_S0(SB), X1                    \
 	PXOR X1, X0                              \ 
 	\
 	MOVL X0, F_R1                            \ // F_R1
@@ -567,8 +567,8 @@ GLOBL zuc_gfni_s1_m2<>(SB), RODATA, $16
 	S0_comput_AVX(X1, X2, X3)                \
 	S1_comput_AVX(X0, X2, X3, X4)            \
 	\
-	VPAND mask_S1<>(SB), X0, X0              \
-	VPAND mask_S0<>(SB), X1, X1              \ 
+	VPAND ·mask_S1(SB), X0, X0               \
+	VPAND ·mask_S0(SB), X1, X1               \
 	VPXOR X1, X0, X0                         \ 
 	\
 	MOVL X0, F_R1                            \ // F_R1 = X0[31:0]
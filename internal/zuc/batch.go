@@ -0,0 +1,258 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package zuc
+
+import (
+	"cmp"
+	"crypto/subtle"
+	"errors"
+	"slices"
+
+	"github.com/emmansun/gmsm/internal/alias"
+	"github.com/emmansun/gmsm/internal/byteorder"
+)
+
+const (
+	// batchLanes is the number of ZUC instances processed in lockstep.
+	batchLanes = 8
+	// batchRoundWords is the number of keywords per lane generated by one
+	// full rotation of the LFSR.
+	batchRoundWords = 16
+	// batchChunkRounds bounds the keystream buffered per lane.
+	batchChunkRounds = 64
+)
+
+// zucState32x8 holds 8 ZUC states in lane-interleaved layout,
+// lfsr[i][j] is the i-th LFSR cell of lane j.
+type zucState32x8 struct {
+	lfsr [16][batchLanes]uint32
+	r1   [batchLanes]uint32
+	r2   [batchLanes]uint32
+}
+
+func (s *zucState32x8) load(lane int, st *zucState32) {
+	for i := range st.lfsr {
+		s.lfsr[i][lane] = st.lfsr[i]
+	}
+	s.r1[lane] = st.r1
+	s.r2[lane] = st.r2
+}
+
+func (s *zucState32x8) store(lane int, st *zucState32) {
+	for i := range st.lfsr {
+		st.lfsr[i] = s.lfsr[i][lane]
+	}
+	st.r1 = s.r1[lane]
+	st.r2 = s.r2[lane]
+}
+
+// initBy8Generic runs the 32 initialization rounds for every lane.
+func initBy8Generic(s *zucState32x8) {
+	var st zucState32
+	for lane := range batchLanes {
+		s.store(lane, &st)
+		for i := 0; i < 32; i++ {
+			st.bitReorganization()
+			w := st.f32()
+			st.enterInitMode(w >> 1)
+		}
+		s.load(lane, &st)
+	}
+}
+
+// genKeyStreamBy8Generic generates rounds*batchRoundWords keywords for every lane,
+// keyStream[w*batchLanes+j] is the w-th keyword of lane j.
+func genKeyStreamBy8Generic(s *zucState32x8, keyStream []uint32, rounds int) {
+	var st zucState32
+	for lane := range batchLanes {
+		s.store(lane, &st)
+		for w := 0; w < rounds*batchRoundWords; w++ {
+			st.bitReorganization()
+			keyStream[w*batchLanes+lane] = st.x3 ^ st.f32()
+			st.enterWorkMode()
+		}
+		s.load(lane, &st)
+	}
+}
+
+// genKeyStreamBatch generates words[i] keywords for every loaded (but not yet
+// initialized) state, processing up to batchLanes instances in lockstep.
+// The keywords of instance i are passed to consume in order, in one or more calls.
+func genKeyStreamBatch(states []zucState32, words []int, consume func(i int, keyWords []uint32)) {
+	// Group instances with similar keystream lengths together.
+	order := make([]int, len(states))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(words[b], words[a])
+	})
+
+	var s zucState32x8
+	buf := make([]uint32, batchChunkRounds*batchRoundWords*batchLanes)
+	laneWords := make([]uint32, batchChunkRounds*batchRoundWords)
+	for len(order) > 0 {
+		group := order[:min(batchLanes, len(order))]
+		order = order[len(group):]
+
+		s = zucState32x8{}
+		for j, i := range group {
+			s.load(j, &states[i])
+		}
+		initBy8(&s)
+
+		// The first keyword after initialization is discarded.
+		total := words[group[0]] + 1
+		for generated := 0; generated < total; {
+			rounds := min(batchChunkRounds, (total-generated+batchRoundWords-1)/batchRoundWords)
+			genKeyStreamBy8(&s, buf[:rounds*batchRoundWords*batchLanes], rounds)
+			for j, i := range group {
+				start := max(generated, 1)
+				end := min(generated+rounds*batchRoundWords, words[i]+1)
+				if start >= end {
+					continue
+				}
+				for k := start; k < end; k++ {
+					laneWords[k-start] = buf[(k-generated)*batchLanes+j]
+				}
+				consume(i, laneWords[:end-start])
+			}
+			generated += rounds * batchRoundWords
+		}
+	}
+}
+
+// XORKeyStreamBatch XORs every src[i] with the keystream of the ZUC instance
+// created from keys[i] and ivs[i], and writes the result to dst[i].
+func XORKeyStreamBatch(keys, ivs, dst, src [][]byte) error {
+	n := len(keys)
+	if len(ivs) != n || len(dst) != n || len(src) != n {
+		return errors.New("zuc: mismatched batch sizes")
+	}
+	states := make([]zucState32, n)
+	words := make([]int, n)
+	for i := range n {
+		if len(dst[i]) < len(src[i]) {
+			panic("zuc: output smaller than input")
+		}
+		if alias.InexactOverlap(dst[i][:len(src[i])], src[i]) {
+			panic("zuc: invalid buffer overlap")
+		}
+		if err := states[i].loadKeyIV(keys[i], ivs[i]); err != nil {
+			return err
+		}
+		words[i] = (len(src[i]) + WordSize - 1) / WordSize
+	}
+
+	offsets := make([]int, n)
+	keyBytes := make([]byte, batchChunkRounds*batchRoundWords*WordSize)
+	genKeyStreamBatch(states, words, func(i int, keyWords []uint32) {
+		for k, w := range keyWords {
+			byteorder.BEPutUint32(keyBytes[k*WordSize:], w)
+		}
+		off := offsets[i]
+		offsets[i] += subtle.XORBytes(dst[i][off:], src[i][off:], keyBytes[:len(keyWords)*WordSize])
+	})
+	return nil
+}
+
+// EEAXORKeyStreamBatch is like XORKeyStreamBatch, but the ZUC-128 IVs are
+// constructed from counts, bearers and directions as in 128-EEA3.
+func EEAXORKeyStreamBatch(keys [][]byte, counts, bearers, directions []uint32, dst, src [][]byte) error {
+	ivs, err := eeaIVs(len(keys), counts, bearers, directions, construcIV4EEA)
+	if err != nil {
+		return err
+	}
+	return XORKeyStreamBatch(keys, ivs, dst, src)
+}
+
+func eeaIVs(n int, counts, bearers, directions []uint32, construct func(count, bearer, direction uint32) []byte) ([][]byte, error) {
+	if len(counts) != n || len(bearers) != n || len(directions) != n {
+		return nil, errors.New("zuc: mismatched batch sizes")
+	}
+	ivs := make([][]byte, n)
+	for i := range n {
+		ivs[i] = construct(counts[i], bearers[i], directions[i])
+	}
+	return ivs, nil
+}
+
+// MACBatch computes the ZUC-128 MAC of the first nbits[i] bits of msgs[i]
+// with keys[i] and ivs[i], for every i.
+func MACBatch(keys, ivs, msgs [][]byte, nbits []int) ([][]byte, error) {
+	n := len(keys)
+	if len(ivs) != n || len(msgs) != n || len(nbits) != n {
+		return nil, errors.New("zuc: mismatched batch sizes")
+	}
+	states := make([]zucState32, n)
+	words := make([]int, n)
+	keyStreams := make([][]uint32, n)
+	for i := range n {
+		if len(keys[i]) != 16 {
+			return nil, KeySizeError(len(keys[i]))
+		}
+		if nbits[i] < 0 || len(msgs[i]) < (nbits[i]+7)/8 {
+			return nil, errors.New("zuc: invalid message bit length")
+		}
+		if err := states[i].loadKeyIV(keys[i], ivs[i]); err != nil {
+			return nil, err
+		}
+		words[i] = (nbits[i]+31)/32 + 2
+		// eiaRoundTag4 may read beyond the last keyword it uses.
+		keyStreams[i] = make([]uint32, 0, words[i]+4)
+	}
+
+	genKeyStreamBatch(states, words, func(i int, keyWords []uint32) {
+		keyStreams[i] = append(keyStreams[i], keyWords...)
+	})
+
+	macs := make([][]byte, n)
+	for i := range n {
+		macs[i] = byteorder.BEAppendUint32(nil, eiaTag(keyStreams[i], msgs[i], nbits[i]))
+	}
+	return macs, nil
+}
+
+// EIAMACBatch is like MACBatch, but the ZUC-128 IVs are constructed from
+// counts, bearers and directions as in 128-EIA3.
+func EIAMACBatch(keys [][]byte, counts, bearers, directions []uint32, msgs [][]byte, nbits []int) ([][]byte, error) {
+	ivs, err := eeaIVs(len(keys), counts, bearers, directions, genIV4EIA)
+	if err != nil {
+		return nil, err
+	}
+	return MACBatch(keys, ivs, msgs, nbits)
+}
+
+// keyWindow returns the 32-bit keystream window starting at bit i.
+func keyWindow(keyStream []uint32, i int) uint32 {
+	w, r := i/32, uint(i%32)
+	if r == 0 {
+		return keyStream[w]
+	}
+	return keyStream[w]<<r | keyStream[w+1]>>(32-r)
+}
+
+// eiaTag computes the 128-EIA3 tag of the first nbits bits of p with the
+// keyStream, which must contain at least nbits/32+2 keywords.
+func eiaTag(keyStream []uint32, p []byte, nbits int) uint32 {
+	var t uint32
+	nbytes := nbits / 128 * chunk
+	blockKeyStream(&t, keyStream, p[:nbytes])
+	for i := nbytes * 8; i < nbits; i++ {
+		bit := uint32(p[i/8]>>(7-i%8)) & 1
+		t ^= -bit & keyWindow(keyStream, i)
+	}
+	t ^= keyWindow(keyStream, nbits)
+	t ^= keyStream[(nbits+31)/32+1]
+	return t
+}
+
+// blockKeyStreamGeneric is like blockGeneric, but takes the keywords from keyStream.
+func blockKeyStreamGeneric(t *uint32, keyStream []uint32, p []byte) {
+	for i := 0; i < len(p)*8; i++ {
+		bit := uint32(p[i/8]>>(7-i%8)) & 1
+		*t ^= -bit & keyWindow(keyStream, i)
+	}
+}
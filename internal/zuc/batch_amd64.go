@@ -0,0 +1,33 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build !purego
+
+package zuc
+
+import "github.com/emmansun/gmsm/internal/deps/cpu"
+
+var useBatchAVX2 = cpu.X86.HasAVX2 && supportsAES
+
+//go:noescape
+func initBy8Asm(s *zucState32x8)
+
+//go:noescape
+func genKeyStreamBy8Asm(s *zucState32x8, keyStream *uint32, rounds int)
+
+func initBy8(s *zucState32x8) {
+	if useBatchAVX2 {
+		initBy8Asm(s)
+		return
+	}
+	initBy8Generic(s)
+}
+
+func genKeyStreamBy8(s *zucState32x8, keyStream []uint32, rounds int) {
+	if useBatchAVX2 {
+		genKeyStreamBy8Asm(s, &keyStream[0], rounds)
+		return
+	}
+	genKeyStreamBy8Generic(s, keyStream, rounds)
+}
@@ -0,0 +1,256 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// 8-lane ZUC keystream generation with AVX2.
+// The linear parts (bit reorganization, L1/L2, LFSR) process 8 lanes in YMM
+// registers; the S-boxes reuse the constant-time nibble/AES-NI approach of
+// asm_amd64.s on the two 128-bit halves.
+//go:build !purego
+
+#include "textflag.h"
+
+DATA mask31<>+0x00(SB)/8, $0x7FFFFFFF7FFFFFFF
+DATA mask31<>+0x08(SB)/8, $0x7FFFFFFF7FFFFFFF
+DATA mask31<>+0x10(SB)/8, $0x7FFFFFFF7FFFFFFF
+DATA mask31<>+0x18(SB)/8, $0x7FFFFFFF7FFFFFFF
+GLOBL mask31<>(SB), RODATA, $32
+
+// LFSR row i of the current round, rows are 32 bytes (8 lanes) each.
+#define ROW(i, idx) ((((i) + (idx)) % 16)*32)(SI)
+
+#define R1 Y14
+#define R2 Y15
+#define MASK31 Y13
+
+// Rotate left 5 bits in each byte, within an XMM register.
+#define Rotl_5(XDATA, XTMP0)                                 \
+	VPSLLD $5, XDATA, XTMP0                                  \
+	VPSRLD $3, XDATA, XDATA                                  \
+	VPAND ·Top3_bits_of_the_byte(SB), XTMP0, XTMP0           \
+	VPAND ·Bottom5_bits_of_the_byte(SB), XDATA, XDATA        \
+	VPOR XTMP0, XDATA, XDATA
+
+// Compute 16 S0 box values from 16 bytes.
+#define S0_comput(IN_OUT, XTMP1, XTMP2)                \
+	VPSRLQ $4, IN_OUT, XTMP1                           \
+	VPAND ·Low_nibble_mask(SB), XTMP1, XTMP1           \
+	VPAND ·Low_nibble_mask(SB), IN_OUT, IN_OUT         \
+	VMOVDQU ·P1(SB), XTMP2                             \
+	VPSHUFB IN_OUT, XTMP2, XTMP2                       \
+	VPXOR XTMP1, XTMP2, XTMP2                          \
+	VMOVDQU ·P2(SB), XTMP1                             \
+	VPSHUFB XTMP2, XTMP1, XTMP1                        \
+	VPXOR IN_OUT, XTMP1, XTMP1                         \
+	VMOVDQU ·P3(SB), IN_OUT                            \
+	VPSHUFB XTMP1, IN_OUT, IN_OUT                      \
+	VPXOR XTMP2, IN_OUT, IN_OUT                        \
+	VPSLLQ $4, IN_OUT, IN_OUT                          \
+	VPOR XTMP1, IN_OUT, IN_OUT                         \
+	Rotl_5(IN_OUT, XTMP1)
+
+// Perform 8x8 matrix multiplication using lookup tables with partial results
+// for high and low nible of each input byte.
+#define MUL_PSHUFB(XIN, XLO, XHI_OUT, XTMP)            \
+	VPAND ·Low_nibble_mask(SB), XIN, XTMP              \
+	VPSHUFB XTMP, XLO, XLO                             \
+	VPSRLQ $4, XIN, XTMP                               \
+	VPAND ·Low_nibble_mask(SB), XTMP, XTMP             \
+	VPSHUFB XTMP, XHI_OUT, XHI_OUT                     \
+	VPXOR XLO, XHI_OUT, XHI_OUT
+
+// Compute 16 S1 box values from 16 bytes.
+#define S1_comput(XIN_OUT, XTMP1, XTMP2, XTMP3)              \
+	VMOVDQU ·Aes_to_Zuc_mul_low_nibble(SB), XTMP1            \
+	VMOVDQU ·Aes_to_Zuc_mul_high_nibble(SB), XTMP2           \
+	MUL_PSHUFB(XIN_OUT, XTMP1, XTMP2, XTMP3)                 \
+	VPSHUFB ·Shuf_mask(SB), XTMP2, XTMP2                     \
+	VAESENCLAST ·Cancel_aes(SB), XTMP2, XTMP2                \
+	VMOVDQU ·Comb_matrix_mul_low_nibble(SB), XTMP1           \
+	VMOVDQU ·Comb_matrix_mul_high_nibble(SB), XIN_OUT        \
+	MUL_PSHUFB(XTMP2, XTMP1, XIN_OUT, XTMP3)
+
+// S applies S0 to bytes 3/1 and S1 to bytes 2/0 of the 4 words in XIN_OUT.
+#define S_BOX(XIN_OUT, XT0, XT1, XT2, XT3)              \
+	VMOVDQA XIN_OUT, XT0                                \
+	S0_comput(XT0, XT1, XT2)                            \
+	S1_comput(XIN_OUT, XT1, XT2, XT3)                   \
+	VPAND ·mask_S1(SB), XIN_OUT, XIN_OUT                \
+	VPAND ·mask_S0(SB), XT0, XT0                        \
+	VPXOR XT0, XIN_OUT, XIN_OUT
+
+// ACC ^= IN <<< n, 32-bit lanes.
+#define ROLXOR(IN, n, ACC, TMP)   \
+	VPSLLD $(n), IN, TMP          \
+	VPXOR TMP, ACC, ACC           \
+	VPSRLD $(32-(n)), IN, TMP     \
+	VPXOR TMP, ACC, ACC
+
+// ACC = ACC + (IN * 2^n) mod (2^31 - 1), 31-bit lanes.
+#define ADD_ROT31(IN, n, ACC, T0, T1) \
+	VPSLLD $(n), IN, T0               \
+	VPSRLD $(31-(n)), IN, T1          \
+	VPOR T1, T0, T0                   \
+	VPAND MASK31, T0, T0              \
+	ADD31(T0, ACC, T1)
+
+// ACC = ACC + IN mod (2^31 - 1), 31-bit lanes.
+#define ADD31(IN, ACC, TMP) \
+	VPADDD IN, ACC, ACC     \
+	VPSRLD $31, ACC, TMP    \
+	VPAND MASK31, ACC, ACC  \
+	VPADDD TMP, ACC, ACC
+
+// BITS_REORG_8 returns X0 in Y0, X1 in Y1, X2 in Y2 and X3 in Y3.
+#define BITS_REORG_8(idx)            \
+	VMOVDQU ROW(15, idx), Y0         \
+	VMOVDQU ROW(14, idx), Y4         \
+	VPSRLD $15, Y0, Y0               \
+	VPSLLD $16, Y0, Y0               \
+	VPSLLD $16, Y4, Y4               \
+	VPSRLD $16, Y4, Y4               \
+	VPOR Y4, Y0, Y0                  \
+	VMOVDQU ROW(11, idx), Y1         \
+	VMOVDQU ROW(9, idx), Y4          \
+	VPSLLD $16, Y1, Y1               \
+	VPSRLD $15, Y4, Y4               \
+	VPOR Y4, Y1, Y1                  \
+	VMOVDQU ROW(7, idx), Y2          \
+	VMOVDQU ROW(5, idx), Y4          \
+	VPSLLD $16, Y2, Y2               \
+	VPSRLD $15, Y4, Y4               \
+	VPOR Y4, Y2, Y2                  \
+	VMOVDQU ROW(2, idx), Y3          \
+	VMOVDQU ROW(0, idx), Y4          \
+	VPSLLD $16, Y3, Y3               \
+	VPSRLD $15, Y4, Y4               \
+	VPOR Y4, Y3, Y3
+
+// NONLIN_FUN_8 returns W in Y0 and updates R1, R2, uses Y1, Y2, Y4-Y12.
+#define NONLIN_FUN_8                 \
+	VPXOR R1, Y0, Y0                 \
+	VPADDD R2, Y0, Y0                \ // W = (X0 ^ R1) + R2
+	VPADDD R1, Y1, Y1                \ // W1 = R1 + X1
+	VPXOR R2, Y2, Y2                 \ // W2 = R2 ^ X2
+	VPSLLD $16, Y1, Y4               \
+	VPSRLD $16, Y2, Y5               \
+	VPOR Y5, Y4, Y4                  \ // P = W1 << 16 | W2 >> 16
+	VPSLLD $16, Y2, Y5               \
+	VPSRLD $16, Y1, Y6               \
+	VPOR Y6, Y5, Y5                  \ // Q = W2 << 16 | W1 >> 16
+	VMOVDQA Y4, Y6                   \
+	ROLXOR(Y4, 2, Y6, Y7)            \
+	ROLXOR(Y4, 10, Y6, Y7)           \
+	ROLXOR(Y4, 18, Y6, Y7)           \
+	ROLXOR(Y4, 24, Y6, Y7)           \ // U = L1(P)
+	VMOVDQA Y5, Y4                   \
+	ROLXOR(Y4, 8, Y5, Y7)            \
+	ROLXOR(Y4, 14, Y5, Y7)           \
+	ROLXOR(Y4, 22, Y5, Y7)           \
+	ROLXOR(Y4, 30, Y5, Y7)           \ // V = L2(Q)
+	VEXTRACTI128 $1, Y6, X7          \
+	S_BOX(X6, X8, X9, X10, X11)      \
+	S_BOX(X7, X8, X9, X10, X11)      \
+	VINSERTI128 $1, X7, Y6, R1       \ // R1 = S(U)
+	VEXTRACTI128 $1, Y5, X7          \
+	S_BOX(X5, X8, X9, X10, X11)      \
+	S_BOX(X7, X8, X9, X10, X11)      \
+	VINSERTI128 $1, X7, Y5, R2         // R2 = S(V)
+
+// LFSR_UPDT_8 computes the next LFSR cell from ACC (Y0, zero or W >> 1) and
+// overwrites lfsr[idx % 16], uses Y1, Y2, Y4.
+#define LFSR_UPDT_8(idx)                \
+	VMOVDQU ROW(0, idx), Y1             \
+	ADD31(Y1, Y0, Y2)                   \
+	ADD_ROT31(Y1, 8, Y0, Y2, Y4)        \
+	VMOVDQU ROW(4, idx), Y1             \
+	ADD_ROT31(Y1, 20, Y0, Y2, Y4)       \
+	VMOVDQU ROW(10, idx), Y1            \
+	ADD_ROT31(Y1, 21, Y0, Y2, Y4)       \
+	VMOVDQU ROW(13, idx), Y1            \
+	ADD_ROT31(Y1, 17, Y0, Y2, Y4)       \
+	VMOVDQU ROW(15, idx), Y1            \
+	ADD_ROT31(Y1, 15, Y0, Y2, Y4)       \
+	VMOVDQU Y0, ROW(0, idx)
+
+#define INIT_ROUND_8(idx)      \
+	BITS_REORG_8(idx)          \
+	NONLIN_FUN_8               \
+	VPSRLD $1, Y0, Y0          \
+	LFSR_UPDT_8(idx)
+
+#define KEYSTREAM_ROUND_8(idx)     \
+	BITS_REORG_8(idx)              \
+	NONLIN_FUN_8                   \
+	VPXOR Y3, Y0, Y0               \
+	VMOVDQU Y0, (idx*32)(DI)       \
+	VPXOR Y0, Y0, Y0               \
+	LFSR_UPDT_8(idx)
+
+// func initBy8Asm(s *zucState32x8)
+TEXT ·initBy8Asm(SB),NOSPLIT,$0
+	MOVQ s+0(FP), SI
+	VMOVDQU (16*32)(SI), R1
+	VMOVDQU (17*32)(SI), R2
+	VMOVDQU mask31<>(SB), MASK31
+	MOVQ $2, BX
+
+initLoop:
+	INIT_ROUND_8(0)
+	INIT_ROUND_8(1)
+	INIT_ROUND_8(2)
+	INIT_ROUND_8(3)
+	INIT_ROUND_8(4)
+	INIT_ROUND_8(5)
+	INIT_ROUND_8(6)
+	INIT_ROUND_8(7)
+	INIT_ROUND_8(8)
+	INIT_ROUND_8(9)
+	INIT_ROUND_8(10)
+	INIT_ROUND_8(11)
+	INIT_ROUND_8(12)
+	INIT_ROUND_8(13)
+	INIT_ROUND_8(14)
+	INIT_ROUND_8(15)
+	DECQ BX
+	JNZ initLoop
+
+	VMOVDQU R1, (16*32)(SI)
+	VMOVDQU R2, (17*32)(SI)
+	VZEROUPPER
+	RET
+
+// func genKeyStreamBy8Asm(s *zucState32x8, keyStream *uint32, rounds int)
+TEXT ·genKeyStreamBy8Asm(SB),NOSPLIT,$0
+	MOVQ s+0(FP), SI
+	MOVQ keyStream+8(FP), DI
+	MOVQ rounds+16(FP), BX
+	VMOVDQU (16*32)(SI), R1
+	VMOVDQU (17*32)(SI), R2
+	VMOVDQU mask31<>(SB), MASK31
+
+ksLoop:
+	KEYSTREAM_ROUND_8(0)
+	KEYSTREAM_ROUND_8(1)
+	KEYSTREAM_ROUND_8(2)
+	KEYSTREAM_ROUND_8(3)
+	KEYSTREAM_ROUND_8(4)
+	KEYSTREAM_ROUND_8(5)
+	KEYSTREAM_ROUND_8(6)
+	KEYSTREAM_ROUND_8(7)
+	KEYSTREAM_ROUND_8(8)
+	KEYSTREAM_ROUND_8(9)
+	KEYSTREAM_ROUND_8(10)
+	KEYSTREAM_ROUND_8(11)
+	KEYSTREAM_ROUND_8(12)
+	KEYSTREAM_ROUND_8(13)
+	KEYSTREAM_ROUND_8(14)
+	KEYSTREAM_ROUND_8(15)
+	ADDQ $512, DI
+	DECQ BX
+	JNZ ksLoop
+
+	VMOVDQU R1, (16*32)(SI)
+	VMOVDQU R2, (17*32)(SI)
+	VZEROUPPER
+	RET
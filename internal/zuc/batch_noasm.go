@@ -0,0 +1,15 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build purego || !amd64
+
+package zuc
+
+func initBy8(s *zucState32x8) {
+	initBy8Generic(s)
+}
+
+func genKeyStreamBy8(s *zucState32x8, keyStream []uint32, rounds int) {
+	genKeyStreamBy8Generic(s, keyStream, rounds)
+}
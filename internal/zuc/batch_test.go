@@ -0,0 +1,219 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package zuc
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/emmansun/gmsm/internal/byteorder"
+)
+
+func newBatchTestState(t testing.TB, lane int) *zucState32 {
+	key := make([]byte, 16)
+	iv := make([]byte, 16)
+	for i := range key {
+		key[i] = byte(lane*31 + i)
+		iv[i] = byte(lane*7 - i)
+	}
+	var s zucState32
+	if err := s.loadKeyIV(key, iv); err != nil {
+		t.Fatal(err)
+	}
+	return &s
+}
+
+func TestGenKeyStreamBy8(t *testing.T) {
+	var s, s2 zucState32x8
+	for lane := range batchLanes {
+		s.load(lane, newBatchTestState(t, lane))
+	}
+	s2 = s
+	initBy8(&s)
+	initBy8Generic(&s2)
+	if s != s2 {
+		t.Fatal("initialization mismatch")
+	}
+	for _, rounds := range []int{1, 2, 5} {
+		ks := make([]uint32, rounds*batchRoundWords*batchLanes)
+		ks2 := make([]uint32, rounds*batchRoundWords*batchLanes)
+		genKeyStreamBy8(&s, ks, rounds)
+		genKeyStreamBy8Generic(&s2, ks2, rounds)
+		if s != s2 {
+			t.Fatalf("rounds %d: state mismatch", rounds)
+		}
+		for i := range ks {
+			if ks[i] != ks2[i] {
+				t.Fatalf("rounds %d: keyword %d of lane %d = %08x, want %08x", rounds, i/batchLanes, i%batchLanes, ks[i], ks2[i])
+			}
+		}
+	}
+}
+
+func TestXORKeyStreamBatch(t *testing.T) {
+	for _, n := range []int{1, 3, 8, 9, 17} {
+		t.Run(fmt.Sprintf("%d", n), func(t *testing.T) {
+			keys := make([][]byte, n)
+			ivs := make([][]byte, n)
+			src := make([][]byte, n)
+			dst := make([][]byte, n)
+			for i := range n {
+				if i%3 == 2 {
+					keys[i] = make([]byte, 32)
+					ivs[i] = make([]byte, IVSize256)
+				} else {
+					keys[i] = make([]byte, 16)
+					ivs[i] = make([]byte, IVSize128)
+				}
+				rand.Read(keys[i])
+				rand.Read(ivs[i])
+				// include empty, unaligned and multi-chunk lengths
+				src[i] = make([]byte, (i*1237)%9000+i%5)
+				rand.Read(src[i])
+				dst[i] = make([]byte, len(src[i]))
+			}
+			if err := XORKeyStreamBatch(keys, ivs, dst, src); err != nil {
+				t.Fatal(err)
+			}
+			for i := range n {
+				c, err := NewCipher(keys[i], ivs[i])
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := make([]byte, len(src[i]))
+				c.XORKeyStream(want, src[i])
+				if !bytes.Equal(dst[i], want) {
+					t.Fatalf("instance %d (%d bytes) mismatch", i, len(src[i]))
+				}
+			}
+		})
+	}
+}
+
+func TestEEAXORKeyStreamBatch(t *testing.T) {
+	n := len(zucEEATests)
+	keys := make([][]byte, n)
+	counts := make([]uint32, n)
+	bearers := make([]uint32, n)
+	directions := make([]uint32, n)
+	src := make([][]byte, n)
+	dst := make([][]byte, n)
+	for i, test := range zucEEATests {
+		keys[i], _ = hex.DecodeString(test.key)
+		counts[i], bearers[i], directions[i] = test.count, test.bearer, test.direction
+		src[i], _ = hex.DecodeString(test.in)
+		dst[i] = make([]byte, len(src[i]))
+	}
+	if err := EEAXORKeyStreamBatch(keys, counts, bearers, directions, dst, src); err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range zucEEATests {
+		if got := hex.EncodeToString(dst[i]); got != test.out {
+			t.Errorf("case %d: got %s, want %s", i, got, test.out)
+		}
+	}
+	if err := EEAXORKeyStreamBatch(keys, counts[:1], bearers, directions, dst, src); err == nil {
+		t.Error("expected error for mismatched batch sizes")
+	}
+}
+
+func TestMACBatch(t *testing.T) {
+	n := 21
+	keys := make([][]byte, n)
+	ivs := make([][]byte, n)
+	msgs := make([][]byte, n)
+	nbits := make([]int, n)
+	for i := range n {
+		keys[i] = make([]byte, 16)
+		ivs[i] = make([]byte, IVSize128)
+		rand.Read(keys[i])
+		rand.Read(ivs[i])
+		msgs[i] = make([]byte, i*97%1500)
+		rand.Read(msgs[i])
+		nbits[i] = len(msgs[i]) * 8
+		if i%2 == 1 && nbits[i] > 0 {
+			nbits[i] -= i % 8
+		}
+	}
+	macs, err := MACBatch(keys, ivs, msgs, nbits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range n {
+		h, err := NewHash(keys[i], ivs[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := h.Finish(msgs[i], nbits[i])
+		if !bytes.Equal(macs[i], want) {
+			t.Errorf("instance %d (%d bits): got %x, want %x", i, nbits[i], macs[i], want)
+		}
+	}
+}
+
+func TestEIAMACBatch(t *testing.T) {
+	n := len(zucEIATests)
+	keys := make([][]byte, n)
+	counts := make([]uint32, n)
+	bearers := make([]uint32, n)
+	directions := make([]uint32, n)
+	msgs := make([][]byte, n)
+	nbits := make([]int, n)
+	for i, test := range zucEIATests {
+		keys[i], counts[i], bearers[i], directions[i] = test.key, test.count, test.bearer, test.direction
+		for _, w := range test.in {
+			msgs[i] = byteorder.BEAppendUint32(msgs[i], w)
+		}
+		nbits[i] = test.nbits
+	}
+	macs, err := EIAMACBatch(keys, counts, bearers, directions, msgs, nbits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, test := range zucEIATests {
+		if got := fmt.Sprintf("%x", macs[i]); got != test.mac {
+			t.Errorf("case %d: got %s, want %s", i, got, test.mac)
+		}
+	}
+}
+
+func BenchmarkEEAXORKeyStreamBatch(b *testing.B) {
+	const n, size = 64, 1500
+	keys := make([][]byte, n)
+	counts := make([]uint32, n)
+	bearers := make([]uint32, n)
+	directions := make([]uint32, n)
+	src := make([][]byte, n)
+	dst := make([][]byte, n)
+	for i := range n {
+		keys[i] = make([]byte, 16)
+		counts[i] = uint32(i)
+		src[i] = make([]byte, size)
+		dst[i] = make([]byte, size)
+	}
+	b.SetBytes(n * size)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		EEAXORKeyStreamBatch(keys, counts, bearers, directions, dst, src)
+	}
+}
+
+func BenchmarkEEAXORKeyStreamSingle(b *testing.B) {
+	const n, size = 64, 1500
+	key := make([]byte, 16)
+	src := make([]byte, size)
+	dst := make([]byte, size)
+	b.SetBytes(n * size)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range n {
+			c, _ := NewEEACipher(key, uint32(j), 0, 0)
+			c.XORKeyStream(dst, src)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package zuc
+
+import "errors"
+
+// XORKeyStreamBits XORs the first nbits bits of src with the keystream of c
+// and writes them to dst. The unused trailing bits of the last output byte
+// are set to zero.
+func XORKeyStreamBits(c *eea, dst, src []byte, nbits int) error {
+	if nbits < 0 {
+		return errors.New("zuc: negative bit length")
+	}
+	n := (nbits + 7) / 8
+	if len(src) < n {
+		return errors.New("zuc: input shorter than bit length")
+	}
+	if len(dst) < n {
+		return errors.New("zuc: output shorter than bit length")
+	}
+	c.XORKeyStream(dst[:n], src[:n])
+	if r := nbits % 8; r != 0 {
+		dst[n-1] &= byte(0xff << (8 - r))
+	}
+	return nil
+}
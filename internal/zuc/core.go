@@ -207,23 +207,32 @@ func (k IVSizeError) Error() string {
 	return "zuc: invalid IV size " + strconv.Itoa(int(k))
 }
 
-func newZUCState(key, iv []byte) (*zucState32, error) {
+// loadKeyIV loads the key and iv into the LFSR, the state still needs
+// to be initialized.
+func (s *zucState32) loadKeyIV(key, iv []byte) error {
 	k := len(key)
 	ivLen := len(iv)
-	state := &zucState32{}
 	switch k {
 	default:
-		return nil, KeySizeError(k)
+		return KeySizeError(k)
 	case 16: // ZUC-128
 		if ivLen != IVSize128 {
-			return nil, IVSizeError(ivLen)
+			return IVSizeError(ivLen)
 		}
-		state.loadKeyIV16(key, iv)
+		s.loadKeyIV16(key, iv)
 	case 32: // ZUC-256
 		if ivLen != IVSize256 {
-			return nil, IVSizeError(ivLen)
+			return IVSizeError(ivLen)
 		}
-		state.loadKeyIV32(key, iv, zuc256_d0[:])
+		s.loadKeyIV32(key, iv, zuc256_d0[:])
+	}
+	return nil
+}
+
+func newZUCState(key, iv []byte) (*zucState32, error) {
+	state := &zucState32{}
+	if err := state.loadKeyIV(key, iv); err != nil {
+		return nil, err
 	}
 
 	// initialization
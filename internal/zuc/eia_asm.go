@@ -22,3 +22,15 @@ func block(m *ZUC128Mac, p []byte) {
 		blockGeneric(m, p)
 	}
 }
+
+// blockKeyStream is like block, but takes the keywords from keyStream.
+func blockKeyStream(t *uint32, keyStream []uint32, p []byte) {
+	if supportsGFMUL {
+		for i := 0; len(p) >= chunk; i += 4 {
+			eiaRoundTag4(t, &keyStream[i], &p[0])
+			p = p[chunk:]
+		}
+	} else {
+		blockKeyStreamGeneric(t, keyStream, p)
+	}
+}
@@ -5,3 +5,7 @@ package zuc
 func block(m *ZUC128Mac, p []byte) {
 	blockGeneric(m, p)
 }
+
+func blockKeyStream(t *uint32, keyStream []uint32, p []byte) {
+	blockKeyStreamGeneric(t, keyStream, p)
+}
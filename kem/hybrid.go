@@ -0,0 +1,179 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package kem
+
+import (
+	"crypto/ecdh"
+	"errors"
+	"io"
+
+	gmecdh "github.com/emmansun/gmsm/ecdh"
+	"github.com/emmansun/gmsm/mlkem"
+	"github.com/emmansun/gmsm/tls13"
+)
+
+// The TLS 1.3 hybrid groups as KEMs: the public key is the key share of the
+// client, the ciphertext the key share of the server and the shared key the
+// shared secret of the key exchange, the concatenation of the ECDH shared
+// secret and the ML-KEM shared key. The concatenation doesn't bind the
+// ciphertext, which TLS 1.3 does with the transcript; the other protocols
+// should prefer [XWing] or [SM2Wing].
+
+type hybridParams struct {
+	group         tls13.CurveID
+	classicalSize int  // the size of the classical private key
+	mlkemFirst    bool // the order of the key shares
+	// checkPublicKey checks the classical public key and the ML-KEM
+	// encapsulation key of a key share
+	checkPublicKey func(b []byte) error
+}
+
+// checkKeyShare returns the check of a key share of the classical public key
+// of size classicalSize and the ML-KEM encapsulation key of size mlkemSize.
+func checkKeyShare(classicalSize, mlkemSize int, mlkemFirst bool, classical, m func([]byte) error) func([]byte) error {
+	return func(b []byte) error {
+		if len(b) != classicalSize+mlkemSize {
+			return errors.New("kem: invalid hybrid public key size")
+		}
+		c, e := b[:classicalSize], b[classicalSize:]
+		if mlkemFirst {
+			e, c = b[:mlkemSize], b[mlkemSize:]
+		}
+		if err := classical(c); err != nil {
+			return err
+		}
+		return m(e)
+	}
+}
+
+func checkStdlib(c ecdh.Curve) func([]byte) error {
+	return func(b []byte) error {
+		_, err := c.NewPublicKey(b)
+		return err
+	}
+}
+
+func checkSM2(b []byte) error {
+	_, err := gmecdh.P256().NewPublicKey(b)
+	return err
+}
+
+func checkMLKEM768(b []byte) error {
+	_, err := mlkem.NewEncapsulationKey768(b)
+	return err
+}
+
+func checkMLKEM1024(b []byte) error {
+	_, err := mlkem.NewEncapsulationKey1024(b)
+	return err
+}
+
+func hybridParamsOf(a Algorithm) *hybridParams {
+	switch a {
+	case X25519MLKEM768:
+		return &hybridParams{tls13.X25519MLKEM768, 32, true,
+			checkKeyShare(32, mlkem.EncapsulationKeySize768, true, checkStdlib(ecdh.X25519()), checkMLKEM768)}
+	case SecP256r1MLKEM768:
+		return &hybridParams{tls13.SecP256r1MLKEM768, 32, false,
+			checkKeyShare(65, mlkem.EncapsulationKeySize768, false, checkStdlib(ecdh.P256()), checkMLKEM768)}
+	case SecP384r1MLKEM1024:
+		return &hybridParams{tls13.SecP384r1MLKEM1024, 48, false,
+			checkKeyShare(97, mlkem.EncapsulationKeySize1024, false, checkStdlib(ecdh.P384()), checkMLKEM1024)}
+	case SM2MLKEM768:
+		return &hybridParams{tls13.SM2MLKEM768, 32, false,
+			checkKeyShare(65, mlkem.EncapsulationKeySize768, false, checkSM2, checkMLKEM768)}
+	default:
+		return nil
+	}
+}
+
+type hybridEncapsulator struct {
+	alg Algorithm
+	kex tls13.KeyExchange
+	pk  []byte
+}
+
+func parseHybridEncapsulator(a Algorithm, b []byte) (Encapsulator, error) {
+	p := hybridParamsOf(a)
+	kex, err := tls13.NewKeyExchange(p.group)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkPublicKey(b); err != nil {
+		return nil, err
+	}
+	return &hybridEncapsulator{alg: a, kex: kex, pk: append([]byte{}, b...)}, nil
+}
+
+func (e *hybridEncapsulator) Algorithm() Algorithm { return e.alg }
+
+func (e *hybridEncapsulator) Bytes() []byte { return append([]byte{}, e.pk...) }
+
+func (e *hybridEncapsulator) Encapsulate(rand io.Reader) ([]byte, []byte, error) {
+	sharedKey, share, err := e.kex.ServerSharedSecret(rand, e.pk)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sharedKey, share.Data, nil
+}
+
+type hybridDecapsulator struct {
+	b    []byte
+	keys *tls13.KeySharePrivateKeys
+	enc  *hybridEncapsulator
+}
+
+func generateHybrid(rand io.Reader, a Algorithm) (Decapsulator, error) {
+	p := hybridParamsOf(a)
+	b := make([]byte, p.classicalSize+mlkem.SeedSize)
+	for {
+		if _, err := io.ReadFull(rand, b); err != nil {
+			return nil, err
+		}
+		// the classical private key is rejected with a probability of
+		// about 2^-32 for P-256 and SM2, and a negligible one otherwise
+		if _, err := tls13.NewKeySharePrivateKeys(p.group, b[:p.classicalSize], b[p.classicalSize:]); err == nil {
+			break
+		}
+	}
+	return parseHybridDecapsulator(a, b)
+}
+
+func parseHybridDecapsulator(a Algorithm, b []byte) (Decapsulator, error) {
+	p := hybridParamsOf(a)
+	if len(b) != p.classicalSize+mlkem.SeedSize {
+		return nil, errors.New("kem: invalid hybrid private key size")
+	}
+	keys, err := tls13.NewKeySharePrivateKeys(p.group, b[:p.classicalSize], b[p.classicalSize:])
+	if err != nil {
+		return nil, err
+	}
+	kex, err := tls13.NewKeyExchange(p.group)
+	if err != nil {
+		return nil, err
+	}
+	classical, ek := keys.ECDHE.PublicKeyBytes(), keys.MLKEM.EncapsulationKeyBytes()
+	var pk []byte
+	if p.mlkemFirst {
+		pk = append(append(pk, ek...), classical...)
+	} else {
+		pk = append(append(pk, classical...), ek...)
+	}
+	return &hybridDecapsulator{
+		b:    append([]byte{}, b...),
+		keys: keys,
+		enc:  &hybridEncapsulator{alg: a, kex: kex, pk: pk},
+	}, nil
+}
+
+func (d *hybridDecapsulator) Algorithm() Algorithm { return d.enc.alg }
+
+func (d *hybridDecapsulator) Bytes() []byte { return append([]byte{}, d.b...) }
+
+func (d *hybridDecapsulator) Encapsulator() Encapsulator { return d.enc }
+
+func (d *hybridDecapsulator) Decapsulate(ciphertext []byte) ([]byte, error) {
+	return d.enc.kex.ClientSharedSecret(d.keys, ciphertext)
+}
@@ -0,0 +1,305 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package kem defines a common interface for the key encapsulation mechanisms
+// of this module, so that the envelopes and the file formats handle them
+// uniformly:
+//
+//   - ML-KEM-512, ML-KEM-768 and ML-KEM-1024 of package mlkem;
+//   - SM2, where the shared key is a random 32-byte key encrypted with
+//     [sm2.EncryptASN1], as in the SM2 key transport of CMS;
+//   - SM9, with the key encapsulation of [sm9.WrapKey] bound to the identity
+//     of the recipient;
+//   - X-Wing and SM2-Wing of package xwing;
+//   - the TLS 1.3 hybrid groups of package tls13.
+//
+// Each algorithm defines the serializations of its keys, see
+// [Algorithm.NewEncapsulator] and [Algorithm.NewDecapsulator].
+package kem
+
+import (
+	"encoding/asn1"
+	"errors"
+	"io"
+
+	"github.com/emmansun/gmsm/mlkem"
+	"github.com/emmansun/gmsm/xwing"
+)
+
+// Encapsulator is the public key of a KEM.
+type Encapsulator interface {
+	// Algorithm returns the algorithm of the key.
+	Algorithm() Algorithm
+	// Bytes returns the serialization of the key.
+	Bytes() []byte
+	// Encapsulate generates a shared key and its ciphertext for the key.
+	Encapsulate(rand io.Reader) (sharedKey, ciphertext []byte, err error)
+}
+
+// Decapsulator is the private key of a KEM.
+type Decapsulator interface {
+	// Algorithm returns the algorithm of the key.
+	Algorithm() Algorithm
+	// Bytes returns the serialization of the key, which must be kept secret.
+	Bytes() []byte
+	// Encapsulator returns the public key of the key.
+	Encapsulator() Encapsulator
+	// Decapsulate returns the shared key encapsulated in the ciphertext.
+	Decapsulate(ciphertext []byte) (sharedKey []byte, err error)
+}
+
+// Algorithm is the identifier of a KEM.
+type Algorithm int
+
+const (
+	MLKEM512 Algorithm = iota + 1
+	MLKEM768
+	MLKEM1024
+	SM2
+	SM9
+	XWing
+	SM2Wing
+	X25519MLKEM768     // the TLS 1.3 hybrid group of the same name
+	SecP256r1MLKEM768  // the TLS 1.3 hybrid group of the same name
+	SecP384r1MLKEM1024 // the TLS 1.3 hybrid group of the same name
+	SM2MLKEM768        // the TLS 1.3 hybrid group of the same name
+)
+
+var (
+	oidMLKEM512  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 1}
+	oidMLKEM768  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 2}
+	oidMLKEM1024 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 3}
+	oidSM2       = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 301, 3}
+	oidSM9       = asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 302, 3}
+	oidXWing     = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 62253, 25722}
+)
+
+var algorithms = []struct {
+	alg  Algorithm
+	name string
+	oid  asn1.ObjectIdentifier
+}{
+	{MLKEM512, "ML-KEM-512", oidMLKEM512},
+	{MLKEM768, "ML-KEM-768", oidMLKEM768},
+	{MLKEM1024, "ML-KEM-1024", oidMLKEM1024},
+	{SM2, "SM2", oidSM2},
+	{SM9, "SM9", oidSM9},
+	{XWing, "X-Wing", oidXWing},
+	{SM2Wing, "SM2-Wing", nil},
+	{X25519MLKEM768, "X25519MLKEM768", nil},
+	{SecP256r1MLKEM768, "SecP256r1MLKEM768", nil},
+	{SecP384r1MLKEM1024, "SecP384r1MLKEM1024", nil},
+	{SM2MLKEM768, "SM2MLKEM768", nil},
+}
+
+// String returns the name of the algorithm.
+func (a Algorithm) String() string {
+	for _, v := range algorithms {
+		if v.alg == a {
+			return v.name
+		}
+	}
+	return "unknown KEM"
+}
+
+// OID returns the object identifier of the algorithm, or nil if it has none:
+// the NIST identifiers of ML-KEM, sm2encrypt and sm9encrypt of GM/T 0006, and
+// the identifier of X-Wing of draft-connolly-cfrg-xwing-kem.
+func (a Algorithm) OID() asn1.ObjectIdentifier {
+	for _, v := range algorithms {
+		if v.alg == a && v.oid != nil {
+			return append(asn1.ObjectIdentifier{}, v.oid...)
+		}
+	}
+	return nil
+}
+
+// AlgorithmFromOID returns the algorithm of the object identifier.
+func AlgorithmFromOID(oid asn1.ObjectIdentifier) (Algorithm, error) {
+	for _, v := range algorithms {
+		if v.oid != nil && v.oid.Equal(oid) {
+			return v.alg, nil
+		}
+	}
+	return 0, errors.New("kem: unknown algorithm identifier")
+}
+
+// GenerateKey generates a private key of the algorithm. The SM9 private keys
+// are extracted by the key generation center, see [NewSM9Decapsulator].
+func (a Algorithm) GenerateKey(rand io.Reader) (Decapsulator, error) {
+	switch a {
+	case MLKEM512:
+		dk, err := mlkem.GenerateKey512(rand)
+		if err != nil {
+			return nil, err
+		}
+		return newDecapsulator(a, dk.Seed(), dk, dk.EncapsulationKey()), nil
+	case MLKEM768:
+		dk, err := mlkem.GenerateKey768(rand)
+		if err != nil {
+			return nil, err
+		}
+		return newDecapsulator(a, dk.Seed(), dk, dk.EncapsulationKey()), nil
+	case MLKEM1024:
+		dk, err := mlkem.GenerateKey1024(rand)
+		if err != nil {
+			return nil, err
+		}
+		return newDecapsulator(a, dk.Seed(), dk, dk.EncapsulationKey()), nil
+	case SM2:
+		return generateSM2(rand)
+	case XWing:
+		dk, err := xwing.GenerateKey(rand)
+		if err != nil {
+			return nil, err
+		}
+		return newDecapsulator(a, dk.Bytes(), dk, dk.EncapsulationKey()), nil
+	case SM2Wing:
+		dk, err := xwing.GenerateKeySM2(rand)
+		if err != nil {
+			return nil, err
+		}
+		return newDecapsulator(a, dk.Bytes(), dk, dk.EncapsulationKey()), nil
+	case X25519MLKEM768, SecP256r1MLKEM768, SecP384r1MLKEM1024, SM2MLKEM768:
+		return generateHybrid(rand, a)
+	case SM9:
+		return nil, errors.New("kem: SM9 private keys are extracted by the key generation center")
+	default:
+		return nil, errors.New("kem: unsupported algorithm")
+	}
+}
+
+// NewEncapsulator parses the serialization of a public key of the algorithm:
+//
+//   - the encapsulation key of ML-KEM, X-Wing and SM2-Wing;
+//   - the uncompressed point of SM2;
+//   - the key share of the client of the TLS 1.3 hybrid groups;
+//   - an ASN.1 SEQUENCE of the raw master public key as a BIT STRING, the
+//     identity as an OCTET STRING and the hid as an INTEGER for SM9.
+func (a Algorithm) NewEncapsulator(b []byte) (Encapsulator, error) {
+	var ek encapsulationKey
+	var err error
+	switch a {
+	case MLKEM512:
+		ek, err = mlkem.NewEncapsulationKey512(b)
+	case MLKEM768:
+		ek, err = mlkem.NewEncapsulationKey768(b)
+	case MLKEM1024:
+		ek, err = mlkem.NewEncapsulationKey1024(b)
+	case XWing:
+		ek, err = xwing.NewEncapsulationKey(b)
+	case SM2Wing:
+		ek, err = xwing.NewEncapsulationKeySM2(b)
+	case SM2:
+		return parseSM2Encapsulator(b)
+	case SM9:
+		return parseSM9Encapsulator(b)
+	case X25519MLKEM768, SecP256r1MLKEM768, SecP384r1MLKEM1024, SM2MLKEM768:
+		return parseHybridEncapsulator(a, b)
+	default:
+		return nil, errors.New("kem: unsupported algorithm")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &encapsulator{alg: a, ek: ek}, nil
+}
+
+// NewDecapsulator parses the serialization of a private key of the
+// algorithm:
+//
+//   - the 64-byte seed of ML-KEM and the 32-byte seed of X-Wing and SM2-Wing;
+//   - the 32-byte private key of SM2;
+//   - the classical private key || the ML-KEM seed of the TLS 1.3 hybrid
+//     groups;
+//   - an ASN.1 SEQUENCE of the raw private key and the raw master public key
+//     as BIT STRINGs, the identity as an OCTET STRING and the hid as an
+//     INTEGER for SM9.
+func (a Algorithm) NewDecapsulator(b []byte) (Decapsulator, error) {
+	switch a {
+	case MLKEM512:
+		dk, err := mlkem.NewDecapsulationKeyFromSeed512(b)
+		if err != nil {
+			return nil, err
+		}
+		return newDecapsulator(a, b, dk, dk.EncapsulationKey()), nil
+	case MLKEM768:
+		dk, err := mlkem.NewDecapsulationKeyFromSeed768(b)
+		if err != nil {
+			return nil, err
+		}
+		return newDecapsulator(a, b, dk, dk.EncapsulationKey()), nil
+	case MLKEM1024:
+		dk, err := mlkem.NewDecapsulationKeyFromSeed1024(b)
+		if err != nil {
+			return nil, err
+		}
+		return newDecapsulator(a, b, dk, dk.EncapsulationKey()), nil
+	case XWing:
+		dk, err := xwing.NewDecapsulationKeyFromSeed(b)
+		if err != nil {
+			return nil, err
+		}
+		return newDecapsulator(a, b, dk, dk.EncapsulationKey()), nil
+	case SM2Wing:
+		dk, err := xwing.NewDecapsulationKeyFromSeedSM2(b)
+		if err != nil {
+			return nil, err
+		}
+		return newDecapsulator(a, b, dk, dk.EncapsulationKey()), nil
+	case SM2:
+		return parseSM2Decapsulator(b)
+	case SM9:
+		return parseSM9Decapsulator(b)
+	case X25519MLKEM768, SecP256r1MLKEM768, SecP384r1MLKEM1024, SM2MLKEM768:
+		return parseHybridDecapsulator(a, b)
+	default:
+		return nil, errors.New("kem: unsupported algorithm")
+	}
+}
+
+// encapsulationKey is the encapsulation key of package mlkem and xwing.
+type encapsulationKey interface {
+	Bytes() []byte
+	Encapsulate(rand io.Reader) (sharedKey, ciphertext []byte, err error)
+}
+
+type encapsulator struct {
+	alg Algorithm
+	ek  encapsulationKey
+}
+
+func (e *encapsulator) Algorithm() Algorithm { return e.alg }
+
+func (e *encapsulator) Bytes() []byte { return e.ek.Bytes() }
+
+func (e *encapsulator) Encapsulate(rand io.Reader) ([]byte, []byte, error) {
+	return e.ek.Encapsulate(rand)
+}
+
+// decapsulationKey is the decapsulation key of package mlkem and xwing.
+type decapsulationKey interface {
+	Decapsulate(ciphertext []byte) (sharedKey []byte, err error)
+}
+
+type decapsulator struct {
+	alg Algorithm
+	b   []byte
+	dk  decapsulationKey
+	ek  *encapsulator
+}
+
+func newDecapsulator(alg Algorithm, b []byte, dk decapsulationKey, ek encapsulationKey) *decapsulator {
+	return &decapsulator{alg: alg, b: append([]byte{}, b...), dk: dk, ek: &encapsulator{alg: alg, ek: ek}}
+}
+
+func (d *decapsulator) Algorithm() Algorithm { return d.alg }
+
+func (d *decapsulator) Bytes() []byte { return append([]byte{}, d.b...) }
+
+func (d *decapsulator) Encapsulator() Encapsulator { return d.ek }
+
+func (d *decapsulator) Decapsulate(ciphertext []byte) ([]byte, error) {
+	return d.dk.Decapsulate(ciphertext)
+}
@@ -0,0 +1,259 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package kem
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/emmansun/gmsm/mlkem"
+	"github.com/emmansun/gmsm/sm2"
+	"github.com/emmansun/gmsm/sm9"
+	"github.com/emmansun/gmsm/tls13"
+)
+
+func newSM9Key(t testing.TB) Decapsulator {
+	master, err := sm9.GenerateEncryptMasterKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uid := []byte("Alice")
+	priv, err := master.GenerateUserKey(uid, 0x03)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := NewSM9Decapsulator(master.PublicKey(), priv, uid, 0x03)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func generateKey(t testing.TB, a Algorithm) Decapsulator {
+	if a == SM9 {
+		return newSM9Key(t)
+	}
+	d, err := a.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+var allAlgorithms = []struct {
+	alg           Algorithm
+	sharedKeySize int
+}{
+	{MLKEM512, 32},
+	{MLKEM768, 32},
+	{MLKEM1024, 32},
+	{SM2, 32},
+	{SM9, 32},
+	{XWing, 32},
+	{SM2Wing, 32},
+	{X25519MLKEM768, 64},
+	{SecP256r1MLKEM768, 64},
+	{SecP384r1MLKEM1024, 80},
+	{SM2MLKEM768, 64},
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, tc := range allAlgorithms {
+		t.Run(tc.alg.String(), func(t *testing.T) {
+			d := generateKey(t, tc.alg)
+			// the keys go through their serializations
+			d, err := tc.alg.NewDecapsulator(d.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			e, err := tc.alg.NewEncapsulator(d.Encapsulator().Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d.Algorithm() != tc.alg || e.Algorithm() != tc.alg {
+				t.Errorf("algorithms %v and %v", d.Algorithm(), e.Algorithm())
+			}
+			if !bytes.Equal(e.Bytes(), d.Encapsulator().Bytes()) {
+				t.Error("public key serialization mismatch")
+			}
+			sharedKey, ciphertext, err := e.Encapsulate(rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(sharedKey) != tc.sharedKeySize {
+				t.Errorf("shared key size = %d, want %d", len(sharedKey), tc.sharedKeySize)
+			}
+			got, err := d.Decapsulate(ciphertext)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, sharedKey) {
+				t.Errorf("decapsulated %x, want %x", got, sharedKey)
+			}
+			// another private key doesn't recover the shared key
+			other := generateKey(t, tc.alg)
+			if got, err := other.Decapsulate(ciphertext); err == nil && bytes.Equal(got, sharedKey) {
+				t.Error("another private key decapsulated the shared key")
+			}
+		})
+	}
+}
+
+// TestInterop checks that the adapters use the formats of the underlying
+// packages.
+func TestInterop(t *testing.T) {
+	// ML-KEM
+	dk, _ := mlkem.GenerateKey768(rand.Reader)
+	d, err := MLKEM768.NewDecapsulator(dk.Seed())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sharedKey, ciphertext, err := d.Encapsulator().Encapsulate(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := dk.Decapsulate(ciphertext); err != nil || !bytes.Equal(got, sharedKey) {
+		t.Errorf("ML-KEM-768: %x, %v", got, err)
+	}
+
+	// SM2 encryption
+	priv, _ := sm2.GenerateKey(rand.Reader)
+	e, err := NewSM2Encapsulator(&priv.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sharedKey, ciphertext, err = e.Encapsulate(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := priv.Decrypt(nil, ciphertext, sm2.ASN1DecrypterOpts); err != nil || !bytes.Equal(got, sharedKey) {
+		t.Errorf("SM2: %x, %v", got, err)
+	}
+	d, _ = NewSM2Decapsulator(priv)
+	if !bytes.Equal(d.Bytes(), priv.D.FillBytes(make([]byte, 32))) {
+		t.Error("SM2 private key serialization mismatch")
+	}
+
+	// SM9 key wrapping
+	master, _ := sm9.GenerateEncryptMasterKey(rand.Reader)
+	userKey, _ := master.GenerateUserKey([]byte("Bob"), 0x03)
+	e, _ = NewSM9Encapsulator(master.PublicKey(), []byte("Bob"), 0x03)
+	sharedKey, ciphertext, err = e.Encapsulate(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := sm9.UnwrapKey(userKey, []byte("Bob"), ciphertext, 32); err != nil || !bytes.Equal(got, sharedKey) {
+		t.Errorf("SM9: %x, %v", got, err)
+	}
+	// the ciphertext is bound to the identity
+	if got, err := sm9.UnwrapKey(userKey, []byte("Carol"), ciphertext, 32); err == nil && bytes.Equal(got, sharedKey) {
+		t.Error("SM9: the shared key is not bound to the identity")
+	}
+
+	// TLS 1.3 hybrid group, the decapsulator is the client
+	d, _ = SM2MLKEM768.GenerateKey(rand.Reader)
+	kex, _ := tls13.NewKeyExchange(tls13.SM2MLKEM768)
+	sharedKey, share, err := kex.ServerSharedSecret(rand.Reader, d.Encapsulator().Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := d.Decapsulate(share.Data); err != nil || !bytes.Equal(got, sharedKey) {
+		t.Errorf("SM2MLKEM768: %x, %v", got, err)
+	}
+}
+
+func TestOID(t *testing.T) {
+	for _, tc := range []struct {
+		alg Algorithm
+		oid asn1.ObjectIdentifier
+	}{
+		{MLKEM512, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 1}},
+		{MLKEM768, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 2}},
+		{MLKEM1024, asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 4, 3}},
+		{SM2, asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 301, 3}},
+		{SM9, asn1.ObjectIdentifier{1, 2, 156, 10197, 1, 302, 3}},
+		{XWing, asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 62253, 25722}},
+	} {
+		if !tc.alg.OID().Equal(tc.oid) {
+			t.Errorf("%v: OID = %v, want %v", tc.alg, tc.alg.OID(), tc.oid)
+		}
+		alg, err := AlgorithmFromOID(tc.oid)
+		if err != nil || alg != tc.alg {
+			t.Errorf("%v: AlgorithmFromOID = %v, %v", tc.oid, alg, err)
+		}
+	}
+	if SM2Wing.OID() != nil || SM2MLKEM768.OID() != nil {
+		t.Error("unexpected OID")
+	}
+	if _, err := AlgorithmFromOID(asn1.ObjectIdentifier{1, 2, 3}); err == nil {
+		t.Error("expected error with an unknown OID")
+	}
+	if Algorithm(0).String() != "unknown KEM" || SM2MLKEM768.String() != "SM2MLKEM768" {
+		t.Error("unexpected names")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, err := SM9.GenerateKey(rand.Reader); err == nil {
+		t.Error("expected error generating an SM9 key")
+	}
+	if _, err := Algorithm(0).GenerateKey(rand.Reader); err == nil {
+		t.Error("expected error with an unknown algorithm")
+	}
+	for _, tc := range allAlgorithms {
+		if _, err := tc.alg.NewEncapsulator(make([]byte, 10)); err == nil {
+			t.Errorf("%v: expected error with an invalid public key", tc.alg)
+		}
+		if _, err := tc.alg.NewDecapsulator(make([]byte, 10)); err == nil {
+			t.Errorf("%v: expected error with an invalid private key", tc.alg)
+		}
+		// the public key of another algorithm
+		other := MLKEM768
+		if tc.alg == MLKEM768 {
+			other = SM2MLKEM768
+		}
+		pk := generateKey(t, other).Encapsulator().Bytes()
+		if _, err := tc.alg.NewEncapsulator(pk); err == nil {
+			t.Errorf("%v: expected error with a public key of %v", tc.alg, other)
+		}
+	}
+	// the SM2 component of an SM2MLKEM768 public key is not on the curve
+	pk := generateKey(t, SM2MLKEM768).Encapsulator().Bytes()
+	pk[64] ^= 1
+	if _, err := SM2MLKEM768.NewEncapsulator(pk); err == nil {
+		t.Error("expected error with an invalid SM2 point")
+	}
+	d := generateKey(t, SM2)
+	if _, err := d.Decapsulate([]byte("invalid")); err == nil {
+		t.Error("expected error with an invalid SM2 ciphertext")
+	}
+	// the SM2 ciphertext of a key of another size
+	ciphertext, err := sm2.EncryptASN1(rand.Reader, &d.(*sm2Decapsulator).priv.PublicKey, []byte("short key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Decapsulate(ciphertext); err == nil {
+		t.Error("expected error with a short SM2 shared key")
+	}
+	if _, err := NewSM9Encapsulator(nil, []byte("Alice"), 3); err == nil {
+		t.Error("expected error with nil SM9 master public key")
+	}
+}
+
+func BenchmarkEncapsulate(b *testing.B) {
+	for _, tc := range allAlgorithms {
+		e := generateKey(b, tc.alg).Encapsulator()
+		b.Run(tc.alg.String(), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, _, err := e.Encapsulate(rand.Reader); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package kem
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"errors"
+	"io"
+
+	"github.com/emmansun/gmsm/sm2"
+	"github.com/emmansun/gmsm/sm9"
+	"golang.org/x/crypto/cryptobyte"
+	cryptobyte_asn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+const (
+	sm2SharedKeySize = 32
+	sm9SharedKeySize = 32
+)
+
+type sm2Encapsulator struct {
+	pub *ecdsa.PublicKey
+}
+
+// NewSM2Encapsulator returns the SM2 [Encapsulator] of the public key.
+func NewSM2Encapsulator(pub *ecdsa.PublicKey) (Encapsulator, error) {
+	if pub == nil {
+		return nil, errors.New("kem: invalid SM2 public key")
+	}
+	if _, err := sm2.PublicKeyToECDH(pub); err != nil {
+		return nil, err
+	}
+	return &sm2Encapsulator{pub: pub}, nil
+}
+
+func parseSM2Encapsulator(b []byte) (Encapsulator, error) {
+	pub, err := sm2.NewPublicKey(b)
+	if err != nil {
+		return nil, err
+	}
+	return &sm2Encapsulator{pub: pub}, nil
+}
+
+func (e *sm2Encapsulator) Algorithm() Algorithm { return SM2 }
+
+func (e *sm2Encapsulator) Bytes() []byte {
+	return elliptic.Marshal(e.pub.Curve, e.pub.X, e.pub.Y)
+}
+
+// Encapsulate encrypts a random key with the public key.
+func (e *sm2Encapsulator) Encapsulate(rand io.Reader) ([]byte, []byte, error) {
+	key := make([]byte, sm2SharedKeySize)
+	if _, err := io.ReadFull(rand, key); err != nil {
+		return nil, nil, err
+	}
+	ciphertext, err := sm2.EncryptASN1(rand, e.pub, key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, ciphertext, nil
+}
+
+type sm2Decapsulator struct {
+	priv *sm2.PrivateKey
+}
+
+// NewSM2Decapsulator returns the SM2 [Decapsulator] of the private key.
+func NewSM2Decapsulator(priv *sm2.PrivateKey) (Decapsulator, error) {
+	if priv == nil {
+		return nil, errors.New("kem: invalid SM2 private key")
+	}
+	return &sm2Decapsulator{priv: priv}, nil
+}
+
+func generateSM2(rand io.Reader) (Decapsulator, error) {
+	priv, err := sm2.GenerateKey(rand)
+	if err != nil {
+		return nil, err
+	}
+	return &sm2Decapsulator{priv: priv}, nil
+}
+
+func parseSM2Decapsulator(b []byte) (Decapsulator, error) {
+	priv, err := sm2.NewPrivateKey(b)
+	if err != nil {
+		return nil, err
+	}
+	return &sm2Decapsulator{priv: priv}, nil
+}
+
+func (d *sm2Decapsulator) Algorithm() Algorithm { return SM2 }
+
+func (d *sm2Decapsulator) Bytes() []byte {
+	return d.priv.D.FillBytes(make([]byte, 32))
+}
+
+func (d *sm2Decapsulator) Encapsulator() Encapsulator {
+	return &sm2Encapsulator{pub: &d.priv.PublicKey}
+}
+
+func (d *sm2Decapsulator) Decapsulate(ciphertext []byte) ([]byte, error) {
+	key, err := d.priv.Decrypt(nil, ciphertext, sm2.ASN1DecrypterOpts)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != sm2SharedKeySize {
+		return nil, sm2.ErrDecryption
+	}
+	return key, nil
+}
+
+// sm9Encapsulator is the master public key and the identity of a recipient.
+type sm9Encapsulator struct {
+	pub *sm9.EncryptMasterPublicKey
+	uid []byte
+	hid byte
+}
+
+// NewSM9Encapsulator returns the SM9 [Encapsulator] of the recipient of
+// identity uid and hid under the master public key.
+func NewSM9Encapsulator(pub *sm9.EncryptMasterPublicKey, uid []byte, hid byte) (Encapsulator, error) {
+	if pub == nil || len(uid) == 0 {
+		return nil, errors.New("kem: invalid SM9 public key or identity")
+	}
+	return &sm9Encapsulator{pub: pub, uid: append([]byte{}, uid...), hid: hid}, nil
+}
+
+func readSM9Identity(s *cryptobyte.String) (uid []byte, hid byte, ok bool) {
+	var h int64
+	if !s.ReadASN1Bytes(&uid, cryptobyte_asn1.OCTET_STRING) || len(uid) == 0 ||
+		!s.ReadASN1Integer(&h) || h < 0 || h > 255 {
+		return nil, 0, false
+	}
+	return append([]byte{}, uid...), byte(h), true
+}
+
+func parseSM9Encapsulator(b []byte) (Encapsulator, error) {
+	var inner cryptobyte.String
+	var pubBytes []byte
+	input := cryptobyte.String(b)
+	if !input.ReadASN1(&inner, cryptobyte_asn1.SEQUENCE) || !input.Empty() ||
+		!inner.ReadASN1BitStringAsBytes(&pubBytes) {
+		return nil, errors.New("kem: invalid SM9 public key")
+	}
+	uid, hid, ok := readSM9Identity(&inner)
+	if !ok || !inner.Empty() {
+		return nil, errors.New("kem: invalid SM9 public key")
+	}
+	pub, err := sm9.UnmarshalEncryptMasterPublicKeyRaw(pubBytes)
+	if err != nil {
+		return nil, err
+	}
+	return &sm9Encapsulator{pub: pub, uid: uid, hid: hid}, nil
+}
+
+func (e *sm9Encapsulator) Algorithm() Algorithm { return SM9 }
+
+func (e *sm9Encapsulator) Bytes() []byte {
+	var b cryptobyte.Builder
+	b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1BitString(e.pub.Bytes())
+		b.AddASN1OctetString(e.uid)
+		b.AddASN1Int64(int64(e.hid))
+	})
+	return b.BytesOrPanic()
+}
+
+// Encapsulate wraps a key for the identity of the recipient, the ciphertext
+// is the raw point C of [sm9.WrapKey].
+func (e *sm9Encapsulator) Encapsulate(rand io.Reader) ([]byte, []byte, error) {
+	return sm9.WrapKey(rand, e.pub, e.uid, e.hid, sm9SharedKeySize)
+}
+
+type sm9Decapsulator struct {
+	priv *sm9.EncryptPrivateKey
+	enc  *sm9Encapsulator
+}
+
+// NewSM9Decapsulator returns the SM9 [Decapsulator] of the private key of the
+// identity uid and hid under the master public key.
+func NewSM9Decapsulator(pub *sm9.EncryptMasterPublicKey, priv *sm9.EncryptPrivateKey, uid []byte, hid byte) (Decapsulator, error) {
+	if priv == nil {
+		return nil, errors.New("kem: invalid SM9 private key")
+	}
+	e, err := NewSM9Encapsulator(pub, uid, hid)
+	if err != nil {
+		return nil, err
+	}
+	return &sm9Decapsulator{priv: priv, enc: e.(*sm9Encapsulator)}, nil
+}
+
+func parseSM9Decapsulator(b []byte) (Decapsulator, error) {
+	var inner cryptobyte.String
+	var privBytes, pubBytes []byte
+	input := cryptobyte.String(b)
+	if !input.ReadASN1(&inner, cryptobyte_asn1.SEQUENCE) || !input.Empty() ||
+		!inner.ReadASN1BitStringAsBytes(&privBytes) ||
+		!inner.ReadASN1BitStringAsBytes(&pubBytes) {
+		return nil, errors.New("kem: invalid SM9 private key")
+	}
+	uid, hid, ok := readSM9Identity(&inner)
+	if !ok || !inner.Empty() {
+		return nil, errors.New("kem: invalid SM9 private key")
+	}
+	priv, err := sm9.UnmarshalEncryptPrivateKeyRaw(privBytes)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := sm9.UnmarshalEncryptMasterPublicKeyRaw(pubBytes)
+	if err != nil {
+		return nil, err
+	}
+	return &sm9Decapsulator{priv: priv, enc: &sm9Encapsulator{pub: pub, uid: uid, hid: hid}}, nil
+}
+
+func (d *sm9Decapsulator) Algorithm() Algorithm { return SM9 }
+
+func (d *sm9Decapsulator) Bytes() []byte {
+	var b cryptobyte.Builder
+	b.AddASN1(cryptobyte_asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1BitString(d.priv.Bytes())
+		b.AddASN1BitString(d.enc.pub.Bytes())
+		b.AddASN1OctetString(d.enc.uid)
+		b.AddASN1Int64(int64(d.enc.hid))
+	})
+	return b.BytesOrPanic()
+}
+
+func (d *sm9Decapsulator) Encapsulator() Encapsulator { return d.enc }
+
+func (d *sm9Decapsulator) Decapsulate(ciphertext []byte) ([]byte, error) {
+	return sm9.UnwrapKey(d.priv, d.enc.uid, ciphertext, sm9SharedKeySize)
+}
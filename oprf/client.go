@@ -0,0 +1,154 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package oprf
+
+import (
+	"errors"
+	"io"
+
+	"github.com/emmansun/gmsm/internal/bigmod"
+	"github.com/emmansun/gmsm/internal/ecgroup"
+)
+
+// maxBatchSize is the largest number of elements of one evaluation, the
+// index of an element in the composite transcript is encoded on 2 bytes.
+const maxBatchSize = 0xffff
+
+// Client is the client of an OPRF protocol instance.
+type Client struct {
+	suite *Suite
+	mode  Mode
+	pub   *PublicKey
+}
+
+// NewClient returns a client of the mode. The public key of the server is
+// required by the VOPRF and POPRF modes, and ignored by the OPRF mode.
+func (s *Suite) NewClient(mode Mode, pub *PublicKey) (*Client, error) {
+	switch mode {
+	case ModeOPRF:
+		pub = nil
+	case ModeVOPRF, ModePOPRF:
+		if pub == nil || pub.suite != s {
+			return nil, errors.New("oprf: invalid public key")
+		}
+	default:
+		return nil, errInvalidMode
+	}
+	return &Client{suite: s, mode: mode, pub: pub}, nil
+}
+
+// FinalizeData is the state kept by the client between [Client.Blind] and
+// [Client.Finalize]. It contains the blinds, which must stay secret.
+type FinalizeData struct {
+	inputs     [][]byte
+	info       []byte
+	blinds     []*bigmod.Nat
+	blinded    []*ecgroup.Element
+	tweakedKey *ecgroup.Element
+}
+
+func checkInfo(mode Mode, info []byte) error {
+	if mode != ModePOPRF && len(info) > 0 {
+		return errors.New("oprf: info is only supported by the POPRF mode")
+	}
+	if len(info) > 0xffff {
+		return errors.New("oprf: info is too long")
+	}
+	return nil
+}
+
+// Blind blinds the inputs, as Blind of RFC 9497, sections 3.3.1 and 3.3.3.
+// The info is the public input of the POPRF mode, it must be empty in the
+// other modes. Blind returns the state for [Client.Finalize] and the
+// serialized blinded elements to send to the server.
+func (c *Client) Blind(rand io.Reader, inputs [][]byte, info []byte) (*FinalizeData, [][]byte, error) {
+	s := c.suite
+	if len(inputs) == 0 || len(inputs) > maxBatchSize {
+		return nil, nil, errors.New("oprf: invalid number of inputs")
+	}
+	if err := checkInfo(c.mode, info); err != nil {
+		return nil, nil, err
+	}
+	state := &FinalizeData{info: info}
+	if c.mode == ModePOPRF {
+		m := s.hashToScalarMode(c.mode, framedInfo(info))
+		state.tweakedKey = s.group.ScalarBaseMult(m).Add(c.pub.e)
+		if state.tweakedKey.IsIdentity() {
+			return nil, nil, errInvalidInput
+		}
+	}
+	blinded := make([][]byte, len(inputs))
+	for i, input := range inputs {
+		if len(input) > 0xffff {
+			return nil, nil, errors.New("oprf: input is too long")
+		}
+		blind, err := s.group.RandomScalar(rand)
+		if err != nil {
+			return nil, nil, err
+		}
+		inputElement, err := s.hashToGroup(c.mode, input)
+		if err != nil {
+			return nil, nil, err
+		}
+		blindedElement := inputElement.ScalarMult(blind)
+		state.inputs = append(state.inputs, input)
+		state.blinds = append(state.blinds, blind)
+		state.blinded = append(state.blinded, blindedElement)
+		blinded[i] = blindedElement.BytesCompressed()
+	}
+	return state, blinded, nil
+}
+
+// Finalize verifies the proof of the evaluation in the VOPRF and POPRF modes,
+// unblinds the evaluated elements, and returns the PRF outputs of the inputs
+// of [Client.Blind], as Finalize of RFC 9497, sections 3.3.1 to 3.3.3.
+func (c *Client) Finalize(state *FinalizeData, ev *Evaluation) ([][]byte, error) {
+	s := c.suite
+	if len(ev.Elements) != len(state.blinded) {
+		return nil, errors.New("oprf: invalid number of evaluated elements")
+	}
+	evaluated := make([]*ecgroup.Element, len(ev.Elements))
+	for i, b := range ev.Elements {
+		e, err := s.group.DecodeCompressed(b)
+		if err != nil {
+			return nil, err
+		}
+		evaluated[i] = e
+	}
+	switch c.mode {
+	case ModeVOPRF:
+		if !s.verifyProof(c.mode, s.group.Generator(), c.pub.e, state.blinded, evaluated, ev.Proof) {
+			return nil, errVerify
+		}
+	case ModePOPRF:
+		if !s.verifyProof(c.mode, s.group.Generator(), state.tweakedKey, evaluated, state.blinded, ev.Proof) {
+			return nil, errVerify
+		}
+	}
+	outputs := make([][]byte, len(evaluated))
+	for i, e := range evaluated {
+		n := e.ScalarMult(s.group.Invert(state.blinds[i]))
+		outputs[i] = s.finalizeHash(c.mode, state.inputs[i], state.info, n.BytesCompressed())
+	}
+	return outputs, nil
+}
+
+// finalizeHash returns the PRF output of the input from the serialized
+// unblinded element.
+func (s *Suite) finalizeHash(mode Mode, input, info, unblinded []byte) []byte {
+	h := s.hash()
+	h.Write(lengthPrefixed(nil, input))
+	if mode == ModePOPRF {
+		h.Write(lengthPrefixed(nil, info))
+	}
+	h.Write(lengthPrefixed(nil, unblinded))
+	h.Write([]byte("Finalize"))
+	return h.Sum(nil)
+}
+
+// framedInfo returns "Info" || I2OSP(len(info), 2) || info.
+func framedInfo(info []byte) []byte {
+	return lengthPrefixed([]byte("Info"), info)
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package oprf
+
+import (
+	"crypto/subtle"
+	"io"
+
+	"github.com/emmansun/gmsm/internal/bigmod"
+	"github.com/emmansun/gmsm/internal/ecgroup"
+)
+
+// The discrete logarithm equivalence proofs of RFC 9497, section 2.2. A proof
+// shows that k is both the discrete logarithm of B with respect to A and of
+// each D[i] with respect to C[i], the pairs are batched into a single pair of
+// composite elements.
+
+// computeComposites implements ComputeComposites of RFC 9497, section
+// 2.2.1, and ComputeCompositesFast of section 2.2.2 if k is not nil.
+func (s *Suite) computeComposites(mode Mode, k *bigmod.Nat, B *ecgroup.Element, C, D []*ecgroup.Element) (M, Z *ecgroup.Element) {
+	seedDST := append([]byte("Seed-"), s.contextString(mode)...)
+	h := s.hash()
+	h.Write(lengthPrefixed(nil, B.BytesCompressed()))
+	h.Write(lengthPrefixed(nil, seedDST))
+	seed := h.Sum(nil)
+
+	M, Z = s.group.Identity(), s.group.Identity()
+	for i := range C {
+		transcript := lengthPrefixed(nil, seed)
+		transcript = append(transcript, byte(i>>8), byte(i))
+		transcript = lengthPrefixed(transcript, C[i].BytesCompressed())
+		transcript = lengthPrefixed(transcript, D[i].BytesCompressed())
+		transcript = append(transcript, "Composite"...)
+		di := s.hashToScalarMode(mode, transcript)
+		M = C[i].ScalarMult(di).Add(M)
+		if k == nil {
+			Z = D[i].ScalarMult(di).Add(Z)
+		}
+	}
+	if k != nil {
+		Z = M.ScalarMult(k)
+	}
+	return M, Z
+}
+
+func (s *Suite) challenge(mode Mode, B, M, Z, t2, t3 *ecgroup.Element) *bigmod.Nat {
+	var transcript []byte
+	for _, e := range []*ecgroup.Element{B, M, Z, t2, t3} {
+		transcript = lengthPrefixed(transcript, e.BytesCompressed())
+	}
+	transcript = append(transcript, "Challenge"...)
+	return s.hashToScalarMode(mode, transcript)
+}
+
+// generateProof implements GenerateProof of RFC 9497, section 2.2.1. The
+// proof is the serialization of the scalars c and s.
+func (s *Suite) generateProof(rand io.Reader, mode Mode, k *bigmod.Nat, A, B *ecgroup.Element, C, D []*ecgroup.Element) ([]byte, error) {
+	M, Z := s.computeComposites(mode, k, B, C, D)
+	r, err := s.group.RandomScalar(rand)
+	if err != nil {
+		return nil, err
+	}
+	c := s.challenge(mode, B, M, Z, A.ScalarMult(r), M.ScalarMult(r))
+	// s = r - c * k
+	ck := bigmod.NewNat().Set(c).Mul(k, s.n)
+	r.Sub(ck, s.n)
+	return append(c.Bytes(s.n), r.Bytes(s.n)...), nil
+}
+
+// verifyProof implements VerifyProof of RFC 9497, section 2.2.2.
+func (s *Suite) verifyProof(mode Mode, A, B *ecgroup.Element, C, D []*ecgroup.Element, proof []byte) bool {
+	size := s.n.Size()
+	if len(proof) != 2*size {
+		return false
+	}
+	c, err := bigmod.NewNat().SetBytes(proof[:size], s.n)
+	if err != nil {
+		return false
+	}
+	sc, err := bigmod.NewNat().SetBytes(proof[size:], s.n)
+	if err != nil {
+		return false
+	}
+	M, Z := s.computeComposites(mode, nil, B, C, D)
+	t2 := A.ScalarMult(sc).Add(B.ScalarMult(c))
+	t3 := M.ScalarMult(sc).Add(Z.ScalarMult(c))
+	expected := s.challenge(mode, B, M, Z, t2, t3)
+	return subtle.ConstantTimeCompare(expected.Bytes(s.n), proof[:size]) == 1
+}
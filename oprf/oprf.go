@@ -0,0 +1,218 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package oprf implements the oblivious pseudorandom functions of RFC 9497:
+// the base OPRF mode, the verifiable VOPRF mode and the partially oblivious
+// POPRF mode, with DLEQ proofs and batched evaluation.
+//
+// The protocol is written once over a prime-order elliptic curve group, and
+// instantiated with the ciphersuite P256-SHA256 of RFC 9497, section 4.3, and
+// the ciphersuite SM2-SM3 defined by this package:
+//
+//   - Group: the SM2 curve, elements are serialized in compressed form (Ne =
+//     33) and scalars as 32-byte big-endian integers (Ns = 32);
+//   - HashToGroup: hash_to_curve with the suite SM2P256_XMD:SM3_SSWU_RO_ of
+//     package h2c, with DST = "HashToGroup-" || contextString;
+//   - HashToScalar: hash_to_field with expand_message_xmd, SM3 and L = 48,
+//     modulo the group order, with DST = "HashToScalar-" || contextString;
+//   - Hash: SM3 (Nh = 32).
+//
+// A protocol instance is a [Client] and a [Server] of the same suite and
+// mode. The client blinds its inputs, the server evaluates the blinded
+// elements, and the client finalizes the evaluation into the PRF outputs.
+package oprf
+
+import (
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"io"
+
+	"github.com/emmansun/gmsm/h2c"
+	"github.com/emmansun/gmsm/internal/bigmod"
+	"github.com/emmansun/gmsm/internal/ecgroup"
+	"github.com/emmansun/gmsm/sm3"
+)
+
+// Mode is a protocol variant of RFC 9497, section 3.1.
+type Mode byte
+
+const (
+	ModeOPRF  Mode = 0x00 // base mode
+	ModeVOPRF Mode = 0x01 // verifiable mode
+	ModePOPRF Mode = 0x02 // partially oblivious mode
+)
+
+// The identifiers of the supported ciphersuites.
+const (
+	SM2SM3     = "SM2-SM3"
+	P256SHA256 = "P256-SHA256"
+)
+
+var (
+	errInvalidInput = errors.New("oprf: input is mapped to the identity element")
+	errInvalidMode  = errors.New("oprf: invalid mode")
+	errVerify       = errors.New("oprf: proof verification failed")
+)
+
+// Suite is an OPRF ciphersuite, which defines the prime-order group and the
+// hash function of the protocol.
+type Suite struct {
+	id    string
+	group *ecgroup.Group
+	n     *bigmod.Modulus
+	h2c   *h2c.Suite
+	hash  func() hash.Hash
+}
+
+var suites = map[string]*Suite{
+	SM2SM3:     newSuite(SM2SM3, ecgroup.SM2, h2c.SM2P256RO, sm3.New),
+	P256SHA256: newSuite(P256SHA256, ecgroup.P256, h2c.P256RO, sha256.New),
+}
+
+func newSuite(id string, group *ecgroup.Group, h2cID string, h func() hash.Hash) *Suite {
+	encoder, err := h2c.NewSuite(h2cID)
+	if err != nil {
+		panic(err)
+	}
+	return &Suite{id: id, group: group, n: group.Order(), h2c: encoder, hash: h}
+}
+
+// NewSuite returns the ciphersuite of the identifier id.
+func NewSuite(id string) (*Suite, error) {
+	s, ok := suites[id]
+	if !ok {
+		return nil, errors.New("oprf: unsupported suite " + id)
+	}
+	return s, nil
+}
+
+// ID returns the identifier of the suite.
+func (s *Suite) ID() string {
+	return s.id
+}
+
+// contextString returns "OPRFV1-" || I2OSP(mode, 1) || "-" || identifier.
+func (s *Suite) contextString(mode Mode) []byte {
+	return append([]byte{'O', 'P', 'R', 'F', 'V', '1', '-', byte(mode), '-'}, s.id...)
+}
+
+func (s *Suite) hashToGroup(mode Mode, msg []byte) (*ecgroup.Element, error) {
+	dst := append([]byte("HashToGroup-"), s.contextString(mode)...)
+	p, err := s.h2c.Hash(msg, dst)
+	if err != nil {
+		return nil, err
+	}
+	if len(p) == 1 {
+		return nil, errInvalidInput
+	}
+	return s.group.DecodeUncompressed(p)
+}
+
+func (s *Suite) hashToScalar(msg, dst []byte) *bigmod.Nat {
+	b, err := s.h2c.HashToScalar(msg, dst)
+	if err != nil {
+		// the DST is never empty
+		panic("oprf: internal error: " + err.Error())
+	}
+	k, _ := bigmod.NewNat().SetBytes(b, s.n)
+	return k
+}
+
+func (s *Suite) hashToScalarMode(mode Mode, msg []byte) *bigmod.Nat {
+	return s.hashToScalar(msg, append([]byte("HashToScalar-"), s.contextString(mode)...))
+}
+
+// lengthPrefixed appends I2OSP(len(b), 2) || b to dst.
+func lengthPrefixed(dst, b []byte) []byte {
+	return append(append(dst, byte(len(b)>>8), byte(len(b))), b...)
+}
+
+// PrivateKey is the private key skS of a server.
+type PrivateKey struct {
+	suite *Suite
+	k     *bigmod.Nat
+	pub   *PublicKey
+}
+
+// PublicKey is the public key pkS of a server, used by the clients of the
+// VOPRF and POPRF modes.
+type PublicKey struct {
+	suite *Suite
+	e     *ecgroup.Element
+}
+
+func (s *Suite) newPrivateKey(k *bigmod.Nat) *PrivateKey {
+	return &PrivateKey{suite: s, k: k, pub: &PublicKey{suite: s, e: s.group.ScalarBaseMult(k)}}
+}
+
+// GenerateKey generates a random key pair, as GenerateKeyPair of RFC 9497,
+// section 3.2.
+func (s *Suite) GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	k, err := s.group.RandomScalar(rand)
+	if err != nil {
+		return nil, err
+	}
+	return s.newPrivateKey(k), nil
+}
+
+// DeriveKey deterministically derives a key pair of the mode from a seed of at
+// least 32 bytes and the public info, as DeriveKeyPair of RFC 9497, section
+// 3.2.1.
+func (s *Suite) DeriveKey(mode Mode, seed, info []byte) (*PrivateKey, error) {
+	if mode > ModePOPRF {
+		return nil, errInvalidMode
+	}
+	if len(seed) < s.n.Size() {
+		return nil, errors.New("oprf: seed is too short")
+	}
+	if len(info) > 0xffff {
+		return nil, errors.New("oprf: info is too long")
+	}
+	deriveInput := lengthPrefixed(append([]byte{}, seed...), info)
+	dst := append([]byte("DeriveKeyPair"), s.contextString(mode)...)
+	for counter := 0; counter < 256; counter++ {
+		k := s.hashToScalar(append(deriveInput, byte(counter)), dst)
+		if k.IsZero() == 0 {
+			return s.newPrivateKey(k), nil
+		}
+	}
+	return nil, errors.New("oprf: failed to derive a key pair")
+}
+
+// NewPrivateKey decodes a private key from its 32-byte big-endian encoding.
+func (s *Suite) NewPrivateKey(b []byte) (*PrivateKey, error) {
+	if len(b) != s.n.Size() {
+		return nil, errors.New("oprf: invalid private key length")
+	}
+	k, err := bigmod.NewNat().SetBytes(b, s.n)
+	if err != nil || k.IsZero() == 1 {
+		return nil, errors.New("oprf: invalid private key")
+	}
+	return s.newPrivateKey(k), nil
+}
+
+// NewPublicKey decodes a public key from its compressed encoding.
+func (s *Suite) NewPublicKey(b []byte) (*PublicKey, error) {
+	e, err := s.group.DecodeCompressed(b)
+	if err != nil {
+		return nil, err
+	}
+	return &PublicKey{suite: s, e: e}, nil
+}
+
+// Bytes returns the 32-byte big-endian encoding of the private key.
+func (priv *PrivateKey) Bytes() []byte {
+	return priv.k.Bytes(priv.suite.n)
+}
+
+// Public returns the public key of priv.
+func (priv *PrivateKey) Public() *PublicKey {
+	return priv.pub
+}
+
+// Bytes returns the compressed encoding of the public key.
+func (pub *PublicKey) Bytes() []byte {
+	return pub.e.BytesCompressed()
+}
@@ -0,0 +1,272 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package oprf
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testdata/vectors.json holds the P256-SHA256 vectors of RFC 9497, appendix
+// A.3. The SM2-SM3 entries use the same seeds and inputs, and record the
+// outputs of this package for the suite, which RFC 9497 doesn't define.
+type vectorSuite struct {
+	Identifier string `json:"identifier"`
+	Mode       Mode   `json:"mode"`
+	Seed       string `json:"seed"`
+	KeyInfo    string `json:"keyInfo"`
+	SkSm       string `json:"skSm"`
+	PkSm       string `json:"pkSm"`
+	Vectors    []struct {
+		Batch             int    `json:"Batch"`
+		Blind             string `json:"Blind"`
+		BlindedElement    string `json:"BlindedElement"`
+		EvaluationElement string `json:"EvaluationElement"`
+		Info              string `json:"Info"`
+		Input             string `json:"Input"`
+		Output            string `json:"Output"`
+		Proof             struct {
+			Proof string `json:"proof"`
+			R     string `json:"r"`
+		} `json:"Proof"`
+	} `json:"vectors"`
+}
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func decodeList(t *testing.T, s string) [][]byte {
+	t.Helper()
+	var out [][]byte
+	for _, v := range strings.Split(s, ",") {
+		out = append(out, decodeHex(t, v))
+	}
+	return out
+}
+
+func encodeList(l [][]byte) string {
+	s := make([]string, len(l))
+	for i, b := range l {
+		s[i] = hex.EncodeToString(b)
+	}
+	return strings.Join(s, ",")
+}
+
+func TestVectors(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "vectors.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vectorSuites []vectorSuite
+	if err := json.Unmarshal(data, &vectorSuites); err != nil {
+		t.Fatal(err)
+	}
+	for _, vs := range vectorSuites {
+		s, err := NewSuite(vs.Identifier)
+		if err != nil {
+			t.Fatal(err)
+		}
+		priv, err := s.DeriveKey(vs.Mode, decodeHex(t, vs.Seed), decodeHex(t, vs.KeyInfo))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(priv.Bytes()); got != vs.SkSm {
+			t.Fatalf("%s mode %d: skSm = %s, want %s", vs.Identifier, vs.Mode, got, vs.SkSm)
+		}
+		if vs.PkSm != "" {
+			if got := hex.EncodeToString(priv.Public().Bytes()); got != vs.PkSm {
+				t.Fatalf("%s mode %d: pkSm = %s, want %s", vs.Identifier, vs.Mode, got, vs.PkSm)
+			}
+		}
+		client, err := s.NewClient(vs.Mode, priv.Public())
+		if err != nil {
+			t.Fatal(err)
+		}
+		server, err := s.NewServer(vs.Mode, priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, v := range vs.Vectors {
+			inputs := decodeList(t, v.Input)
+			info := decodeHex(t, v.Info)
+			blinds := bytes.NewReader(bytes.Join(decodeList(t, v.Blind), nil))
+			state, blinded, err := client.Blind(blinds, inputs, info)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := encodeList(blinded); got != v.BlindedElement {
+				t.Errorf("%s mode %d #%d: BlindedElement = %s, want %s", vs.Identifier, vs.Mode, i, got, v.BlindedElement)
+			}
+			ev, err := server.BlindEvaluate(bytes.NewReader(decodeHex(t, v.Proof.R)), blinded, info)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := encodeList(ev.Elements); got != v.EvaluationElement {
+				t.Errorf("%s mode %d #%d: EvaluationElement = %s, want %s", vs.Identifier, vs.Mode, i, got, v.EvaluationElement)
+			}
+			if got := hex.EncodeToString(ev.Proof); got != v.Proof.Proof {
+				t.Errorf("%s mode %d #%d: Proof = %s, want %s", vs.Identifier, vs.Mode, i, got, v.Proof.Proof)
+			}
+			outputs, err := client.Finalize(state, ev)
+			if err != nil {
+				t.Fatalf("%s mode %d #%d: %v", vs.Identifier, vs.Mode, i, err)
+			}
+			if got := encodeList(outputs); got != v.Output {
+				t.Errorf("%s mode %d #%d: Output = %s, want %s", vs.Identifier, vs.Mode, i, got, v.Output)
+			}
+			for j, input := range inputs {
+				output, err := server.Evaluate(input, info)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(output, outputs[j]) {
+					t.Errorf("%s mode %d #%d: Evaluate mismatch", vs.Identifier, vs.Mode, i)
+				}
+			}
+		}
+	}
+}
+
+func TestProtocol(t *testing.T) {
+	for _, id := range []string{SM2SM3, P256SHA256} {
+		s, _ := NewSuite(id)
+		for _, mode := range []Mode{ModeOPRF, ModeVOPRF, ModePOPRF} {
+			priv, err := s.GenerateKey(rand.Reader)
+			if err != nil {
+				t.Fatal(err)
+			}
+			client, _ := s.NewClient(mode, priv.Public())
+			server, _ := s.NewServer(mode, priv)
+			var info []byte
+			if mode == ModePOPRF {
+				info = []byte("2026-10")
+			}
+			inputs := [][]byte{[]byte("password"), []byte("another password"), {}}
+			state, blinded, err := client.Blind(rand.Reader, inputs, info)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ev, err := server.BlindEvaluate(rand.Reader, blinded, info)
+			if err != nil {
+				t.Fatal(err)
+			}
+			outputs, err := client.Finalize(state, ev)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, input := range inputs {
+				if output, _ := server.Evaluate(input, info); !bytes.Equal(output, outputs[i]) {
+					t.Fatalf("%s mode %d: output mismatch", id, mode)
+				}
+			}
+			if mode == ModeOPRF {
+				continue
+			}
+
+			// an evaluation with another key must be rejected
+			other, _ := s.GenerateKey(rand.Reader)
+			otherServer, _ := s.NewServer(mode, other)
+			ev, _ = otherServer.BlindEvaluate(rand.Reader, blinded, info)
+			if _, err := client.Finalize(state, ev); err == nil {
+				t.Fatalf("%s mode %d: expected error for another key", id, mode)
+			}
+			// swapped elements must be rejected
+			ev, _ = server.BlindEvaluate(rand.Reader, blinded, info)
+			ev.Elements[0], ev.Elements[1] = ev.Elements[1], ev.Elements[0]
+			if _, err := client.Finalize(state, ev); err == nil {
+				t.Fatalf("%s mode %d: expected error for swapped elements", id, mode)
+			}
+			ev, _ = server.BlindEvaluate(rand.Reader, blinded, info)
+			ev.Proof[len(ev.Proof)-1] ^= 1
+			if _, err := client.Finalize(state, ev); err == nil {
+				t.Fatalf("%s mode %d: expected error for invalid proof", id, mode)
+			}
+		}
+	}
+}
+
+func TestPOPRFInfo(t *testing.T) {
+	s, _ := NewSuite(SM2SM3)
+	priv, _ := s.GenerateKey(rand.Reader)
+	server, _ := s.NewServer(ModePOPRF, priv)
+	a, _ := server.Evaluate([]byte("input"), []byte("info a"))
+	b, _ := server.Evaluate([]byte("input"), []byte("info b"))
+	if bytes.Equal(a, b) {
+		t.Fatal("outputs of different infos are equal")
+	}
+	client, _ := s.NewClient(ModePOPRF, priv.Public())
+	state, blinded, _ := client.Blind(rand.Reader, [][]byte{[]byte("input")}, []byte("info a"))
+	ev, _ := server.BlindEvaluate(rand.Reader, blinded, []byte("info b"))
+	if _, err := client.Finalize(state, ev); err == nil {
+		t.Fatal("expected error for mismatched info")
+	}
+	oprf, _ := s.NewServer(ModeOPRF, priv)
+	if _, err := oprf.Evaluate([]byte("input"), []byte("info")); err == nil {
+		t.Fatal("expected error for info in the OPRF mode")
+	}
+}
+
+func TestKeys(t *testing.T) {
+	s, _ := NewSuite(SM2SM3)
+	priv, _ := s.GenerateKey(rand.Reader)
+	priv2, err := s.NewPrivateKey(priv.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(priv2.Public().Bytes(), priv.Public().Bytes()) {
+		t.Fatal("private key round trip failed")
+	}
+	if _, err := s.NewPublicKey(priv.Public().Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.NewPrivateKey(make([]byte, 32)); err == nil {
+		t.Fatal("expected error for zero private key")
+	}
+	if _, err := s.NewPublicKey(make([]byte, 33)); err == nil {
+		t.Fatal("expected error for invalid public key")
+	}
+	if _, err := s.DeriveKey(ModeOPRF, make([]byte, 31), nil); err == nil {
+		t.Fatal("expected error for short seed")
+	}
+	if _, err := s.NewClient(ModeVOPRF, nil); err == nil {
+		t.Fatal("expected error for missing public key")
+	}
+	p256, _ := NewSuite(P256SHA256)
+	if _, err := p256.NewServer(ModeOPRF, priv); err == nil {
+		t.Fatal("expected error for key of another suite")
+	}
+	if _, err := NewSuite("ristretto255-SHA512"); err == nil {
+		t.Fatal("expected error for unsupported suite")
+	}
+}
+
+func BenchmarkVOPRF(b *testing.B) {
+	s, _ := NewSuite(SM2SM3)
+	priv, _ := s.GenerateKey(rand.Reader)
+	client, _ := s.NewClient(ModeVOPRF, priv.Public())
+	server, _ := s.NewServer(ModeVOPRF, priv)
+	inputs := [][]byte{[]byte("password")}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		state, blinded, _ := client.Blind(rand.Reader, inputs, nil)
+		ev, _ := server.BlindEvaluate(rand.Reader, blinded, nil)
+		if _, err := client.Finalize(state, ev); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package oprf
+
+import (
+	"errors"
+	"io"
+
+	"github.com/emmansun/gmsm/internal/bigmod"
+	"github.com/emmansun/gmsm/internal/ecgroup"
+)
+
+// Server is the server of an OPRF protocol instance.
+type Server struct {
+	suite *Suite
+	mode  Mode
+	key   *PrivateKey
+}
+
+// Evaluation is the response of the server to the blinded elements of a
+// client. Proof is the DLEQ proof of the VOPRF and POPRF modes, and is empty
+// in the OPRF mode.
+type Evaluation struct {
+	Elements [][]byte
+	Proof    []byte
+}
+
+// NewServer returns a server of the mode with the private key.
+func (s *Suite) NewServer(mode Mode, key *PrivateKey) (*Server, error) {
+	if mode > ModePOPRF {
+		return nil, errInvalidMode
+	}
+	if key == nil || key.suite != s {
+		return nil, errors.New("oprf: invalid private key")
+	}
+	return &Server{suite: s, mode: mode, key: key}, nil
+}
+
+// tweakedKey returns the scalar t = skS + m of the POPRF mode, where m is
+// the hash of info, or skS in the other modes.
+func (srv *Server) tweakedKey(info []byte) (*bigmod.Nat, error) {
+	s := srv.suite
+	if srv.mode != ModePOPRF {
+		return srv.key.k, nil
+	}
+	t := s.hashToScalarMode(srv.mode, framedInfo(info))
+	t.Add(srv.key.k, s.n)
+	if t.IsZero() == 1 {
+		return nil, errors.New("oprf: info is mapped to the inverse of the private key")
+	}
+	return t, nil
+}
+
+// BlindEvaluate evaluates the serialized blinded elements of a client, as
+// BlindEvaluate of RFC 9497, sections 3.3.1 to 3.3.3. All the elements are
+// covered by a single proof in the VOPRF and POPRF modes, and rand is the
+// source of the proof randomness.
+func (srv *Server) BlindEvaluate(rand io.Reader, blinded [][]byte, info []byte) (*Evaluation, error) {
+	s := srv.suite
+	if len(blinded) == 0 || len(blinded) > maxBatchSize {
+		return nil, errors.New("oprf: invalid number of blinded elements")
+	}
+	if err := checkInfo(srv.mode, info); err != nil {
+		return nil, err
+	}
+	t, err := srv.tweakedKey(info)
+	if err != nil {
+		return nil, err
+	}
+	k := t
+	if srv.mode == ModePOPRF {
+		k = s.group.Invert(t)
+	}
+	blindedElements := make([]*ecgroup.Element, len(blinded))
+	evaluatedElements := make([]*ecgroup.Element, len(blinded))
+	ev := &Evaluation{Elements: make([][]byte, len(blinded))}
+	for i, b := range blinded {
+		if blindedElements[i], err = s.group.DecodeCompressed(b); err != nil {
+			return nil, err
+		}
+		evaluatedElements[i] = blindedElements[i].ScalarMult(k)
+		ev.Elements[i] = evaluatedElements[i].BytesCompressed()
+	}
+	switch srv.mode {
+	case ModeVOPRF:
+		ev.Proof, err = s.generateProof(rand, srv.mode, srv.key.k, s.group.Generator(), srv.key.pub.e, blindedElements, evaluatedElements)
+	case ModePOPRF:
+		// t is the discrete logarithm of the blinded elements with respect
+		// to the evaluated elements
+		ev.Proof, err = s.generateProof(rand, srv.mode, t, s.group.Generator(), s.group.ScalarBaseMult(t), evaluatedElements, blindedElements)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ev, nil
+}
+
+// Evaluate computes the PRF output of the input directly with the private
+// key, as Evaluate of RFC 9497, sections 3.3.1 to 3.3.3. The output is the
+// same as the output of the blinded protocol.
+func (srv *Server) Evaluate(input, info []byte) ([]byte, error) {
+	s := srv.suite
+	if len(input) > 0xffff {
+		return nil, errors.New("oprf: input is too long")
+	}
+	if err := checkInfo(srv.mode, info); err != nil {
+		return nil, err
+	}
+	t, err := srv.tweakedKey(info)
+	if err != nil {
+		return nil, err
+	}
+	if srv.mode == ModePOPRF {
+		t = s.group.Invert(t)
+	}
+	inputElement, err := s.hashToGroup(srv.mode, input)
+	if err != nil {
+		return nil, err
+	}
+	issued := inputElement.ScalarMult(t).BytesCompressed()
+	return s.finalizeHash(srv.mode, input, info, issued), nil
+}
@@ -18,10 +18,11 @@ type IssuerAndSerial struct {
 }
 
 // RecipientInfo is a structure that holds the recipient information
-// supports IssuerAndSerial and SubjectKeyIdentifier.
+// supports IssuerAndSerial, SubjectKeyIdentifier and SM9 identity.
 type RecipientInfo struct {
 	IssuerAndSerial
 	SubjectKeyIdentifier []byte
+	SM9Identifier        *SM9Identifier
 }
 
 func newIssuerAndSerial(issuerAndSerial issuerAndSerial) IssuerAndSerial {
@@ -43,6 +44,9 @@ func newRecipientInfo(recipientInfo recipientInfo) RecipientInfo {
 	if len(recipientInfo.SubjectKeyIdentifier.Bytes) > 0 {
 		ri.SubjectKeyIdentifier = append(ri.SubjectKeyIdentifier, recipientInfo.SubjectKeyIdentifier.Bytes...)
 	}
+	if id, err := parseSM9Identifier(recipientInfo.SM9Identifier); err == nil {
+		ri.SM9Identifier = id
+	}
 
 	return ri
 }
@@ -32,6 +32,7 @@ type recipientInfo struct {
 	Version                int
 	IssuerAndSerialNumber  issuerAndSerial `asn1:"optional"`
 	SubjectKeyIdentifier   asn1.RawValue   `asn1:"tag:0,optional"`
+	SM9Identifier          asn1.RawValue   `asn1:"tag:2,optional"`
 	KeyEncryptionAlgorithm pkix.AlgorithmIdentifier
 	EncryptedKey           []byte
 }
@@ -199,6 +200,9 @@ func newEnvelopedData(cipher pkcs.Cipher, content []byte, contentType asn1.Objec
 	if SM2OIDEnvelopedData.Equal(contentType) {
 		ed.encryptedContentType = SM2OIDData
 		version = 1
+	} else if SM9OIDEnvelopedData.Equal(contentType) {
+		ed.encryptedContentType = SM9OIDData
+		version = 1
 	}
 	ed.key = key
 	ed.ed = envelopedData{
@@ -309,9 +309,9 @@ func ParseWithSession(session Session, data []byte) (p7 *PKCS7, err error) {
 	}
 
 	switch {
-	case info.ContentType.Equal(OIDSignedData) || info.ContentType.Equal(SM2OIDSignedData):
+	case info.ContentType.Equal(OIDSignedData) || info.ContentType.Equal(SM2OIDSignedData) || info.ContentType.Equal(SM9OIDSignedData):
 		return parseSignedData(info.Content.Bytes)
-	case info.ContentType.Equal(OIDEnvelopedData) || info.ContentType.Equal(SM2OIDEnvelopedData):
+	case info.ContentType.Equal(OIDEnvelopedData) || info.ContentType.Equal(SM2OIDEnvelopedData) || info.ContentType.Equal(SM9OIDEnvelopedData):
 		return parseEnvelopedData(session, info.Content.Bytes)
 	case info.ContentType.Equal(OIDEncryptedData) || info.ContentType.Equal(SM2OIDEncryptedData):
 		return parseEncryptedData(session, info.Content.Bytes)
@@ -115,8 +115,9 @@ type signedData struct {
 }
 
 type signerInfo struct {
-	Version                   int `asn1:"default:1"`
-	IssuerAndSerialNumber     issuerAndSerial
+	Version                   int             `asn1:"default:1"`
+	IssuerAndSerialNumber     issuerAndSerial `asn1:"optional"`
+	SM9Identifier             asn1.RawValue   `asn1:"tag:2,optional"`
 	DigestAlgorithm           pkix.AlgorithmIdentifier
 	AuthenticatedAttributes   []attribute `asn1:"optional,omitempty,tag:0"`
 	DigestEncryptionAlgorithm pkix.AlgorithmIdentifier
@@ -508,6 +509,12 @@ func signData(data []byte, pkey crypto.PrivateKey, hasher crypto.Hash, isDigestP
 			// data is the message (or DER-encoded attributes), sign it directly
 			// SLH-DSA implements crypto.Signer, so we can call Sign with nil opts
 			opts = nil
+		} else if isSM9Key(pkey) {
+			// SM9 signs the message directly, it is hashed with H2(M||w)
+			if isDigestProvided {
+				return nil, errors.New("pkcs7: SM9 does not support digest signing, use message signing instead")
+			}
+			opts = nil
 		} else {
 			return nil, fmt.Errorf("pkcs7: unsupported hash function %v", hasher)
 		}
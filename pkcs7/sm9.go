@@ -0,0 +1,293 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pkcs7
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+
+	"github.com/emmansun/gmsm/pkcs"
+	"github.com/emmansun/gmsm/sm3"
+	"github.com/emmansun/gmsm/sm9"
+)
+
+// SM9Identifier identifies an SM9 signer or recipient, which has no certificate.
+// It replaces the IssuerAndSerialNumber of the SignerInfo and RecipientInfo:
+//
+//	SM9Identifier ::= [2] IMPLICIT SEQUENCE {
+//	    masterKeyIdentifier OCTET STRING, -- SM3 digest of the master public key
+//	    uid                 OCTET STRING,
+//	    hid                 INTEGER
+//	}
+type SM9Identifier struct {
+	MasterKeyIdentifier []byte
+	UID                 []byte
+	HID                 byte
+}
+
+type sm9Identifier struct {
+	MasterKeyIdentifier []byte
+	UID                 []byte
+	HID                 int
+}
+
+// sm9MasterKeyIdentifier returns the SM3 digest of the raw master public key.
+func sm9MasterKeyIdentifier(masterPublicKey []byte) []byte {
+	h := sm3.Sum(masterPublicKey)
+	return h[:]
+}
+
+func marshalSM9Identifier(id *SM9Identifier) (asn1.RawValue, error) {
+	der, err := asn1.Marshal(sm9Identifier{id.MasterKeyIdentifier, id.UID, int(id.HID)})
+	if err != nil {
+		return asn1.RawValue{}, err
+	}
+	var seq asn1.RawValue
+	if _, err := asn1.Unmarshal(der, &seq); err != nil {
+		return asn1.RawValue{}, err
+	}
+	return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 2, IsCompound: true, Bytes: seq.Bytes}, nil
+}
+
+func parseSM9Identifier(raw asn1.RawValue) (*SM9Identifier, error) {
+	if raw.Class != asn1.ClassContextSpecific || raw.Tag != 2 || !raw.IsCompound {
+		return nil, errors.New("pkcs7: not an SM9 identifier")
+	}
+	var id sm9Identifier
+	der, err := asn1.Marshal(asn1.RawValue{Tag: asn1.TagSequence, IsCompound: true, Bytes: raw.Bytes})
+	if err != nil {
+		return nil, err
+	}
+	if rest, err := asn1.Unmarshal(der, &id); err != nil || len(rest) > 0 || id.HID < 0 || id.HID > 0xff {
+		return nil, errors.New("pkcs7: invalid SM9 identifier")
+	}
+	return &SM9Identifier{MasterKeyIdentifier: id.MasterKeyIdentifier, UID: id.UID, HID: byte(id.HID)}, nil
+}
+
+func (id *SM9Identifier) match(masterPublicKey, uid []byte, hid byte) bool {
+	return bytes.Equal(id.MasterKeyIdentifier, sm9MasterKeyIdentifier(masterPublicKey)) &&
+		bytes.Equal(id.UID, uid) && id.HID == hid
+}
+
+// isSM9Key checks if the provided key is an SM9 signature private key
+func isSM9Key(pkey any) bool {
+	_, ok := pkey.(*sm9.SignPrivateKey)
+	return ok
+}
+
+func isSM9Signer(signer signerInfo) bool {
+	return len(signer.SM9Identifier.Bytes) > 0
+}
+
+// NewSM9SignedData creates a new SignedData object with the GM/T 0010 SM9 content
+// types and the SM3 digest algorithm, the signers are added with AddSM9Signer.
+func NewSM9SignedData(data []byte) (*SignedData, error) {
+	sd, err := NewSignedData(data)
+	if err != nil {
+		return nil, err
+	}
+	sd.sd.ContentInfo.ContentType = SM9OIDData
+	sd.digestOid = OIDDigestAlgorithmSM3
+	sd.contentTypeOid = SM9OIDSignedData
+	return sd, nil
+}
+
+// AddSM9Signer signs the attributes about the content with the SM9 signature
+// private key of uid and adds the signer info to the SignedData. The signer is
+// identified by the master public key of priv, uid and hid instead of a
+// certificate, see [SM9Identifier].
+func (sd *SignedData) AddSM9Signer(priv *sm9.SignPrivateKey, uid []byte, hid byte, config SignerInfoConfig) error {
+	if priv == nil {
+		return errors.New("pkcs7: SM9 private key is nil")
+	}
+	if !sd.digestOid.Equal(OIDDigestAlgorithmSM3) {
+		return errors.New("pkcs7: SM9 signer requires SM3 digest algorithm")
+	}
+	id, err := marshalSM9Identifier(&SM9Identifier{
+		MasterKeyIdentifier: sm9MasterKeyIdentifier(priv.MasterPublic().Bytes()),
+		UID:                 uid,
+		HID:                 hid,
+	})
+	if err != nil {
+		return err
+	}
+	finalAttrs, signature, err := sd.signWithAttributes(priv, config)
+	if err != nil {
+		return err
+	}
+	sd.sd.DigestAlgorithmIdentifiers = append(sd.sd.DigestAlgorithmIdentifiers,
+		pkix.AlgorithmIdentifier{Algorithm: sd.digestOid, Parameters: asn1.NullRawValue},
+	)
+	signer := signerInfo{
+		AuthenticatedAttributes:   finalAttrs,
+		DigestAlgorithm:           pkix.AlgorithmIdentifier{Algorithm: sd.digestOid, Parameters: asn1.NullRawValue},
+		DigestEncryptionAlgorithm: pkix.AlgorithmIdentifier{Algorithm: OIDDigestEncryptionAlgorithmSM9, Parameters: asn1.NullRawValue},
+		SM9Identifier:             id,
+		EncryptedDigest:           signature,
+		Version:                   1,
+	}
+	if err = signer.SetUnauthenticatedAttributes(config.ExtraUnsignedAttributes); err != nil {
+		return err
+	}
+	sd.sd.SignerInfos = append(sd.sd.SignerInfos, signer)
+	return nil
+}
+
+// GetSM9Signers returns the identities of the SM9 signers.
+func (p7 *PKCS7) GetSM9Signers() ([]*SM9Identifier, error) {
+	var ids []*SM9Identifier
+	for _, signer := range p7.Signers {
+		if !isSM9Signer(signer) {
+			continue
+		}
+		id, err := parseSM9Identifier(signer.SM9Identifier)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// VerifySM9 checks the signatures of the SM9 signers with the signature master
+// public key, it fails if there is no SM9 signer or a signer belongs to another
+// master key. The signers identified by certificates are checked by Verify.
+func (p7 *PKCS7) VerifySM9(master *sm9.SignMasterPublicKey) error {
+	masterID := sm9MasterKeyIdentifier(master.Bytes())
+	var found bool
+	for _, signer := range p7.Signers {
+		if !isSM9Signer(signer) {
+			continue
+		}
+		found = true
+		id, err := parseSM9Identifier(signer.SM9Identifier)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(id.MasterKeyIdentifier, masterID) {
+			return errors.New("pkcs7: SM9 signer belongs to another master public key")
+		}
+		if err := verifySM9Signature(p7, signer, master, id); err != nil {
+			return err
+		}
+	}
+	if !found {
+		return errors.New("pkcs7: Message has no SM9 signers")
+	}
+	return nil
+}
+
+func verifySM9Signature(p7 *PKCS7, signer signerInfo, master *sm9.SignMasterPublicKey, id *SM9Identifier) error {
+	if !signer.DigestEncryptionAlgorithm.Algorithm.Equal(OIDDigestEncryptionAlgorithmSM9) {
+		return errors.New("pkcs7: unsupported SM9 signature algorithm")
+	}
+	signedData := p7.Content
+	if len(signer.AuthenticatedAttributes) > 0 {
+		var digest []byte
+		if err := unmarshalAttribute(signer.AuthenticatedAttributes, OIDAttributeMessageDigest, &digest); err != nil {
+			return err
+		}
+		if !signer.DigestAlgorithm.Algorithm.Equal(OIDDigestAlgorithmSM3) {
+			return errors.New("pkcs7: SM9 signer requires SM3 digest algorithm")
+		}
+		computed := sm3.Sum(p7.Content)
+		if subtle.ConstantTimeCompare(digest, computed[:]) != 1 {
+			return &MessageDigestMismatchError{
+				ExpectedDigest: digest,
+				ActualDigest:   computed[:],
+			}
+		}
+		var err error
+		if signedData, err = marshalAttributes(signer.AuthenticatedAttributes); err != nil {
+			return err
+		}
+	}
+	if !master.Verify(id.UID, id.HID, signedData, signer.EncryptedDigest) {
+		return errors.New("pkcs7: SM9 signature verification failed")
+	}
+	return nil
+}
+
+// NewSM9EnvelopedData creates a new EnvelopedData structure with the GM/T 0010
+// SM9 content types, the recipients are added with AddSM9Recipient.
+func NewSM9EnvelopedData(cipher pkcs.Cipher, content []byte) (*EnvelopedData, error) {
+	return newEnvelopedData(cipher, content, SM9OIDEnvelopedData, nil)
+}
+
+// AddSM9Recipient adds an SM9 recipient of uid and hid to the EnvelopedData.
+// The content encryption key is encapsulated with [sm9.EncryptMasterPublicKey.WrapKey]
+// and encrypted in the SM9Cipher format of the XOR mode, which is
+// [sm9.EncryptMasterPublicKey.Encrypt] with [sm9.DefaultEncrypterOpts].
+func (ed *EnvelopedData) AddSM9Recipient(master *sm9.EncryptMasterPublicKey, uid []byte, hid byte) error {
+	if master == nil {
+		return errors.New("pkcs7: SM9 master public key is nil")
+	}
+	encrypted, err := master.Encrypt(rand.Reader, uid, hid, ed.key, sm9.DefaultEncrypterOpts)
+	if err != nil {
+		return err
+	}
+	id, err := marshalSM9Identifier(&SM9Identifier{
+		MasterKeyIdentifier: sm9MasterKeyIdentifier(master.Bytes()),
+		UID:                 uid,
+		HID:                 hid,
+	})
+	if err != nil {
+		return err
+	}
+	ed.ed.RecipientInfos = append(ed.ed.RecipientInfos, recipientInfo{
+		Version:       1,
+		SM9Identifier: id,
+		KeyEncryptionAlgorithm: pkix.AlgorithmIdentifier{
+			Algorithm:  OIDKeyEncryptionAlgorithmSM9,
+			Parameters: asn1.NullRawValue,
+		},
+		EncryptedKey: encrypted,
+	})
+	return nil
+}
+
+// EncryptSM9 creates and returns an SM9 envelope data PKCS7 structure, the
+// content key is encrypted for every SM9 recipient with the master public key.
+func EncryptSM9(cipher pkcs.Cipher, content []byte, master *sm9.EncryptMasterPublicKey, recipients []sm9.Recipient) ([]byte, error) {
+	ed, err := NewSM9EnvelopedData(cipher, content)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range recipients {
+		if err := ed.AddSM9Recipient(master, r.UID, r.HID); err != nil {
+			return nil, err
+		}
+	}
+	return ed.Finish()
+}
+
+// DecryptSM9 decrypts the enveloped content with the SM9 encryption private key
+// of uid and hid.
+func (p7 *PKCS7) DecryptSM9(priv *sm9.EncryptPrivateKey, uid []byte, hid byte) ([]byte, error) {
+	decryptableData, ok := p7.raw.(envelopedData)
+	if !ok {
+		return nil, ErrNotEnvelopedData
+	}
+	masterPublicKey := priv.MasterPublic().Bytes()
+	for _, recp := range decryptableData.RecipientInfos {
+		id, err := parseSM9Identifier(recp.SM9Identifier)
+		if err != nil || !id.match(masterPublicKey, uid, hid) {
+			continue
+		}
+		if !recp.KeyEncryptionAlgorithm.Algorithm.Equal(OIDKeyEncryptionAlgorithmSM9) {
+			return nil, ErrUnsupportedAlgorithm
+		}
+		contentKey, err := priv.DecryptASN1(uid, recp.EncryptedKey)
+		if err != nil {
+			return nil, err
+		}
+		return decryptableData.GetEncryptedContentInfo().decrypt(contentKey)
+	}
+	return nil, errors.New("pkcs7: no enveloped recipient for provided SM9 identity")
+}
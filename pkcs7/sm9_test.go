@@ -0,0 +1,133 @@
+package pkcs7
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/emmansun/gmsm/pkcs"
+	"github.com/emmansun/gmsm/sm9"
+)
+
+func TestSM9SignedData(t *testing.T) {
+	master, err := sm9.GenerateSignMasterKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uid := []byte("emmansun")
+	hid := byte(0x01)
+	priv, err := master.GenerateUserKey(uid, hid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := []byte("Hello World")
+	toBeSigned, err := NewSM9SignedData(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := toBeSigned.AddSM9Signer(priv, uid, hid, SignerInfoConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	signed, err := toBeSigned.Finish()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p7, err := Parse(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(p7.Content, content) {
+		t.Fatalf("content mismatch %x", p7.Content)
+	}
+	signers, err := p7.GetSM9Signers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signers) != 1 || !bytes.Equal(signers[0].UID, uid) || signers[0].HID != hid {
+		t.Fatalf("unexpected signers %v", signers)
+	}
+	if err := p7.VerifySM9(master.PublicKey()); err != nil {
+		t.Fatal(err)
+	}
+	if err := p7.Verify(); err == nil {
+		t.Fatal("expected error for verifying SM9 signer with certificates")
+	}
+
+	other, err := sm9.GenerateSignMasterKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p7.VerifySM9(other.PublicKey()); err == nil {
+		t.Fatal("expected error for another master public key")
+	}
+	p7.Content = []byte("Hello Word")
+	if err := p7.VerifySM9(master.PublicKey()); err == nil {
+		t.Fatal("expected error for tampered content")
+	}
+
+	// the signature of the attributes is checked
+	p7.Content = content
+	p7.Signers[0].EncryptedDigest[len(p7.Signers[0].EncryptedDigest)-1] ^= 1
+	if err := p7.VerifySM9(master.PublicKey()); err == nil {
+		t.Fatal("expected error for invalid signature")
+	}
+
+	toBeSigned, _ = NewSMSignedData(content)
+	toBeSigned.SetDigestAlgorithm(OIDDigestAlgorithmSHA256)
+	if err := toBeSigned.AddSM9Signer(priv, uid, hid, SignerInfoConfig{}); err == nil {
+		t.Fatal("expected error for non SM3 digest")
+	}
+}
+
+func TestSM9EnvelopedData(t *testing.T) {
+	master, err := sm9.GenerateEncryptMasterKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hid := byte(0x03)
+	recipients := []sm9.Recipient{{UID: []byte("Alice"), HID: hid}, {UID: []byte("Bob"), HID: hid}}
+	content := []byte("SM9 enveloped content")
+	for _, cipher := range []pkcs.Cipher{pkcs.SM4CBC, pkcs.SM4GCM} {
+		enveloped, err := EncryptSM9(cipher, content, master.PublicKey(), recipients)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p7, err := Parse(enveloped)
+		if err != nil {
+			t.Fatal(err)
+		}
+		infos, err := p7.GetRecipients()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(infos) != 2 {
+			t.Fatalf("unexpected recipients %v", infos)
+		}
+		for _, info := range infos {
+			// the recipient infos are a DER SET, so the order is not kept
+			if info.SM9Identifier == nil || info.SM9Identifier.HID != hid {
+				t.Fatalf("unexpected recipient %v", info)
+			}
+		}
+		for _, r := range recipients {
+			priv, err := master.GenerateUserKey(r.UID, r.HID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := p7.DecryptSM9(priv, r.UID, r.HID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, content) {
+				t.Fatal("decrypted content mismatch")
+			}
+		}
+		outsider, err := master.GenerateUserKey([]byte("Carol"), hid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := p7.DecryptSM9(outsider, []byte("Carol"), hid); err == nil {
+			t.Fatal("expected error for an outsider")
+		}
+	}
+}
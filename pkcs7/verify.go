@@ -91,6 +91,9 @@ func (e *InvalidSigningTimeError) Error() string {
 }
 
 func verifySignature(p7 *PKCS7, signer signerInfo, truststore *smx509.CertPool, currentTime *time.Time, isDigest bool) (err error) {
+	if isSM9Signer(signer) {
+		return errors.New("pkcs7: SM9 signer has no certificate, use VerifySM9 instead")
+	}
 	signedData := p7.Content
 	ee := getCertFromCertsByIssuerAndSerial(p7.Certificates, signer.IssuerAndSerialNumber)
 	if ee == nil {
@@ -0,0 +1,44 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package shamir
+
+// The arithmetic of GF(2^8) with the polynomial x^8 + x^4 + x^3 + x + 1,
+// without lookup tables nor branches on the values.
+
+// gfMul returns a * b.
+func gfMul(a, b byte) byte {
+	var r byte
+	for i := 0; i < 8; i++ {
+		r ^= a & -(b & 1)
+		b >>= 1
+		a = a<<1 ^ 0x1b&-(a>>7)
+	}
+	return r
+}
+
+// gfInv returns a^254, the inverse of a, or 0 if a is 0.
+func gfInv(a byte) byte {
+	a2 := gfMul(a, a)
+	a3 := gfMul(a2, a)
+	a6 := gfMul(a3, a3)
+	a12 := gfMul(a6, a6)
+	a15 := gfMul(a12, a3)
+	a30 := gfMul(a15, a15)
+	a60 := gfMul(a30, a30)
+	a63 := gfMul(a60, a3)
+	a126 := gfMul(a63, a63)
+	a127 := gfMul(a126, a)
+	return gfMul(a127, a127)
+}
+
+// gfEval returns the value at x of the polynomial of the coefficients coeffs,
+// the constant term first.
+func gfEval(coeffs []byte, x byte) byte {
+	var y byte
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		y = gfMul(y, x) ^ coeffs[i]
+	}
+	return y
+}
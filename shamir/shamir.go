@@ -0,0 +1,165 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package shamir implements Shamir's threshold secret sharing, for the M-of-N
+// backup of keys such as SM2 private keys and SM4 keys.
+//
+// [Split] and [Combine] share secrets of any length byte by byte over
+// GF(2^8). [SplitFeldman] and [SplitPedersen] share a scalar modulo the order
+// of the SM2 curve, and publish a [Commitment] to the polynomial with which
+// each share holder verifies its share, see [Commitment.Verify]. The
+// arithmetic doesn't branch on nor index memory with the secret values.
+//
+// The shares and the commitments have an encoding which embeds the threshold
+// and the share index:
+//
+//	Share          0x01 || threshold || index || value, as long as the secret
+//	ScalarShare    0x02 || threshold || index || value (32 bytes), Feldman
+//	               0x03 || threshold || index || value || blinding value, Pedersen
+//	Commitment     0x02 or 0x03 || threshold || threshold compressed points
+//
+// The thresholds and the indices are between 1 and 255.
+package shamir
+
+import (
+	"errors"
+	"io"
+)
+
+const (
+	typeGF256    = 0x01
+	typeFeldman  = 0x02
+	typePedersen = 0x03
+
+	headerSize = 3
+)
+
+// Share is a share of a secret split with [Split].
+type Share struct {
+	Threshold byte   // the number of shares needed to recover the secret
+	Index     byte   // the non-zero x-coordinate of the share
+	Value     []byte // the y-coordinates, one per byte of the secret
+}
+
+// Bytes returns the encoding of the share.
+func (s *Share) Bytes() []byte {
+	return append([]byte{typeGF256, s.Threshold, s.Index}, s.Value...)
+}
+
+// ParseShare parses an encoded share of [Split].
+func ParseShare(b []byte) (*Share, error) {
+	if len(b) < headerSize+1 || b[0] != typeGF256 || b[1] == 0 || b[2] == 0 {
+		return nil, errors.New("shamir: invalid share encoding")
+	}
+	return &Share{Threshold: b[1], Index: b[2], Value: append([]byte{}, b[headerSize:]...)}, nil
+}
+
+func checkParameters(n, threshold int) error {
+	if threshold < 2 || threshold > n || n > 255 {
+		return errors.New("shamir: invalid number of shares or threshold")
+	}
+	return nil
+}
+
+// Split splits the non-empty secret into n shares, any threshold of which
+// recover the secret with [Combine]. The share indices are 1 to n. The
+// threshold is at least 2 and n is at most 255.
+func Split(rand io.Reader, secret []byte, n, threshold int) ([]*Share, error) {
+	if err := checkParameters(n, threshold); err != nil {
+		return nil, err
+	}
+	if len(secret) == 0 {
+		return nil, errors.New("shamir: empty secret")
+	}
+	// the coefficients of the polynomial of the i-th byte are
+	// secret[i], coeffs[i*(threshold-1):(i+1)*(threshold-1)]
+	random := make([]byte, len(secret)*(threshold-1))
+	if _, err := io.ReadFull(rand, random); err != nil {
+		return nil, err
+	}
+	shares := make([]*Share, n)
+	for j := range shares {
+		shares[j] = &Share{Threshold: byte(threshold), Index: byte(j + 1), Value: make([]byte, len(secret))}
+	}
+	coeffs := make([]byte, threshold)
+	for i, s := range secret {
+		coeffs[0] = s
+		copy(coeffs[1:], random[i*(threshold-1):])
+		for _, share := range shares {
+			share.Value[i] = gfEval(coeffs, share.Index)
+		}
+	}
+	clear(coeffs)
+	clear(random)
+	return shares, nil
+}
+
+// checkIndices checks that there are enough shares with the same valid
+// threshold and distinct indices, and returns the indices of the first threshold
+// shares.
+func checkIndices(count int, share func(int) (threshold, index byte)) ([]byte, error) {
+	if count == 0 {
+		return nil, errors.New("shamir: no shares")
+	}
+	threshold, _ := share(0)
+	if threshold < 2 {
+		return nil, errors.New("shamir: invalid threshold")
+	}
+	if int(threshold) > count {
+		return nil, errors.New("shamir: not enough shares")
+	}
+	indices := make([]byte, threshold)
+	var seen [256]bool
+	for i := 0; i < count; i++ {
+		t, x := share(i)
+		if t != threshold {
+			return nil, errors.New("shamir: shares of different thresholds")
+		}
+		if x == 0 || seen[x] {
+			return nil, errors.New("shamir: invalid or duplicate share index")
+		}
+		seen[x] = true
+		if i < int(threshold) {
+			indices[i] = x
+		}
+	}
+	return indices, nil
+}
+
+// Combine recovers the secret from at least threshold shares of [Split]. Only
+// the first threshold shares are used; Combine can't detect an invalid share,
+// which gives a wrong secret.
+func Combine(shares []*Share) ([]byte, error) {
+	indices, err := checkIndices(len(shares), func(i int) (byte, byte) {
+		return shares[i].Threshold, shares[i].Index
+	})
+	if err != nil {
+		return nil, err
+	}
+	size := len(shares[0].Value)
+	for _, share := range shares[:len(indices)] {
+		if len(share.Value) != size || size == 0 {
+			return nil, errors.New("shamir: shares of different lengths")
+		}
+	}
+	// the Lagrange coefficients at 0, l_i = prod_{j != i} x_j / (x_j - x_i)
+	lagrange := make([]byte, len(indices))
+	for i, xi := range indices {
+		num, den := byte(1), byte(1)
+		for j, xj := range indices {
+			if i != j {
+				num = gfMul(num, xj)
+				den = gfMul(den, xj^xi)
+			}
+		}
+		lagrange[i] = gfMul(num, gfInv(den))
+	}
+	secret := make([]byte, size)
+	for i, l := range lagrange {
+		for k, y := range shares[i].Value {
+			secret[k] ^= gfMul(l, y)
+		}
+	}
+	return secret, nil
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package shamir
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+)
+
+func TestGF256(t *testing.T) {
+	// FIPS 197, section 4.2
+	if got := gfMul(0x57, 0x83); got != 0xc1 {
+		t.Errorf("0x57 * 0x83 = %#x, want 0xc1", got)
+	}
+	if got := gfMul(0x57, 0x13); got != 0xfe {
+		t.Errorf("0x57 * 0x13 = %#x, want 0xfe", got)
+	}
+	if gfInv(0) != 0 {
+		t.Error("the inverse of 0 is not 0")
+	}
+	for a := 1; a < 256; a++ {
+		if got := gfMul(byte(a), gfInv(byte(a))); got != 1 {
+			t.Errorf("%#x * %#x^-1 = %#x", a, a, got)
+		}
+	}
+}
+
+// TestSplitVector fixes the randomness of Split, so that a change of the
+// GF(2^8) arithmetic or of the share layout changes the shares.
+func TestSplitVector(t *testing.T) {
+	secret := []byte("SM4 master key!!")
+	random := make([]byte, 32)
+	for i := range random {
+		random[i] = byte(0xa0 + i)
+	}
+	shares, err := Split(bytes.NewReader(random), secret, 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"010301524c35216c6072756473216a64782020",
+		"010302baa8c5ddb4b4b2b9ecf7b1f6dccc808c",
+		"010303bba9c4dcb5b5b3b8edf6b0f7ddcd818d",
+		"0103041b2d2c1085a1cbe476496300d6e2c2ea",
+		"0103051a2c2d1184a0cae577486201d7e3c3eb",
+	}
+	for i, share := range shares {
+		if got := hex.EncodeToString(share.Bytes()); got != want[i] {
+			t.Errorf("share %d = %s, want %s", i, got, want[i])
+		}
+	}
+}
+
+func TestSplitCombine(t *testing.T) {
+	secret := []byte("the quick brown fox jumps over the lazy dog")
+	for _, tc := range []struct{ n, threshold int }{{2, 2}, {3, 2}, {5, 3}, {7, 7}, {255, 10}} {
+		shares, err := Split(rand.Reader, secret, tc.n, tc.threshold)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// every window of threshold shares, in both orders, through the encoding
+		for start := 0; start+tc.threshold <= tc.n; start += tc.threshold {
+			subset := make([]*Share, tc.threshold)
+			for i := range subset {
+				if subset[i], err = ParseShare(shares[start+tc.threshold-1-i].Bytes()); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := Combine(subset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, secret) {
+				t.Fatalf("%d-of-%d: got %q", tc.threshold, tc.n, got)
+			}
+		}
+		// fewer shares give no information, and Combine refuses them
+		if _, err := Combine(shares[:tc.threshold-1]); err == nil {
+			t.Errorf("%d-of-%d: expected error with too few shares", tc.threshold, tc.n)
+		}
+	}
+}
+
+func TestShareErrors(t *testing.T) {
+	for _, tc := range []struct{ n, threshold int }{{1, 1}, {3, 1}, {2, 3}, {256, 2}} {
+		if _, err := Split(rand.Reader, []byte("secret"), tc.n, tc.threshold); err == nil {
+			t.Errorf("expected error with n = %d and threshold = %d", tc.n, tc.threshold)
+		}
+	}
+	if _, err := Split(rand.Reader, nil, 3, 2); err == nil {
+		t.Error("expected error with empty secret")
+	}
+	shares, err := Split(rand.Reader, []byte("secret"), 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Combine([]*Share{shares[0], shares[0]}); err == nil {
+		t.Error("expected error with duplicate shares")
+	}
+	other, _ := Split(rand.Reader, []byte("secret"), 3, 3)
+	if _, err := Combine([]*Share{shares[0], other[1], other[2]}); err == nil {
+		t.Error("expected error with different thresholds")
+	}
+	for _, threshold := range []byte{0, 1} {
+		forged := []*Share{{Threshold: threshold, Index: 1, Value: shares[0].Value}}
+		if _, err := Combine(forged); err == nil {
+			t.Errorf("expected error with threshold %d", threshold)
+		}
+	}
+	longer, _ := Split(rand.Reader, []byte("longer secret"), 3, 2)
+	if _, err := Combine([]*Share{shares[0], longer[1]}); err == nil {
+		t.Error("expected error with different lengths")
+	}
+	for _, b := range []string{"", "010201", "020201aa", "010001aa", "010200aa"} {
+		raw, _ := hex.DecodeString(b)
+		if _, err := ParseShare(raw); err == nil {
+			t.Errorf("expected error parsing %q", b)
+		}
+	}
+}
+
+func BenchmarkSplit(b *testing.B) {
+	secret := make([]byte, 32)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := Split(rand.Reader, secret, 5, 3); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,325 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package shamir
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"io"
+
+	"github.com/emmansun/gmsm/sm2"
+	"github.com/emmansun/gmsm/sm2/sm2ec"
+)
+
+// generatorH is the second generator of the Pedersen commitments, the hash
+// to the SM2 curve of "Pedersen commitment generator H" with the suite
+// SM2P256_XMD:SM3_SSWU_RO_ and the DST
+// "GMSM-SHAMIR-V01-CS01-with-SM2P256_XMD:SM3_SSWU_RO_", so that its discrete
+// logarithm to the base G is unknown.
+var generatorH = mustDecodePoint("033138b7bc223d00f7a8ae42cbfacb486c4b7e4334327e2af265ef6ec87d75a054")
+
+func mustDecodePoint(s string) *sm2ec.Point {
+	b, _ := hex.DecodeString(s)
+	p, err := sm2ec.NewPoint().SetBytes(b)
+	if err != nil {
+		panic("shamir: invalid constant point")
+	}
+	return p
+}
+
+const (
+	scalarSize = sm2ec.ScalarSize
+	pointSize  = 33
+)
+
+// ScalarShare is a share of a scalar split with [SplitFeldman] or
+// [SplitPedersen].
+type ScalarShare struct {
+	Threshold byte   // the number of shares needed to recover the secret
+	Index     byte   // the non-zero x-coordinate of the share
+	Value     []byte // f(Index), 32 bytes
+	Blinding  []byte // g(Index) of the Pedersen commitment, nil for Feldman
+}
+
+// Bytes returns the encoding of the share.
+func (s *ScalarShare) Bytes() []byte {
+	typ := byte(typeFeldman)
+	if s.Blinding != nil {
+		typ = typePedersen
+	}
+	b := append([]byte{typ, s.Threshold, s.Index}, s.Value...)
+	return append(b, s.Blinding...)
+}
+
+// ParseScalarShare parses an encoded share of [SplitFeldman] or
+// [SplitPedersen].
+func ParseScalarShare(b []byte) (*ScalarShare, error) {
+	if len(b) < headerSize || b[1] == 0 || b[2] == 0 ||
+		!(b[0] == typeFeldman && len(b) == headerSize+scalarSize ||
+			b[0] == typePedersen && len(b) == headerSize+2*scalarSize) {
+		return nil, errors.New("shamir: invalid share encoding")
+	}
+	s := &ScalarShare{Threshold: b[1], Index: b[2], Value: append([]byte{}, b[headerSize:headerSize+scalarSize]...)}
+	if b[0] == typePedersen {
+		s.Blinding = append([]byte{}, b[headerSize+scalarSize:]...)
+	}
+	if _, err := sm2ec.NewScalar().SetCanonicalBytes(s.Value); err != nil {
+		return nil, errors.New("shamir: invalid share value")
+	}
+	if s.Blinding != nil {
+		if _, err := sm2ec.NewScalar().SetCanonicalBytes(s.Blinding); err != nil {
+			return nil, errors.New("shamir: invalid share value")
+		}
+	}
+	return s, nil
+}
+
+// Commitment is the public commitment to the polynomial of a verifiable
+// secret sharing, with which each share holder verifies its share.
+type Commitment struct {
+	pedersen bool
+	points   []*sm2ec.Point // the commitments to the coefficients
+}
+
+// Threshold returns the number of shares needed to recover the secret.
+func (c *Commitment) Threshold() int {
+	return len(c.points)
+}
+
+// Bytes returns the encoding of the commitment.
+func (c *Commitment) Bytes() []byte {
+	typ := byte(typeFeldman)
+	if c.pedersen {
+		typ = typePedersen
+	}
+	b := make([]byte, 0, 2+len(c.points)*pointSize)
+	b = append(b, typ, byte(len(c.points)))
+	for _, p := range c.points {
+		b = append(b, p.BytesCompressed()...)
+	}
+	return b
+}
+
+// ParseCommitment parses an encoded commitment.
+func ParseCommitment(b []byte) (*Commitment, error) {
+	if len(b) < 2 || b[0] != typeFeldman && b[0] != typePedersen || b[1] < 2 ||
+		len(b) != 2+int(b[1])*pointSize {
+		return nil, errors.New("shamir: invalid commitment encoding")
+	}
+	c := &Commitment{pedersen: b[0] == typePedersen, points: make([]*sm2ec.Point, b[1])}
+	for i := range c.points {
+		p, err := sm2ec.NewPoint().SetBytes(b[2+i*pointSize : 2+(i+1)*pointSize])
+		if err != nil {
+			return nil, errors.New("shamir: invalid commitment point")
+		}
+		c.points[i] = p
+	}
+	return c, nil
+}
+
+// PublicKey returns the commitment to the secret of a Feldman commitment,
+// secret*G, which is the public key of a private key split with
+// [SplitPrivateKey].
+func (c *Commitment) PublicKey() (*ecdsa.PublicKey, error) {
+	if c.pedersen {
+		return nil, errors.New("shamir: a Pedersen commitment hides the secret")
+	}
+	return sm2.NewPublicKey(c.points[0].Bytes())
+}
+
+// Verify checks that the share is consistent with the commitment, that is
+// f(Index)*G = sum C_j*Index^j for Feldman, and
+// f(Index)*G + g(Index)*H = sum C_j*Index^j for Pedersen.
+func (c *Commitment) Verify(share *ScalarShare) error {
+	if int(share.Threshold) != len(c.points) || share.Index == 0 || c.pedersen != (share.Blinding != nil) {
+		return errors.New("shamir: share doesn't match the commitment")
+	}
+	y, err := sm2ec.NewScalar().SetCanonicalBytes(share.Value)
+	if err != nil {
+		return errors.New("shamir: invalid share value")
+	}
+	var lhs *sm2ec.Point
+	if c.pedersen {
+		b, err := sm2ec.NewScalar().SetCanonicalBytes(share.Blinding)
+		if err != nil {
+			return errors.New("shamir: invalid share value")
+		}
+		lhs, err = sm2ec.NewPoint().CombinedMult(generatorH, y.Bytes(), b.Bytes())
+		if err != nil {
+			return err
+		}
+	} else if lhs, err = sm2ec.NewPoint().ScalarBaseMult(y.Bytes()); err != nil {
+		return err
+	}
+	// Horner's rule on the points
+	x := indexToScalar(share.Index).Bytes()
+	rhs := sm2ec.NewPoint().Set(c.points[len(c.points)-1])
+	for j := len(c.points) - 2; j >= 0; j-- {
+		if _, err := rhs.ScalarMult(rhs, x); err != nil {
+			return err
+		}
+		rhs.Add(rhs, c.points[j])
+	}
+	if lhs.Equal(rhs) != 1 {
+		return errors.New("shamir: share verification failed")
+	}
+	return nil
+}
+
+func indexToScalar(x byte) *sm2ec.Scalar {
+	b := make([]byte, scalarSize)
+	b[scalarSize-1] = x
+	s, _ := sm2ec.NewScalar().SetCanonicalBytes(b)
+	return s
+}
+
+// randomPolynomial returns a polynomial of degree threshold-1 with the
+// constant term c0 and random other coefficients.
+func randomPolynomial(rand io.Reader, c0 *sm2ec.Scalar, threshold int) ([]*sm2ec.Scalar, error) {
+	coeffs := make([]*sm2ec.Scalar, threshold)
+	coeffs[0] = c0
+	b := make([]byte, 48)
+	for i := 1; i < threshold; i++ {
+		if _, err := io.ReadFull(rand, b); err != nil {
+			return nil, err
+		}
+		coeffs[i], _ = sm2ec.NewScalar().SetUniformBytes(b)
+	}
+	clear(b)
+	return coeffs, nil
+}
+
+func evalPolynomial(coeffs []*sm2ec.Scalar, x *sm2ec.Scalar) *sm2ec.Scalar {
+	y := sm2ec.NewScalar()
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		y.Multiply(y, x)
+		y.Add(y, coeffs[i])
+	}
+	return y
+}
+
+func splitScalar(rand io.Reader, secret []byte, n, threshold int, pedersen bool) ([]*ScalarShare, *Commitment, error) {
+	if err := checkParameters(n, threshold); err != nil {
+		return nil, nil, err
+	}
+	s, err := sm2ec.NewScalar().SetCanonicalBytes(secret)
+	if err != nil {
+		return nil, nil, errors.New("shamir: secret is not a scalar")
+	}
+	if !pedersen && s.IsZero() == 1 {
+		return nil, nil, errors.New("shamir: secret is zero")
+	}
+	f, err := randomPolynomial(rand, s, threshold)
+	if err != nil {
+		return nil, nil, err
+	}
+	var g []*sm2ec.Scalar
+	if pedersen {
+		b := make([]byte, 48)
+		if _, err := io.ReadFull(rand, b); err != nil {
+			return nil, nil, err
+		}
+		r, _ := sm2ec.NewScalar().SetUniformBytes(b)
+		if g, err = randomPolynomial(rand, r, threshold); err != nil {
+			return nil, nil, err
+		}
+	}
+	c := &Commitment{pedersen: pedersen, points: make([]*sm2ec.Point, threshold)}
+	for j := range c.points {
+		if pedersen {
+			c.points[j], err = sm2ec.NewPoint().CombinedMult(generatorH, f[j].Bytes(), g[j].Bytes())
+		} else {
+			c.points[j], err = sm2ec.NewPoint().ScalarBaseMult(f[j].Bytes())
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	shares := make([]*ScalarShare, n)
+	for i := range shares {
+		x := indexToScalar(byte(i + 1))
+		shares[i] = &ScalarShare{Threshold: byte(threshold), Index: byte(i + 1), Value: evalPolynomial(f, x).Bytes()}
+		if pedersen {
+			shares[i].Blinding = evalPolynomial(g, x).Bytes()
+		}
+	}
+	return shares, c, nil
+}
+
+// SplitFeldman splits the 32-byte big-endian scalar secret, non-zero and lower
+// than the order of the SM2 curve, into n shares, any threshold of which recover the
+// secret with [CombineScalar], with the Feldman commitment C_j = a_j*G to the
+// coefficients a_j of the polynomial.
+//
+// The commitment reveals secret*G, so the secret must have enough entropy to
+// resist a discrete logarithm search: use [SplitPedersen] for short secrets.
+func SplitFeldman(rand io.Reader, secret []byte, n, threshold int) ([]*ScalarShare, *Commitment, error) {
+	return splitScalar(rand, secret, n, threshold, false)
+}
+
+// SplitPedersen is like [SplitFeldman], but with the Pedersen commitment
+// C_j = a_j*G + b_j*H, where b_j are the coefficients of a second random
+// polynomial, which reveals no information about the secret.
+//
+// A 16-byte SM4 key is shared as a 32-byte scalar with 16 leading zeros.
+func SplitPedersen(rand io.Reader, secret []byte, n, threshold int) ([]*ScalarShare, *Commitment, error) {
+	return splitScalar(rand, secret, n, threshold, true)
+}
+
+// CombineScalar recovers the 32-byte secret from at least threshold shares of
+// [SplitFeldman] or [SplitPedersen]. Only the first threshold shares are used;
+// they should be verified with [Commitment.Verify] first.
+func CombineScalar(shares []*ScalarShare) ([]byte, error) {
+	indices, err := checkIndices(len(shares), func(i int) (byte, byte) {
+		return shares[i].Threshold, shares[i].Index
+	})
+	if err != nil {
+		return nil, err
+	}
+	x := make([]*sm2ec.Scalar, len(indices))
+	for i, xi := range indices {
+		x[i] = indexToScalar(xi)
+	}
+	secret := sm2ec.NewScalar()
+	for i := range indices {
+		y, err := sm2ec.NewScalar().SetCanonicalBytes(shares[i].Value)
+		if err != nil {
+			return nil, errors.New("shamir: invalid share value")
+		}
+		// the Lagrange coefficient at 0, prod_{j != i} x_j / (x_j - x_i)
+		num, den := sm2ec.NewScalar().SetOne(), sm2ec.NewScalar().SetOne()
+		diff := sm2ec.NewScalar()
+		for j := range indices {
+			if i != j {
+				num.Multiply(num, x[j])
+				den.Multiply(den, diff.Subtract(x[j], x[i]))
+			}
+		}
+		y.Multiply(y, num.Multiply(num, den.Invert(den)))
+		secret.Add(secret, y)
+	}
+	return secret.Bytes(), nil
+}
+
+// SplitPrivateKey splits the SM2 private key into n shares with
+// [SplitFeldman]. The public key of the returned commitment is the public key
+// of priv.
+func SplitPrivateKey(rand io.Reader, priv *sm2.PrivateKey, n, threshold int) ([]*ScalarShare, *Commitment, error) {
+	if priv.D == nil || priv.D.BitLen() > 256 {
+		return nil, nil, errors.New("shamir: invalid private key")
+	}
+	return SplitFeldman(rand, priv.D.FillBytes(make([]byte, scalarSize)), n, threshold)
+}
+
+// CombinePrivateKey recovers the SM2 private key from at least threshold
+// shares of [SplitPrivateKey].
+func CombinePrivateKey(shares []*ScalarShare) (*sm2.PrivateKey, error) {
+	d, err := CombineScalar(shares)
+	if err != nil {
+		return nil, err
+	}
+	return sm2.NewPrivateKey(d)
+}
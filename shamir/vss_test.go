@@ -0,0 +1,202 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package shamir
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/emmansun/gmsm/h2c"
+	"github.com/emmansun/gmsm/sm2"
+	"github.com/emmansun/gmsm/sm2/sm2ec"
+)
+
+func TestGeneratorH(t *testing.T) {
+	b, err := h2c.HashToCurve([]byte("Pedersen commitment generator H"), []byte("GMSM-SHAMIR-V01-CS01-with-SM2P256_XMD:SM3_SSWU_RO_"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := sm2ec.NewPoint().SetBytes(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.Equal(generatorH) != 1 {
+		t.Errorf("H = %x, want %x", generatorH.BytesCompressed(), h.BytesCompressed())
+	}
+}
+
+func TestVSS(t *testing.T) {
+	secret := make([]byte, 32)
+	copy(secret[16:], "0123456789abcdef") // an SM4 key as a scalar
+	for _, split := range []struct {
+		name string
+		f    func([]byte, int, int) ([]*ScalarShare, *Commitment, error)
+	}{
+		{"Feldman", func(s []byte, n, t int) ([]*ScalarShare, *Commitment, error) {
+			return SplitFeldman(rand.Reader, s, n, t)
+		}},
+		{"Pedersen", func(s []byte, n, t int) ([]*ScalarShare, *Commitment, error) {
+			return SplitPedersen(rand.Reader, s, n, t)
+		}},
+	} {
+		t.Run(split.name, func(t *testing.T) {
+			shares, c, err := split.f(secret, 5, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the share holders receive the encodings
+			c, err = ParseCommitment(c.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.Threshold() != 3 {
+				t.Errorf("threshold = %d", c.Threshold())
+			}
+			for i, share := range shares {
+				if shares[i], err = ParseScalarShare(share.Bytes()); err != nil {
+					t.Fatal(err)
+				}
+				if err := c.Verify(shares[i]); err != nil {
+					t.Errorf("share %d: %v", i, err)
+				}
+			}
+			for _, subset := range [][]*ScalarShare{shares[:3], shares[2:], {shares[4], shares[0], shares[2]}, shares} {
+				got, err := CombineScalar(subset)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(got, secret) {
+					t.Fatalf("got %x, want %x", got, secret)
+				}
+			}
+			if _, err := CombineScalar(shares[:2]); err == nil {
+				t.Error("expected error with too few shares")
+			}
+
+			// a tampered share is detected
+			bad := *shares[1]
+			bad.Value = bytes.Clone(bad.Value)
+			bad.Value[31] ^= 1
+			if err := c.Verify(&bad); err == nil {
+				t.Error("expected error with tampered value")
+			}
+			bad = *shares[1]
+			bad.Index = 6
+			if err := c.Verify(&bad); err == nil {
+				t.Error("expected error with another index")
+			}
+			if bad.Blinding != nil {
+				bad = *shares[1]
+				bad.Blinding = bytes.Clone(bad.Blinding)
+				bad.Blinding[0] ^= 1
+				if err := c.Verify(&bad); err == nil {
+					t.Error("expected error with tampered blinding value")
+				}
+			}
+			// shares of another sharing
+			other, _, err := split.f(secret, 5, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := c.Verify(other[0]); err == nil {
+				t.Error("expected error with a share of another sharing")
+			}
+		})
+	}
+}
+
+func TestPrivateKey(t *testing.T) {
+	priv, err := sm2.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares, c, err := SplitPrivateKey(rand.Reader, priv, 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := c.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pub.Equal(&priv.PublicKey) {
+		t.Error("the commitment doesn't reveal the public key")
+	}
+	got, err := CombinePrivateKey(shares[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(priv) {
+		t.Error("recovered private key mismatch")
+	}
+
+	_, pc, err := SplitPedersen(rand.Reader, priv.D.FillBytes(make([]byte, 32)), 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pc.PublicKey(); err == nil {
+		t.Error("expected error with a Pedersen commitment")
+	}
+	if err := pc.Verify(shares[0]); err == nil {
+		t.Error("expected error with a Feldman share and a Pedersen commitment")
+	}
+}
+
+func TestVSSErrors(t *testing.T) {
+	n := sm2ec.P256().Params().N.Bytes()
+	if _, _, err := SplitFeldman(rand.Reader, n, 3, 2); err == nil {
+		t.Error("expected error with a secret not lower than the order")
+	}
+	if _, _, err := SplitFeldman(rand.Reader, make([]byte, 32), 3, 2); err == nil {
+		t.Error("expected error with a zero secret")
+	}
+	if _, _, err := SplitPedersen(rand.Reader, make([]byte, 16), 3, 2); err == nil {
+		t.Error("expected error with a short secret")
+	}
+	if _, _, err := SplitPedersen(rand.Reader, make([]byte, 32), 3, 4); err == nil {
+		t.Error("expected error with threshold greater than n")
+	}
+	for _, b := range [][]byte{
+		nil,
+		{typeFeldman, 2, 1},
+		append([]byte{typeFeldman, 2, 0}, make([]byte, 32)...),
+		append([]byte{typePedersen, 2, 1}, make([]byte, 32)...),
+		append([]byte{typeFeldman, 2, 1}, n...),
+	} {
+		if _, err := ParseScalarShare(b); err == nil {
+			t.Errorf("expected error parsing share %x", b)
+		}
+	}
+	_, c, err := SplitFeldman(rand.Reader, bytes.Repeat([]byte{1}, 32), 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CombineScalar([]*ScalarShare{{Index: 1, Value: make([]byte, 32)}}); err == nil {
+		t.Error("expected error with threshold 0")
+	}
+	enc := c.Bytes()
+	for _, b := range [][]byte{nil, enc[:len(enc)-1], append([]byte{typeGF256}, enc[1:]...), append([]byte{typeFeldman, 1}, enc[2:35]...)} {
+		if _, err := ParseCommitment(b); err == nil {
+			t.Errorf("expected error parsing commitment %x", b)
+		}
+	}
+	enc[2] = 4
+	if _, err := ParseCommitment(enc); err == nil {
+		t.Error("expected error with invalid point")
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	shares, c, err := SplitPedersen(rand.Reader, bytes.Repeat([]byte{1}, 32), 5, 3)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := c.Verify(shares[4]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package sm2ec
+
+import (
+	"crypto/subtle"
+
+	"github.com/emmansun/gmsm/internal/sm2ec"
+)
+
+// Point is a point of the SM2 curve. The API mirrors filippo.io/nistec: all the
+// operations are constant time, points are encoded according to SEC 1, Version
+// 2.0, Section 2.3.3, and invalid curve points can't be represented.
+//
+// The zero value is NOT valid, use [NewPoint] to create a Point.
+type Point struct {
+	p sm2ec.SM2P256Point
+}
+
+// NewPoint returns a new Point representing the point at infinity.
+func NewPoint() *Point {
+	p := &Point{}
+	p.p.Set(sm2ec.NewSM2P256Point())
+	return p
+}
+
+// SetGenerator sets p to the canonical generator and returns p.
+func (p *Point) SetGenerator() *Point {
+	p.p.SetGenerator()
+	return p
+}
+
+// Set sets p = q and returns p.
+func (p *Point) Set(q *Point) *Point {
+	p.p.Set(&q.p)
+	return p
+}
+
+// SetBytes sets p to the compressed, uncompressed, or infinity value encoded in
+// b, as specified in SEC 1, Version 2.0, Section 2.3.4. If the point is not on
+// the curve, it returns nil and an error, and the receiver is unchanged.
+// Otherwise, it returns p.
+func (p *Point) SetBytes(b []byte) (*Point, error) {
+	if _, err := p.p.SetBytes(b); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Bytes returns the uncompressed or infinity encoding of p, as specified in
+// SEC 1, Version 2.0, Section 2.3.3. Note that the encoding of the point at
+// infinity is shorter than all other encodings.
+func (p *Point) Bytes() []byte {
+	return p.p.Bytes()
+}
+
+// BytesCompressed returns the compressed or infinity encoding of p, as
+// specified in SEC 1, Version 2.0, Section 2.3.3. Note that the encoding of the
+// point at infinity is shorter than all other encodings.
+func (p *Point) BytesCompressed() []byte {
+	return p.p.BytesCompressed()
+}
+
+// BytesX returns the encoding of the x-coordinate of p, as specified in SEC 1,
+// Version 2.0, Section 2.3.5, or an error if p is the point at infinity.
+func (p *Point) BytesX() ([]byte, error) {
+	return p.p.BytesX()
+}
+
+// Add sets q = p1 + p2, and returns q. The points may overlap.
+func (q *Point) Add(p1, p2 *Point) *Point {
+	q.p.Add(&p1.p, &p2.p)
+	return q
+}
+
+// Double sets q = p + p, and returns q. The points may overlap.
+func (q *Point) Double(p *Point) *Point {
+	q.p.Double(&p.p)
+	return q
+}
+
+// Negate sets q = -p, and returns q. The points may overlap.
+func (q *Point) Negate(p *Point) *Point {
+	q.p.Set(&p.p).Negate(1)
+	return q
+}
+
+// Select sets q to p1 if cond == 1, and to p2 if cond == 0.
+func (q *Point) Select(p1, p2 *Point, cond int) *Point {
+	q.p.Select(&p1.p, &p2.p, cond)
+	return q
+}
+
+// ScalarMult sets p = scalar * q, and returns p. The scalar is a 32-byte big
+// endian value, values not lower than the order are reduced.
+func (p *Point) ScalarMult(q *Point, scalar []byte) (*Point, error) {
+	if _, err := p.p.ScalarMult(&q.p, scalar); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ScalarBaseMult sets p = scalar * generator, where scalar is a 32-byte big
+// endian value, and returns p.
+func (p *Point) ScalarBaseMult(scalar []byte) (*Point, error) {
+	if _, err := p.p.ScalarBaseMult(scalar); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// CombinedMult sets p = baseScalar * generator + scalar * q, and returns p.
+// Both scalars are 32-byte big endian values. Unlike the CombinedMult of the
+// [elliptic.Curve] returned by [P256], it is constant time.
+func (p *Point) CombinedMult(q *Point, baseScalar, scalar []byte) (*Point, error) {
+	t := sm2ec.NewSM2P256Point()
+	if _, err := t.ScalarMult(&q.p, scalar); err != nil {
+		return nil, err
+	}
+	if _, err := p.p.ScalarBaseMult(baseScalar); err != nil {
+		return nil, err
+	}
+	p.p.Add(&p.p, t)
+	return p, nil
+}
+
+// Equal returns 1 if p and q represent the same point, and 0 otherwise.
+func (p *Point) Equal(q *Point) int {
+	return subtle.ConstantTimeCompare(p.p.Bytes(), q.p.Bytes())
+}
@@ -0,0 +1,115 @@
+package sm2ec
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+)
+
+func randomScalarBytes(t *testing.T) []byte {
+	b := make([]byte, 64)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewScalar().SetUniformBytes(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s.Bytes()
+}
+
+func TestPointArithmetic(t *testing.T) {
+	curve := P256()
+	for i := 0; i < 10; i++ {
+		k1, k2 := randomScalarBytes(t), randomScalarBytes(t)
+		p1, err := NewPoint().ScalarBaseMult(k1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		x1, y1 := curve.ScalarBaseMult(k1)
+		if !bytes.Equal(p1.Bytes(), marshal(x1, y1)) {
+			t.Fatal("ScalarBaseMult mismatch")
+		}
+		p2, err := NewPoint().ScalarMult(p1, k2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		x2, y2 := curve.ScalarMult(x1, y1, k2)
+		if !bytes.Equal(p2.Bytes(), marshal(x2, y2)) {
+			t.Fatal("ScalarMult mismatch")
+		}
+		sum := NewPoint().Add(p1, p2)
+		x3, y3 := curve.Add(x1, y1, x2, y2)
+		if !bytes.Equal(sum.Bytes(), marshal(x3, y3)) {
+			t.Fatal("Add mismatch")
+		}
+		if NewPoint().Double(p1).Equal(NewPoint().Add(p1, p1)) != 1 {
+			t.Fatal("Double mismatch")
+		}
+		combined, err := NewPoint().CombinedMult(p1, k1, k2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		x4, y4 := sm2p256.CombinedMult(x1, y1, k1, k2)
+		if !bytes.Equal(combined.Bytes(), marshal(x4, y4)) {
+			t.Fatal("CombinedMult mismatch")
+		}
+		if NewPoint().Add(p1, NewPoint().Negate(p1)).Equal(NewPoint()) != 1 {
+			t.Fatal("p + (-p) is not the identity")
+		}
+		if p1.Equal(p2) != 0 {
+			t.Fatal("different points are equal")
+		}
+	}
+}
+
+func marshal(x, y *big.Int) []byte {
+	out := make([]byte, 65)
+	out[0] = 4
+	x.FillBytes(out[1:33])
+	y.FillBytes(out[33:])
+	return out
+}
+
+func TestPointEncoding(t *testing.T) {
+	p, err := NewPoint().ScalarBaseMult(randomScalarBytes(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range [][]byte{p.Bytes(), p.BytesCompressed()} {
+		q, err := NewPoint().SetBytes(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q.Equal(p) != 1 {
+			t.Fatal("round trip failed")
+		}
+	}
+	x, err := p.BytesX()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(x, p.Bytes()[1:33]) {
+		t.Fatal("BytesX mismatch")
+	}
+	infinity := NewPoint()
+	if !bytes.Equal(infinity.Bytes(), []byte{0}) || !bytes.Equal(infinity.BytesCompressed(), []byte{0}) {
+		t.Fatal("invalid infinity encoding")
+	}
+	if _, err := infinity.BytesX(); err == nil {
+		t.Fatal("expected error for BytesX of infinity")
+	}
+	bad := p.Bytes()
+	bad[64] ^= 1
+	if _, err := NewPoint().SetBytes(bad); err == nil {
+		t.Fatal("expected error for invalid point")
+	}
+	if _, err := NewPoint().ScalarBaseMult(make([]byte, 31)); err == nil {
+		t.Fatal("expected error for short scalar")
+	}
+	g := NewPoint().SetGenerator()
+	if NewPoint().Select(g, infinity, 1).Equal(g) != 1 || NewPoint().Select(g, infinity, 0).Equal(infinity) != 1 {
+		t.Fatal("Select mismatch")
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package sm2ec
+
+import (
+	"errors"
+
+	"github.com/emmansun/gmsm/internal/bigmod"
+	"github.com/emmansun/gmsm/internal/sm2ec/fiat"
+)
+
+// ScalarSize is the size in bytes of the encoding of a Scalar.
+const ScalarSize = 32
+
+var orderModulus, _ = bigmod.NewModulus([]byte{
+	0xff, 0xff, 0xff, 0xfe, 0xff, 0xff, 0xff, 0xff,
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+	0x72, 0x03, 0xdf, 0x6b, 0x21, 0xc6, 0x05, 0x2b,
+	0x53, 0xbb, 0xf4, 0x09, 0x39, 0xd5, 0x41, 0x23,
+})
+
+// Scalar is an integer modulo the order N of the SM2 curve. All the operations
+// are constant time.
+//
+// The zero value is a valid zero element.
+type Scalar struct {
+	s fiat.SM2P256OrderElement
+}
+
+// NewScalar returns a new zero Scalar.
+func NewScalar() *Scalar {
+	return &Scalar{}
+}
+
+// Set sets s = x, and returns s.
+func (s *Scalar) Set(x *Scalar) *Scalar {
+	s.s.Set(&x.s)
+	return s
+}
+
+// SetOne sets s = 1, and returns s.
+func (s *Scalar) SetOne() *Scalar {
+	s.s.One()
+	return s
+}
+
+// SetCanonicalBytes sets s = x, where x is a 32-byte big-endian encoding of s,
+// and returns s. If x is not a canonical encoding of s, SetCanonicalBytes
+// returns nil and an error, and the receiver is unchanged.
+func (s *Scalar) SetCanonicalBytes(x []byte) (*Scalar, error) {
+	if len(x) != ScalarSize {
+		return nil, errors.New("sm2ec: invalid scalar length")
+	}
+	if _, err := bigmod.NewNat().SetBytes(x, orderModulus); err != nil {
+		return nil, errors.New("sm2ec: invalid scalar encoding")
+	}
+	if _, err := s.s.SetBytes(x); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SetUniformBytes sets s = x mod N, where x is a big-endian encoding of at
+// least 48 uniformly random bytes, so that the bias is negligible, and returns s.
+func (s *Scalar) SetUniformBytes(x []byte) (*Scalar, error) {
+	if len(x) < 48 {
+		return nil, errors.New("sm2ec: invalid uniform bytes length")
+	}
+	k := bigmod.NewNat().SetReducedBytes(x, orderModulus)
+	if _, err := s.s.SetBytes(k.Bytes(orderModulus)); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Bytes returns the canonical 32-byte big-endian encoding of s.
+func (s *Scalar) Bytes() []byte {
+	return s.s.Bytes()
+}
+
+// Add sets s = x + y mod N, and returns s.
+func (s *Scalar) Add(x, y *Scalar) *Scalar {
+	s.s.Add(&x.s, &y.s)
+	return s
+}
+
+// Subtract sets s = x - y mod N, and returns s.
+func (s *Scalar) Subtract(x, y *Scalar) *Scalar {
+	s.s.Sub(&x.s, &y.s)
+	return s
+}
+
+// Negate sets s = -x mod N, and returns s.
+func (s *Scalar) Negate(x *Scalar) *Scalar {
+	s.s.Sub(new(fiat.SM2P256OrderElement), &x.s)
+	return s
+}
+
+// Multiply sets s = x * y mod N, and returns s.
+func (s *Scalar) Multiply(x, y *Scalar) *Scalar {
+	s.s.Mul(&x.s, &y.s)
+	return s
+}
+
+// Square sets s = x * x mod N, and returns s.
+func (s *Scalar) Square(x *Scalar) *Scalar {
+	s.s.Square(&x.s)
+	return s
+}
+
+// Invert sets s = 1/x mod N, and returns s. If x is zero, s is set to zero.
+// The inversion is constant time, by exponentiation with N - 2.
+func (s *Scalar) Invert(x *Scalar) *Scalar {
+	s.s.Invert(&x.s)
+	return s
+}
+
+// Select sets s to a if cond == 1, and to b if cond == 0.
+func (s *Scalar) Select(a, b *Scalar, cond int) *Scalar {
+	s.s.Select(&a.s, &b.s, cond)
+	return s
+}
+
+// Equal returns 1 if s and t are equal, and 0 otherwise.
+func (s *Scalar) Equal(t *Scalar) int {
+	return s.s.Equal(&t.s)
+}
+
+// IsZero returns 1 if s is zero, and 0 otherwise.
+func (s *Scalar) IsZero() int {
+	return s.s.IsZero()
+}
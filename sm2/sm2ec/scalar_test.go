@@ -0,0 +1,84 @@
+package sm2ec
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+)
+
+func TestScalarArithmetic(t *testing.T) {
+	n := P256().Params().N
+	toBig := func(s *Scalar) *big.Int { return new(big.Int).SetBytes(s.Bytes()) }
+	for i := 0; i < 10; i++ {
+		x, _ := NewScalar().SetCanonicalBytes(randomScalarBytes(t))
+		y, _ := NewScalar().SetCanonicalBytes(randomScalarBytes(t))
+		bx, by := toBig(x), toBig(y)
+
+		want := new(big.Int).Add(bx, by)
+		if toBig(NewScalar().Add(x, y)).Cmp(want.Mod(want, n)) != 0 {
+			t.Fatal("Add mismatch")
+		}
+		want.Sub(bx, by)
+		if toBig(NewScalar().Subtract(x, y)).Cmp(want.Mod(want, n)) != 0 {
+			t.Fatal("Subtract mismatch")
+		}
+		want.Neg(bx)
+		if toBig(NewScalar().Negate(x)).Cmp(want.Mod(want, n)) != 0 {
+			t.Fatal("Negate mismatch")
+		}
+		want.Mul(bx, by)
+		if toBig(NewScalar().Multiply(x, y)).Cmp(want.Mod(want, n)) != 0 {
+			t.Fatal("Multiply mismatch")
+		}
+		want.Mul(bx, bx)
+		if toBig(NewScalar().Square(x)).Cmp(want.Mod(want, n)) != 0 {
+			t.Fatal("Square mismatch")
+		}
+		if toBig(NewScalar().Invert(x)).Cmp(new(big.Int).ModInverse(bx, n)) != 0 {
+			t.Fatal("Invert mismatch")
+		}
+		if NewScalar().Multiply(x, NewScalar().Invert(x)).Equal(NewScalar().SetOne()) != 1 {
+			t.Fatal("x * 1/x != 1")
+		}
+	}
+	if NewScalar().Invert(NewScalar()).IsZero() != 1 {
+		t.Fatal("the inverse of zero should be zero")
+	}
+}
+
+func TestScalarEncoding(t *testing.T) {
+	n := P256().Params().N
+	if _, err := NewScalar().SetCanonicalBytes(n.Bytes()); err == nil {
+		t.Fatal("expected error for N")
+	}
+	nMinus1 := new(big.Int).Sub(n, big.NewInt(1)).Bytes()
+	s, err := NewScalar().SetCanonicalBytes(nMinus1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(s.Bytes(), nMinus1) {
+		t.Fatal("round trip failed")
+	}
+	if _, err := NewScalar().SetCanonicalBytes(nMinus1[1:]); err == nil {
+		t.Fatal("expected error for short encoding")
+	}
+	wide := make([]byte, 48)
+	for i := range wide {
+		wide[i] = 0xff
+	}
+	s, err = NewScalar().SetUniformBytes(wide)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := new(big.Int).SetBytes(wide)
+	if new(big.Int).SetBytes(s.Bytes()).Cmp(want.Mod(want, n)) != 0 {
+		t.Fatal("SetUniformBytes mismatch")
+	}
+	if _, err := NewScalar().SetUniformBytes(wide[:32]); err == nil {
+		t.Fatal("expected error for short uniform bytes")
+	}
+	one := NewScalar().SetOne()
+	if NewScalar().Select(one, s, 1).Equal(one) != 1 || NewScalar().Select(one, s, 0).Equal(s) != 1 {
+		t.Fatal("Select mismatch")
+	}
+}
@@ -1,4 +1,8 @@
 // Package sm2ec defines/implements SM2 elliptic curve structure.
+//
+// [P256] returns the SM2 curve as a math/big based [elliptic.Curve]. New code
+// building protocols on the SM2 group should use the constant-time [Point] and
+// [Scalar] types instead.
 package sm2ec
 
 import (
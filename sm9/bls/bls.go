@@ -0,0 +1,260 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package bls implements BLS signatures on the SM9 BN256 curve with SM3, in
+// the minimal-signature-size variant (signatures in G1, public keys in G2)
+// of the proof-of-possession scheme of draft-irtf-cfrg-bls-signature.
+//
+// The messages are hashed to G1 with [bn256.HashToG1], so the signatures do
+// not interoperate with the BLS12-381 ciphersuites of the draft.
+//
+// Aggregating public keys or signatures over the same message is only safe if
+// the possession of every public key has been checked with [VerifyPossession],
+// otherwise rogue-key attacks are possible.
+package bls
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"errors"
+	"io"
+
+	"github.com/emmansun/gmsm/internal/bigmod"
+	"github.com/emmansun/gmsm/sm9/bn256"
+)
+
+const (
+	// PrivateKeySize is the size of a private key.
+	PrivateKeySize = bn256.ScalarSize
+	// PublicKeySize is the size of a compressed G2 public key.
+	PublicKeySize = 65
+	// SignatureSize is the size of a compressed G1 signature.
+	SignatureSize = 33
+)
+
+// The tags follow the naming of the BLS signature draft, but with SM3_TAI for
+// the try-and-increment hash of [bn256.HashToG1], which is not an RFC 9380
+// suite with expand_message_xmd.
+var (
+	// signDST is the domain separation tag of the signatures.
+	signDST = []byte("BLS_SIG_SM9G1_SM3_TAI_POP_")
+	// popDST is the domain separation tag of the proofs of possession.
+	popDST = []byte("BLS_POP_SM9G1_SM3_TAI_POP_")
+)
+
+var orderNat, _ = bigmod.NewModulus(bn256.Order())
+
+var errInvalidPublicKey = errors.New("sm9/bls: invalid public key")
+
+// PrivateKey is a BLS private key, a scalar in [1, N-1].
+type PrivateKey struct {
+	k   []byte
+	pub *PublicKey
+}
+
+// PublicKey is a BLS public key, sk*P2.
+type PublicKey struct {
+	p *bn256.G2
+}
+
+// GenerateKey generates a new private key.
+func GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	b := make([]byte, PrivateKeySize)
+	k := bigmod.NewNat()
+	for {
+		if _, err := io.ReadFull(rand, b); err != nil {
+			return nil, err
+		}
+		if _, err := k.SetBytes(b, orderNat); err == nil && k.IsZero() == 0 {
+			return newPrivateKey(b)
+		}
+	}
+}
+
+// NewPrivateKey checks that b is a valid private key and returns it.
+func NewPrivateKey(b []byte) (*PrivateKey, error) {
+	if len(b) != PrivateKeySize {
+		return nil, errors.New("sm9/bls: invalid private key size")
+	}
+	k, err := bigmod.NewNat().SetBytes(b, orderNat)
+	if err != nil || k.IsZero() == 1 {
+		return nil, errors.New("sm9/bls: invalid private key")
+	}
+	return newPrivateKey(b)
+}
+
+func newPrivateKey(b []byte) (*PrivateKey, error) {
+	p, err := bn256.NewG2().ScalarBaseMult(b)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{k: append([]byte(nil), b...), pub: &PublicKey{p}}, nil
+}
+
+// Bytes returns the private key encoding.
+func (priv *PrivateKey) Bytes() []byte {
+	return append([]byte(nil), priv.k...)
+}
+
+// PublicKey returns the public key of priv.
+func (priv *PrivateKey) PublicKey() *PublicKey {
+	return priv.pub
+}
+
+// Public returns the public key of priv, it implements [crypto.Signer] together
+// with [PrivateKey.Sign].
+func (priv *PrivateKey) Public() crypto.PublicKey {
+	return priv.pub
+}
+
+// Equal reports whether priv and x have the same value.
+func (priv *PrivateKey) Equal(x crypto.PrivateKey) bool {
+	xx, ok := x.(*PrivateKey)
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare(priv.k, xx.k) == 1
+}
+
+// Sign signs msg, the message is hashed with SM3 as part of the hash to curve,
+// so it must not be hashed beforehand and opts must be crypto.Hash(0). rand is
+// not used, BLS signatures are deterministic.
+func (priv *PrivateKey) Sign(rand io.Reader, msg []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts != nil && opts.HashFunc() != 0 {
+		return nil, errors.New("sm9/bls: cannot sign hashed message")
+	}
+	return priv.sign(msg, signDST)
+}
+
+// ProvePossession returns the proof of possession of priv, a signature of the
+// public key itself with a dedicated domain separation tag.
+func (priv *PrivateKey) ProvePossession() ([]byte, error) {
+	return priv.sign(priv.pub.Bytes(), popDST)
+}
+
+func (priv *PrivateKey) sign(msg, dst []byte) ([]byte, error) {
+	s, err := bn256.NewG1().ScalarMult(bn256.HashToG1(msg, dst), priv.k)
+	if err != nil {
+		return nil, err
+	}
+	return s.BytesCompressed(), nil
+}
+
+// NewPublicKey parses a compressed or uncompressed public key. The identity
+// and the points out of G2 are rejected.
+func NewPublicKey(b []byte) (*PublicKey, error) {
+	p, err := bn256.NewG2().SetBytes(b)
+	if err != nil || p.IsIdentity() {
+		return nil, errInvalidPublicKey
+	}
+	return &PublicKey{p}, nil
+}
+
+// Bytes returns the compressed encoding of pub.
+func (pub *PublicKey) Bytes() []byte {
+	return pub.p.BytesCompressed()
+}
+
+// Equal reports whether pub and x have the same value.
+func (pub *PublicKey) Equal(x crypto.PublicKey) bool {
+	xx, ok := x.(*PublicKey)
+	if !ok {
+		return false
+	}
+	return pub.p.Equal(xx.p) == 1
+}
+
+func parseSignature(sig []byte) (*bn256.G1, error) {
+	return bn256.NewG1().SetBytes(sig)
+}
+
+// Verify reports whether sig is a valid signature of msg by pub.
+func Verify(pub *PublicKey, msg, sig []byte) bool {
+	return verify(pub, msg, sig, signDST)
+}
+
+// VerifyPossession reports whether proof is a valid proof of possession of
+// pub, see [PrivateKey.ProvePossession].
+func VerifyPossession(pub *PublicKey, proof []byte) bool {
+	return verify(pub, pub.Bytes(), proof, popDST)
+}
+
+func verify(pub *PublicKey, msg, sig, dst []byte) bool {
+	s, err := parseSignature(sig)
+	if err != nil {
+		return false
+	}
+	return pairingCheck([]*bn256.G1{bn256.HashToG1(msg, dst)}, []*bn256.G2{pub.p}, s)
+}
+
+// pairingCheck reports whether e(s, P2) = ∏ e(hs[i], ps[i]).
+func pairingCheck(hs []*bn256.G1, ps []*bn256.G2, s *bn256.G1) bool {
+	hs = append(hs, bn256.NewG1().Neg(s))
+	ps = append(ps, bn256.NewG2Generator())
+	e, err := bn256.MultiPair(hs, ps)
+	return err == nil && e.IsOne()
+}
+
+// Aggregate aggregates the signatures into a single signature.
+func Aggregate(sigs [][]byte) ([]byte, error) {
+	if len(sigs) == 0 {
+		return nil, errors.New("sm9/bls: no signatures to aggregate")
+	}
+	acc := bn256.NewG1()
+	for _, sig := range sigs {
+		s, err := parseSignature(sig)
+		if err != nil {
+			return nil, err
+		}
+		acc.Add(acc, s)
+	}
+	return acc.BytesCompressed(), nil
+}
+
+// AggregatePublicKeys aggregates the public keys into a single public key,
+// which verifies the aggregated signature of the same message.
+func AggregatePublicKeys(pubs []*PublicKey) (*PublicKey, error) {
+	if len(pubs) == 0 {
+		return nil, errors.New("sm9/bls: no public keys to aggregate")
+	}
+	acc := bn256.NewG2()
+	for _, pub := range pubs {
+		acc.Add(acc, pub.p)
+	}
+	if acc.IsIdentity() {
+		return nil, errInvalidPublicKey
+	}
+	return &PublicKey{acc}, nil
+}
+
+// AggregateVerify reports whether sig is the aggregate of the signatures of
+// msgs[i] by pubs[i]. The messages may repeat, as the possession of every
+// public key is assumed to be checked.
+func AggregateVerify(pubs []*PublicKey, msgs [][]byte, sig []byte) bool {
+	if len(pubs) == 0 || len(pubs) != len(msgs) {
+		return false
+	}
+	s, err := parseSignature(sig)
+	if err != nil {
+		return false
+	}
+	hs := make([]*bn256.G1, len(msgs))
+	ps := make([]*bn256.G2, len(pubs))
+	for i := range msgs {
+		hs[i] = bn256.HashToG1(msgs[i], signDST)
+		ps[i] = pubs[i].p
+	}
+	return pairingCheck(hs, ps, s)
+}
+
+// FastAggregateVerify reports whether sig is the aggregate of the signatures
+// of the same msg by pubs, it costs two pairings regardless of the number of
+// signers. The possession of every public key must have been checked.
+func FastAggregateVerify(pubs []*PublicKey, msg, sig []byte) bool {
+	pub, err := AggregatePublicKeys(pubs)
+	if err != nil {
+		return false
+	}
+	return Verify(pub, msg, sig)
+}
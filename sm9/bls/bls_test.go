@@ -0,0 +1,151 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package bls_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/emmansun/gmsm/sm9/bls"
+)
+
+func generateKeys(t *testing.T, n int) ([]*bls.PrivateKey, []*bls.PublicKey) {
+	t.Helper()
+	privs := make([]*bls.PrivateKey, n)
+	pubs := make([]*bls.PublicKey, n)
+	for i := range privs {
+		priv, err := bls.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		privs[i], pubs[i] = priv, priv.PublicKey()
+	}
+	return privs, pubs
+}
+
+func TestSignVerify(t *testing.T) {
+	privs, pubs := generateKeys(t, 2)
+	msg := []byte("consortium block 1")
+	sig, err := privs[0].Sign(nil, msg, crypto.Hash(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sig) != bls.SignatureSize {
+		t.Fatalf("unexpected signature size %d", len(sig))
+	}
+	if !bls.Verify(pubs[0], msg, sig) {
+		t.Fatal("signature verification failed")
+	}
+	if bls.Verify(pubs[1], msg, sig) {
+		t.Fatal("signature verified with another key")
+	}
+	if bls.Verify(pubs[0], []byte("consortium block 2"), sig) {
+		t.Fatal("signature verified for another message")
+	}
+	sig2, _ := privs[0].Sign(nil, msg, nil)
+	if !bytes.Equal(sig, sig2) {
+		t.Fatal("signature is not deterministic")
+	}
+	if _, err := privs[0].Sign(nil, msg, crypto.SHA256); err == nil {
+		t.Fatal("expected error for a hashed message")
+	}
+
+	// key round trips
+	priv, err := bls.NewPrivateKey(privs[0].Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !priv.Equal(privs[0]) || !priv.PublicKey().Equal(pubs[0]) {
+		t.Fatal("private key does not round trip")
+	}
+	pub, err := bls.NewPublicKey(pubs[0].Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pub.Equal(pubs[0]) {
+		t.Fatal("public key does not round trip")
+	}
+	if _, err := bls.NewPublicKey([]byte{0}); err == nil {
+		t.Fatal("expected error for the identity public key")
+	}
+	if _, err := bls.NewPrivateKey(make([]byte, bls.PrivateKeySize)); err == nil {
+		t.Fatal("expected error for the zero private key")
+	}
+}
+
+func TestProofOfPossession(t *testing.T) {
+	privs, pubs := generateKeys(t, 2)
+	proof, err := privs[0].ProvePossession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bls.VerifyPossession(pubs[0], proof) {
+		t.Fatal("proof of possession verification failed")
+	}
+	if bls.VerifyPossession(pubs[1], proof) {
+		t.Fatal("proof of possession verified with another key")
+	}
+	// a proof is not a signature of the public key
+	if bls.Verify(pubs[0], pubs[0].Bytes(), proof) {
+		t.Fatal("proof of possession verified as a signature")
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	const n = 4
+	privs, pubs := generateKeys(t, n)
+
+	msg := []byte("same message")
+	var sigs [][]byte
+	for _, priv := range privs {
+		sig, err := priv.Sign(nil, msg, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs = append(sigs, sig)
+	}
+	agg, err := bls.Aggregate(sigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bls.FastAggregateVerify(pubs, msg, agg) {
+		t.Fatal("fast aggregate verification failed")
+	}
+	if bls.FastAggregateVerify(pubs[1:], msg, agg) {
+		t.Fatal("fast aggregate verification succeeded with missing signer")
+	}
+
+	var msgs [][]byte
+	sigs = sigs[:0]
+	for i, priv := range privs {
+		m := []byte(fmt.Sprintf("message %d", i))
+		sig, err := priv.Sign(nil, m, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msgs = append(msgs, m)
+		sigs = append(sigs, sig)
+	}
+	agg, err = bls.Aggregate(sigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bls.AggregateVerify(pubs, msgs, agg) {
+		t.Fatal("aggregate verification failed")
+	}
+	msgs[0], msgs[1] = msgs[1], msgs[0]
+	if bls.AggregateVerify(pubs, msgs, agg) {
+		t.Fatal("aggregate verification succeeded with swapped messages")
+	}
+	if bls.AggregateVerify(pubs, msgs[1:], agg) {
+		t.Fatal("aggregate verification succeeded with mismatched inputs")
+	}
+	if _, err := bls.Aggregate([][]byte{sigs[0], {0x02}}); err == nil {
+		t.Fatal("expected error for an invalid signature")
+	}
+}
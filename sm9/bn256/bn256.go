@@ -0,0 +1,79 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package bn256 exposes the pairing groups of the SM9 BN curve, so that other
+// pairing based schemes can be built on the SM9 curve.
+//
+// G1 is the group of points of y² = x³ + 5 over Fp, G2 is the subgroup of order
+// N of the sextic twist over Fp², and GT is the subgroup of order N of Fp¹². The
+// group operations and the scalar multiplications are constant-time, hashing
+// to the groups and the subgroup checks of decoded points are not.
+//
+// Points are encoded as in SEC 1: 0x04 followed by the coordinates, 0x02 or
+// 0x03 followed by the x coordinate, and the single byte 0x00 for the identity.
+// The coordinates over Fp² are encoded as a || b for a*u + b.
+package bn256
+
+import (
+	"errors"
+
+	bn256internal "github.com/emmansun/gmsm/internal/sm9/bn256"
+)
+
+// ScalarSize is the size of the big-endian scalars accepted by the scalar
+// multiplications.
+const ScalarSize = 32
+
+// Order returns the order N of G1, G2 and GT as a big-endian byte slice.
+func Order() []byte {
+	return append([]byte(nil), bn256internal.OrderBytes...)
+}
+
+var errScalarSize = errors.New("sm9/bn256: invalid scalar length")
+
+// Pair computes the R-ate pairing e(p, q).
+func Pair(p *G1, q *G2) *GT {
+	e := &GT{}
+	e.e.Set(bn256internal.Pair(&p.p, &q.p))
+	return e
+}
+
+// MultiPair computes the product of e(ps[i], qs[i]), it is faster than
+// multiplying the pairings because the final exponentiation is done once.
+func MultiPair(ps []*G1, qs []*G2) (*GT, error) {
+	if len(ps) != len(qs) {
+		return nil, errors.New("sm9/bn256: mismatched number of G1 and G2 points")
+	}
+	acc := new(bn256internal.GT).SetOne()
+	for i := range ps {
+		if ps[i].p.IsInfinity() || qs[i].p.IsInfinity() {
+			continue
+		}
+		acc.Add(acc, bn256internal.Miller(&ps[i].p, &qs[i].p))
+	}
+	e := &GT{}
+	e.e.Set(acc.Finalize())
+	return e, nil
+}
+
+// HashToG1 hashes msg with the domain separation tag dst to a point of G1.
+//
+// It uses try-and-increment with SM3, so the running time depends on msg and
+// dst, which must not be secret. It is not one of the hash-to-curve suites of
+// RFC 9380, which has no suite for the SM9 curve.
+func HashToG1(msg, dst []byte) *G1 {
+	p := &G1{}
+	p.p.Set(bn256internal.HashToG1(msg, dst))
+	return p
+}
+
+// HashToG2 hashes msg with the domain separation tag dst to a point of G2.
+//
+// It uses try-and-increment with SM3 followed by cofactor clearing, so the
+// running time depends on msg and dst, which must not be secret.
+func HashToG2(msg, dst []byte) *G2 {
+	q := &G2{}
+	q.p.Set(bn256internal.HashToG2(msg, dst))
+	return q
+}
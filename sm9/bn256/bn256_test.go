@@ -0,0 +1,184 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package bn256_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/emmansun/gmsm/sm9/bn256"
+)
+
+func randomScalar(t *testing.T) []byte {
+	t.Helper()
+	k, err := rand.Int(rand.Reader, new(big.Int).SetBytes(bn256.Order()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return k.FillBytes(make([]byte, bn256.ScalarSize))
+}
+
+func mulScalars(a, b []byte) []byte {
+	n := new(big.Int).SetBytes(bn256.Order())
+	k := new(big.Int).Mul(new(big.Int).SetBytes(a), new(big.Int).SetBytes(b))
+	return k.Mod(k, n).FillBytes(make([]byte, bn256.ScalarSize))
+}
+
+func TestBilinearity(t *testing.T) {
+	a, b := randomScalar(t), randomScalar(t)
+	p, err := bn256.NewG1().ScalarBaseMult(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := bn256.NewG2().ScalarBaseMult(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// e(aP1, bP2) = e(P1, P2)^ab
+	g := bn256.Pair(bn256.NewG1Generator(), bn256.NewG2Generator())
+	want, err := bn256.NewGT().Exp(g, mulScalars(a, b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bn256.Pair(p, q).Equal(want) != 1 {
+		t.Fatal("e(aP1, bP2) != e(P1, P2)^ab")
+	}
+
+	// e(aP1, bP2) * e(-abP1, P2) = 1
+	r, err := bn256.NewG1().ScalarBaseMult(mulScalars(a, b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Neg(r)
+	got, err := bn256.MultiPair([]*bn256.G1{p, r, bn256.NewG1()}, []*bn256.G2{q, bn256.NewG2Generator(), q})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.IsOne() {
+		t.Fatal("multi-pairing is not one")
+	}
+	if !bn256.Pair(bn256.NewG1(), q).IsOne() {
+		t.Fatal("pairing with the identity is not one")
+	}
+	if !bn256.NewGT().Mul(want, bn256.NewGT().Invert(want)).IsOne() {
+		t.Fatal("g * 1/g is not one")
+	}
+	if _, err := bn256.MultiPair([]*bn256.G1{p}, nil); err == nil {
+		t.Fatal("expected error for mismatched inputs")
+	}
+}
+
+func TestGroupOperations(t *testing.T) {
+	a := randomScalar(t)
+	p, _ := bn256.NewG1().ScalarBaseMult(a)
+	p2, _ := bn256.NewG1().ScalarMult(bn256.NewG1Generator(), a)
+	if p.Equal(p2) != 1 {
+		t.Fatal("G1 ScalarBaseMult and ScalarMult mismatch")
+	}
+	if sum := bn256.NewG1().Add(p, p2); sum.Equal(bn256.NewG1().Double(p)) != 1 {
+		t.Fatal("G1 p + p != 2p")
+	}
+	if sum := bn256.NewG1().Add(p, bn256.NewG1().Neg(p)); !sum.IsIdentity() {
+		t.Fatal("G1 p - p is not the identity")
+	}
+
+	q, _ := bn256.NewG2().ScalarBaseMult(a)
+	q2, _ := bn256.NewG2().ScalarMult(bn256.NewG2Generator(), a)
+	if q.Equal(q2) != 1 {
+		t.Fatal("G2 ScalarBaseMult and ScalarMult mismatch")
+	}
+	if sum := bn256.NewG2().Add(q, q2); sum.Equal(bn256.NewG2().Double(q)) != 1 {
+		t.Fatal("G2 q + q != 2q")
+	}
+	if sum := bn256.NewG2().Add(q, bn256.NewG2().Neg(q)); !sum.IsIdentity() {
+		t.Fatal("G2 q - q is not the identity")
+	}
+
+	if _, err := bn256.NewG1().ScalarBaseMult(a[1:]); err == nil {
+		t.Fatal("expected error for a short scalar")
+	}
+	if _, err := bn256.NewG2().ScalarMult(q, a[1:]); err == nil {
+		t.Fatal("expected error for a short scalar")
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	a := randomScalar(t)
+	p, _ := bn256.NewG1().ScalarBaseMult(a)
+	for _, b := range [][]byte{p.Bytes(), p.BytesCompressed()} {
+		got, err := bn256.NewG1().SetBytes(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Equal(p) != 1 {
+			t.Fatalf("G1 %x does not round trip", b)
+		}
+	}
+	q, _ := bn256.NewG2().ScalarBaseMult(a)
+	for _, b := range [][]byte{q.Bytes(), q.BytesCompressed()} {
+		got, err := bn256.NewG2().SetBytes(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Equal(q) != 1 {
+			t.Fatalf("G2 %x does not round trip", b)
+		}
+	}
+	if b := bn256.NewG1().Bytes(); !bytes.Equal(b, []byte{0}) {
+		t.Fatalf("unexpected G1 identity encoding %x", b)
+	}
+	if got, err := bn256.NewG2Generator().SetBytes([]byte{0}); err != nil || !got.IsIdentity() {
+		t.Fatal("G2 identity does not round trip")
+	}
+
+	g := bn256.Pair(p, q)
+	got, err := bn256.NewGT().SetBytes(g.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Equal(g) != 1 {
+		t.Fatal("GT does not round trip")
+	}
+
+	bad := p.Bytes()
+	bad[len(bad)-1] ^= 1
+	if _, err := bn256.NewG1().SetBytes(bad); err == nil {
+		t.Fatal("expected error for a point not on the curve")
+	}
+	if _, err := bn256.NewG1().SetBytes(make([]byte, 65)); err == nil {
+		t.Fatal("expected error for the zero coordinates")
+	}
+	bad = q.Bytes()
+	bad[len(bad)-1] ^= 1
+	if _, err := bn256.NewG2().SetBytes(bad); err == nil {
+		t.Fatal("expected error for a point not on the twist")
+	}
+	bad = g.Bytes()
+	bad[len(bad)-1] ^= 1
+	if _, err := bn256.NewGT().SetBytes(bad); err == nil {
+		t.Fatal("expected error for an element out of GT")
+	}
+}
+
+func TestHashToCurve(t *testing.T) {
+	dst := []byte("SM9-BN256-TEST")
+	p := bn256.HashToG1([]byte("abc"), dst)
+	q := bn256.HashToG2([]byte("abc"), dst)
+	if p.IsIdentity() || q.IsIdentity() {
+		t.Fatal("hash to the identity")
+	}
+	if p.Equal(bn256.HashToG1([]byte("abc"), dst)) != 1 || q.Equal(bn256.HashToG2([]byte("abc"), dst)) != 1 {
+		t.Fatal("hash is not deterministic")
+	}
+	if p.Equal(bn256.HashToG1([]byte("abd"), dst)) == 1 || q.Equal(bn256.HashToG2([]byte("abc"), []byte("other"))) == 1 {
+		t.Fatal("different inputs hash to the same point")
+	}
+	// the hashed G2 point passes the subgroup check
+	if _, err := bn256.NewG2().SetBytes(q.BytesCompressed()); err != nil {
+		t.Fatal(err)
+	}
+}
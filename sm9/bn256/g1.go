@@ -0,0 +1,142 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package bn256
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	bn256internal "github.com/emmansun/gmsm/internal/sm9/bn256"
+)
+
+const g1CoordinateSize = 32
+
+// G1 is a point of G1. The zero value is NOT valid, use [NewG1] or
+// [NewG1Generator] to create one.
+type G1 struct {
+	p bn256internal.G1
+}
+
+// NewG1 returns a new G1 set to the identity.
+func NewG1() *G1 {
+	p := &G1{}
+	p.p.Unmarshal(make([]byte, 2*g1CoordinateSize))
+	return p
+}
+
+// NewG1Generator returns a new G1 set to the generator P1 of SM9.
+func NewG1Generator() *G1 {
+	p := &G1{}
+	p.p.Set(bn256internal.Gen1)
+	return p
+}
+
+// Set sets p = q and returns p.
+func (p *G1) Set(q *G1) *G1 {
+	p.p.Set(&q.p)
+	return p
+}
+
+// Add sets p = a + b and returns p.
+func (p *G1) Add(a, b *G1) *G1 {
+	p.p.Add(&a.p, &b.p)
+	return p
+}
+
+// Double sets p = a + a and returns p.
+func (p *G1) Double(a *G1) *G1 {
+	p.p.Double(&a.p)
+	return p
+}
+
+// Neg sets p = -a and returns p.
+func (p *G1) Neg(a *G1) *G1 {
+	p.p.Neg(&a.p)
+	return p
+}
+
+// ScalarMult sets p = scalar * q and returns p. scalar is a big-endian
+// [ScalarSize] bytes integer.
+func (p *G1) ScalarMult(q *G1, scalar []byte) (*G1, error) {
+	if len(scalar) != ScalarSize {
+		return nil, errScalarSize
+	}
+	if _, err := p.p.ScalarMult(&q.p, scalar); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ScalarBaseMult sets p = scalar * P1 and returns p. scalar is a big-endian
+// [ScalarSize] bytes integer.
+func (p *G1) ScalarBaseMult(scalar []byte) (*G1, error) {
+	if len(scalar) != ScalarSize {
+		return nil, errScalarSize
+	}
+	if _, err := p.p.ScalarBaseMult(scalar); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Equal returns 1 if p and q are equal, and 0 otherwise.
+func (p *G1) Equal(q *G1) int {
+	return subtle.ConstantTimeCompare(p.p.Marshal(), q.p.Marshal())
+}
+
+// IsIdentity reports whether p is the identity.
+func (p *G1) IsIdentity() bool {
+	return p.p.IsInfinity()
+}
+
+// Bytes returns the uncompressed encoding of p, 0x04 || x || y, or the single
+// byte 0x00 for the identity.
+func (p *G1) Bytes() []byte {
+	if p.p.IsInfinity() {
+		return []byte{0}
+	}
+	return p.p.MarshalUncompressed()
+}
+
+// BytesCompressed returns the compressed encoding of p, 0x02 or 0x03 || x,
+// or the single byte 0x00 for the identity.
+func (p *G1) BytesCompressed() []byte {
+	if p.p.IsInfinity() {
+		return []byte{0}
+	}
+	return p.p.MarshalCompressed()
+}
+
+// SetBytes sets p to the uncompressed, compressed or identity encoded point
+// b and returns p. If b is not a valid encoding of a point of G1, SetBytes
+// returns an error and p is unchanged.
+func (p *G1) SetBytes(b []byte) (*G1, error) {
+	q := &G1{}
+	var err error
+	switch {
+	case len(b) == 1 && b[0] == 0:
+		return p.Set(NewG1()), nil
+	case len(b) == 1+2*g1CoordinateSize && b[0] == 4:
+		_, err = q.p.Unmarshal(b[1:])
+		if err == nil && !q.p.IsInfinity() && subtle.ConstantTimeCompare(q.p.MarshalUncompressed(), b) != 1 {
+			err = errors.New("sm9/bn256: non-canonical G1 point encoding")
+		}
+	case len(b) == 1+g1CoordinateSize && (b[0] == 2 || b[0] == 3):
+		_, err = q.p.UnmarshalCompressed(b)
+		if err == nil && !q.p.IsInfinity() && subtle.ConstantTimeCompare(q.p.MarshalCompressed(), b) != 1 {
+			err = errors.New("sm9/bn256: non-canonical G1 point encoding")
+		}
+	default:
+		return nil, errors.New("sm9/bn256: invalid G1 point encoding")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if q.p.IsInfinity() {
+		// the identity only has the single byte encoding.
+		return nil, errors.New("sm9/bn256: invalid G1 point encoding")
+	}
+	return p.Set(q), nil
+}
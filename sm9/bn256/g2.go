@@ -0,0 +1,148 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package bn256
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	bn256internal "github.com/emmansun/gmsm/internal/sm9/bn256"
+)
+
+const g2CoordinateSize = 64
+
+// G2 is a point of G2. The zero value is NOT valid, use [NewG2] or
+// [NewG2Generator] to create one.
+type G2 struct {
+	p bn256internal.G2
+}
+
+// NewG2 returns a new G2 set to the identity.
+func NewG2() *G2 {
+	q := &G2{}
+	q.p.Unmarshal(make([]byte, 2*g2CoordinateSize))
+	return q
+}
+
+// NewG2Generator returns a new G2 set to the generator P2 of SM9.
+func NewG2Generator() *G2 {
+	q := &G2{}
+	q.p.Set(bn256internal.Gen2)
+	return q
+}
+
+// Set sets q = r and returns q.
+func (q *G2) Set(r *G2) *G2 {
+	q.p.Set(&r.p)
+	return q
+}
+
+// Add sets q = a + b and returns q.
+func (q *G2) Add(a, b *G2) *G2 {
+	q.p.Add(&a.p, &b.p)
+	return q
+}
+
+// Double sets q = a + a and returns q.
+func (q *G2) Double(a *G2) *G2 {
+	q.p.Add(&a.p, &a.p)
+	return q
+}
+
+// Neg sets q = -a and returns q.
+func (q *G2) Neg(a *G2) *G2 {
+	q.p.Neg(&a.p)
+	return q
+}
+
+// ScalarMult sets q = scalar * r and returns q. scalar is a big-endian
+// [ScalarSize] bytes integer.
+func (q *G2) ScalarMult(r *G2, scalar []byte) (*G2, error) {
+	if len(scalar) != ScalarSize {
+		return nil, errScalarSize
+	}
+	if _, err := q.p.ScalarMult(&r.p, scalar); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// ScalarBaseMult sets q = scalar * P2 and returns q. scalar is a big-endian
+// [ScalarSize] bytes integer.
+func (q *G2) ScalarBaseMult(scalar []byte) (*G2, error) {
+	if len(scalar) != ScalarSize {
+		return nil, errScalarSize
+	}
+	if _, err := q.p.ScalarBaseMult(scalar); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// Equal returns 1 if q and r are equal, and 0 otherwise.
+func (q *G2) Equal(r *G2) int {
+	return subtle.ConstantTimeCompare(q.p.Marshal(), r.p.Marshal())
+}
+
+// IsIdentity reports whether q is the identity.
+func (q *G2) IsIdentity() bool {
+	return q.p.IsInfinity()
+}
+
+// Bytes returns the uncompressed encoding of q, 0x04 || x || y, or the single
+// byte 0x00 for the identity.
+func (q *G2) Bytes() []byte {
+	if q.p.IsInfinity() {
+		return []byte{0}
+	}
+	return q.p.MarshalUncompressed()
+}
+
+// BytesCompressed returns the compressed encoding of q, 0x02 or 0x03 || x,
+// or the single byte 0x00 for the identity.
+func (q *G2) BytesCompressed() []byte {
+	if q.p.IsInfinity() {
+		return []byte{0}
+	}
+	return q.p.MarshalCompressed()
+}
+
+// SetBytes sets q to the uncompressed, compressed or identity encoded point
+// b and returns q. If b is not a valid encoding of a point of G2, SetBytes
+// returns an error and q is unchanged.
+//
+// The point is checked to be in the subgroup of order N, which costs a
+// variable-time scalar multiplication.
+func (q *G2) SetBytes(b []byte) (*G2, error) {
+	r := &G2{}
+	var err error
+	switch {
+	case len(b) == 1 && b[0] == 0:
+		return q.Set(NewG2()), nil
+	case len(b) == 1+2*g2CoordinateSize && b[0] == 4:
+		_, err = r.p.Unmarshal(b[1:])
+		if err == nil && !r.p.IsInfinity() && subtle.ConstantTimeCompare(r.p.MarshalUncompressed(), b) != 1 {
+			err = errors.New("sm9/bn256: non-canonical G2 point encoding")
+		}
+	case len(b) == 1+g2CoordinateSize && (b[0] == 2 || b[0] == 3):
+		_, err = r.p.UnmarshalCompressed(b)
+		if err == nil && !r.p.IsInfinity() && subtle.ConstantTimeCompare(r.p.MarshalCompressed(), b) != 1 {
+			err = errors.New("sm9/bn256: non-canonical G2 point encoding")
+		}
+	default:
+		return nil, errors.New("sm9/bn256: invalid G2 point encoding")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if r.p.IsInfinity() {
+		// the identity only has the single byte encoding.
+		return nil, errors.New("sm9/bn256: invalid G2 point encoding")
+	}
+	if !r.p.IsInSubgroup() {
+		return nil, errors.New("sm9/bn256: G2 point is not in the subgroup")
+	}
+	return q.Set(r), nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package bn256
+
+import (
+	"crypto/subtle"
+	"errors"
+
+	bn256internal "github.com/emmansun/gmsm/internal/sm9/bn256"
+)
+
+// GTSize is the size of the encoding of a GT element.
+const GTSize = 384
+
+// GT is an element of GT, the group is written multiplicatively. The zero
+// value is NOT valid, use [NewGT] or [Pair] to create one.
+type GT struct {
+	e bn256internal.GT
+}
+
+// NewGT returns a new GT set to the identity.
+func NewGT() *GT {
+	e := &GT{}
+	e.e.SetOne()
+	return e
+}
+
+// Set sets e = a and returns e.
+func (e *GT) Set(a *GT) *GT {
+	e.e.Set(&a.e)
+	return e
+}
+
+// Mul sets e = a * b and returns e.
+func (e *GT) Mul(a, b *GT) *GT {
+	e.e.Add(&a.e, &b.e)
+	return e
+}
+
+// Invert sets e = 1/a and returns e.
+func (e *GT) Invert(a *GT) *GT {
+	e.e.Invert(&a.e)
+	return e
+}
+
+// Exp sets e = a^scalar and returns e. scalar is a big-endian [ScalarSize]
+// bytes integer.
+func (e *GT) Exp(a *GT, scalar []byte) (*GT, error) {
+	if len(scalar) != ScalarSize {
+		return nil, errScalarSize
+	}
+	r, err := bn256internal.ScalarMultGT(&a.e, scalar)
+	if err != nil {
+		return nil, err
+	}
+	e.e.Set(r)
+	return e, nil
+}
+
+// Equal returns 1 if e and a are equal, and 0 otherwise.
+func (e *GT) Equal(a *GT) int {
+	return subtle.ConstantTimeCompare(e.e.Marshal(), a.e.Marshal())
+}
+
+// IsOne reports whether e is the identity.
+func (e *GT) IsOne() bool {
+	return e.e.IsOne()
+}
+
+// Bytes returns the [GTSize] bytes encoding of e.
+func (e *GT) Bytes() []byte {
+	return e.e.Marshal()
+}
+
+// SetBytes sets e to the encoded element b and returns e. If b is not a
+// valid encoding of an element of GT, SetBytes returns an error and e is
+// unchanged.
+//
+// The element is checked to be in the subgroup of order N, which costs a
+// variable-time exponentiation.
+func (e *GT) SetBytes(b []byte) (*GT, error) {
+	if len(b) != GTSize {
+		return nil, errors.New("sm9/bn256: invalid GT encoding")
+	}
+	r := &GT{}
+	if _, err := r.e.Unmarshal(b); err != nil {
+		return nil, err
+	}
+	if !r.e.IsInSubgroup() {
+		return nil, errors.New("sm9/bn256: GT element is not in the subgroup")
+	}
+	return e.Set(r), nil
+}
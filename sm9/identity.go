@@ -0,0 +1,270 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package sm9
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	identitySeparator  = '|'
+	identityTimeFormat = "20060102150405Z"
+)
+
+// Identity is a time-bound SM9 identity. SM9 has no certificate to revoke, the
+// validity period and the key usage are encoded into the UID instead, so a key
+// is "revoked" by not issuing it for the next epochs.
+//
+// The UID is the canonical encoding
+//
+//	ID || '|' || Usage || '|' || NotBefore || '|' || NotAfter
+//
+// where the times are UTC in the "YYYYMMDDhhmmssZ" format, for example
+// "alice@corp|sign|20261001000000Z|20270101000000Z". ID may contain '|', the
+// encoding is parsed from the right.
+type Identity struct {
+	ID        []byte    // base identity, e.g. an email address
+	Usage     string    // key usage tag, must not contain '|'
+	NotBefore time.Time // inclusive
+	NotAfter  time.Time // exclusive
+}
+
+// UID returns the canonical encoding of id, which is the UID used to generate
+// the user keys and to sign or encrypt.
+func (id *Identity) UID() ([]byte, error) {
+	if strings.IndexByte(id.Usage, identitySeparator) >= 0 {
+		return nil, errors.New("sm9: identity usage contains the separator")
+	}
+	if !id.NotBefore.Before(id.NotAfter) {
+		return nil, errors.New("sm9: identity validity period is empty")
+	}
+	uid := make([]byte, 0, len(id.ID)+len(id.Usage)+2*len(identityTimeFormat)+3)
+	uid = append(uid, id.ID...)
+	uid = append(uid, identitySeparator)
+	uid = append(uid, id.Usage...)
+	uid = append(uid, identitySeparator)
+	uid = id.NotBefore.UTC().AppendFormat(uid, identityTimeFormat)
+	uid = append(uid, identitySeparator)
+	uid = id.NotAfter.UTC().AppendFormat(uid, identityTimeFormat)
+	return uid, nil
+}
+
+// ValidAt reports whether t is in the validity period of id.
+func (id *Identity) ValidAt(t time.Time) bool {
+	return !t.Before(id.NotBefore) && t.Before(id.NotAfter)
+}
+
+// ParseIdentity parses the canonical encoding of a time-bound identity.
+func ParseIdentity(uid []byte) (*Identity, error) {
+	var fields [3][]byte
+	rest := uid
+	for i := len(fields) - 1; i >= 0; i-- {
+		n := bytes.LastIndexByte(rest, identitySeparator)
+		if n < 0 {
+			return nil, errors.New("sm9: invalid identity encoding")
+		}
+		fields[i], rest = rest[n+1:], rest[:n]
+	}
+	notBefore, err := time.Parse(identityTimeFormat, string(fields[1]))
+	if err != nil {
+		return nil, errors.New("sm9: invalid identity validity period")
+	}
+	notAfter, err := time.Parse(identityTimeFormat, string(fields[2]))
+	if err != nil {
+		return nil, errors.New("sm9: invalid identity validity period")
+	}
+	id := &Identity{
+		ID:        bytes.Clone(rest),
+		Usage:     string(fields[0]),
+		NotBefore: notBefore,
+		NotAfter:  notAfter,
+	}
+	// reject the encodings which do not round trip, e.g. "Z" replaced by an offset
+	if canonical, err := id.UID(); err != nil || !bytes.Equal(canonical, uid) {
+		return nil, errors.New("sm9: non-canonical identity encoding")
+	}
+	return id, nil
+}
+
+// EpochSchedule splits the time into consecutive epochs starting at Origin, a
+// user key is issued for each epoch. The epochs are Months calendar months long
+// if Months is positive, for example 3 for quarters, otherwise Length long.
+type EpochSchedule struct {
+	Origin time.Time
+	Length time.Duration
+	Months int
+}
+
+var (
+	errInvalidSchedule = errors.New("sm9: invalid epoch schedule")
+	errInvalidCount    = errors.New("sm9: invalid number of epochs")
+	errEpochRange      = errors.New("sm9: time out of the range of the epoch schedule")
+)
+
+// start returns the start of the n-th epoch. n * Length is computed exactly,
+// as it may not fit in a time.Duration.
+func (s *EpochSchedule) start(n int64) time.Time {
+	if s.Months > 0 {
+		return s.Origin.AddDate(0, int(n)*s.Months, 0)
+	}
+	d := new(big.Int).Mul(big.NewInt(n), big.NewInt(int64(s.Length)))
+	sec, nsec := new(big.Int).DivMod(d, big.NewInt(int64(time.Second)), new(big.Int))
+	return time.Unix(s.Origin.Unix()+sec.Int64(), int64(s.Origin.Nanosecond())+nsec.Int64()).In(s.Origin.Location())
+}
+
+// sinceOrigin returns t - Origin in nanoseconds. Unlike t.Sub, it doesn't
+// saturate for times about 292 years away from Origin.
+func (s *EpochSchedule) sinceOrigin(t time.Time) *big.Int {
+	d := new(big.Int).Sub(big.NewInt(t.Unix()), big.NewInt(s.Origin.Unix()))
+	d.Mul(d, big.NewInt(int64(time.Second)))
+	return d.Add(d, big.NewInt(int64(t.Nanosecond()-s.Origin.Nanosecond())))
+}
+
+// Epoch returns the index of the epoch containing t, the epochs before Origin
+// have negative indexes.
+func (s *EpochSchedule) Epoch(t time.Time) (int64, error) {
+	var n int64
+	switch {
+	case s.Months > 0:
+		y1, m1, _ := s.Origin.Date()
+		y2, m2, _ := t.In(s.Origin.Location()).Date()
+		months := int64(y2-y1)*12 + int64(m2-m1)
+		n = months / int64(s.Months)
+		if months%int64(s.Months) != 0 && months < 0 {
+			n--
+		}
+	case s.Length > 0:
+		// Euclidean division, which rounds toward -inf for a positive Length
+		q := new(big.Int).Div(s.sinceOrigin(t), big.NewInt(int64(s.Length)))
+		if !q.IsInt64() {
+			return 0, errEpochRange
+		}
+		n = q.Int64()
+	default:
+		return 0, errInvalidSchedule
+	}
+	// the day and the time of Origin may move t into the previous or next
+	// epoch, n is off by one at most
+	for t.Before(s.start(n)) {
+		n--
+	}
+	for !t.Before(s.start(n + 1)) {
+		n++
+	}
+	return n, nil
+}
+
+// Identity returns the time-bound identity of id for usage in the n-th epoch.
+func (s *EpochSchedule) Identity(id []byte, usage string, n int64) *Identity {
+	return &Identity{
+		ID:        bytes.Clone(id),
+		Usage:     usage,
+		NotBefore: s.start(n).UTC().Truncate(time.Second),
+		NotAfter:  s.start(n + 1).UTC().Truncate(time.Second),
+	}
+}
+
+// IdentityAt returns the time-bound identity of id for usage in the epoch
+// containing t.
+func (s *EpochSchedule) IdentityAt(id []byte, usage string, t time.Time) (*Identity, error) {
+	n, err := s.Epoch(t)
+	if err != nil {
+		return nil, err
+	}
+	return s.Identity(id, usage, n), nil
+}
+
+// UIDAt returns the UID of id for usage in the epoch containing t. A verifier
+// calls it with the signing time, an encrypter with the current time.
+func (s *EpochSchedule) UIDAt(id []byte, usage string, t time.Time) ([]byte, error) {
+	identity, err := s.IdentityAt(id, usage, t)
+	if err != nil {
+		return nil, err
+	}
+	return identity.UID()
+}
+
+// EpochSignKey is a signature private key issued for one epoch.
+type EpochSignKey struct {
+	Identity *Identity
+	Key      *SignPrivateKey
+}
+
+// EpochEncryptKey is an encryption private key issued for one epoch.
+type EpochEncryptKey struct {
+	Identity *Identity
+	Key      *EncryptPrivateKey
+}
+
+// GenerateEpochUserKeys issues the signature private keys of id for usage for
+// count epochs, starting with the epoch containing from. count must be
+// positive. To revoke the keys of
+// an identity, the KGC stops issuing them for the next epochs.
+func (master *SignMasterPrivateKey) GenerateEpochUserKeys(s *EpochSchedule, id []byte, usage string, hid byte, from time.Time, count int) ([]*EpochSignKey, error) {
+	if count <= 0 {
+		return nil, errInvalidCount
+	}
+	n, err := s.Epoch(from)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*EpochSignKey, count)
+	for i := range keys {
+		identity := s.Identity(id, usage, n+int64(i))
+		uid, err := identity.UID()
+		if err != nil {
+			return nil, err
+		}
+		key, err := master.GenerateUserKey(uid, hid)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = &EpochSignKey{Identity: identity, Key: key}
+	}
+	return keys, nil
+}
+
+// GenerateEpochUserKeys issues the encryption private keys of id for usage for
+// count epochs, starting with the epoch containing from. count must be
+// positive.
+func (master *EncryptMasterPrivateKey) GenerateEpochUserKeys(s *EpochSchedule, id []byte, usage string, hid byte, from time.Time, count int) ([]*EpochEncryptKey, error) {
+	if count <= 0 {
+		return nil, errInvalidCount
+	}
+	n, err := s.Epoch(from)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*EpochEncryptKey, count)
+	for i := range keys {
+		identity := s.Identity(id, usage, n+int64(i))
+		uid, err := identity.UID()
+		if err != nil {
+			return nil, err
+		}
+		key, err := master.GenerateUserKey(uid, hid)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = &EpochEncryptKey{Identity: identity, Key: key}
+	}
+	return keys, nil
+}
+
+// VerifyAt verifies the ASN.1 encoded signature of hash by id for usage,
+// signed at signingTime. The UID is derived from the epoch containing
+// signingTime, so the signatures of keys issued for other epochs are rejected.
+// The caller is responsible for checking that signingTime is trustworthy.
+func (pub *SignMasterPublicKey) VerifyAt(s *EpochSchedule, id []byte, usage string, hid byte, signingTime time.Time, hash, sig []byte) bool {
+	uid, err := s.UIDAt(id, usage, signingTime)
+	if err != nil {
+		return false
+	}
+	return pub.Verify(uid, hid, hash, sig)
+}
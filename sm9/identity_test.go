@@ -0,0 +1,194 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package sm9_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/emmansun/gmsm/sm3"
+	"github.com/emmansun/gmsm/sm9"
+)
+
+func TestIdentityEncoding(t *testing.T) {
+	id := &sm9.Identity{
+		ID:        []byte("alice|corp"),
+		Usage:     "sign",
+		NotBefore: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:  time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	uid, err := id.UID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "alice|corp|sign|20261001000000Z|20270101000000Z"; string(uid) != want {
+		t.Fatalf("got %s, want %s", uid, want)
+	}
+	parsed, err := sm9.ParseIdentity(uid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(parsed.ID, id.ID) || parsed.Usage != id.Usage ||
+		!parsed.NotBefore.Equal(id.NotBefore) || !parsed.NotAfter.Equal(id.NotAfter) {
+		t.Fatalf("got %+v, want %+v", parsed, id)
+	}
+	if !id.ValidAt(id.NotBefore) || id.ValidAt(id.NotAfter) {
+		t.Fatal("validity period should be half-open")
+	}
+
+	for _, bad := range []string{
+		"alice",
+		"alice|sign|20261001000000Z",
+		"alice|sign|20261001000000Z|2027010100000Z",
+		"alice|sign|20270101000000Z|20261001000000Z",
+		"alice|sign|20261001000000+0800|20270101000000Z",
+	} {
+		if _, err := sm9.ParseIdentity([]byte(bad)); err == nil {
+			t.Errorf("%s: expected error", bad)
+		}
+	}
+	id.Usage = "si|gn"
+	if _, err := id.UID(); err == nil {
+		t.Fatal("expected error for usage with separator")
+	}
+}
+
+func TestEpochSchedule(t *testing.T) {
+	quarters := &sm9.EpochSchedule{Origin: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Months: 3}
+	days := &sm9.EpochSchedule{Origin: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC), Length: 24 * time.Hour}
+	var tests = []struct {
+		s         *sm9.EpochSchedule
+		t         time.Time
+		epoch     int64
+		notBefore time.Time
+	}{
+		{quarters, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), 0, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{quarters, time.Date(2026, 11, 15, 8, 0, 0, 0, time.UTC), 3, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{quarters, time.Date(2025, 12, 31, 23, 59, 59, 0, time.UTC), -1, time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{quarters, time.Date(2026, 4, 1, 7, 0, 0, 0, time.FixedZone("CST", 8*3600)), 0, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{days, time.Date(2026, 1, 2, 11, 59, 59, 0, time.UTC), 0, time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{days, time.Date(2026, 1, 1, 11, 0, 0, 0, time.UTC), -1, time.Date(2025, 12, 31, 12, 0, 0, 0, time.UTC)},
+	}
+	for i, tt := range tests {
+		n, err := tt.s.Epoch(tt.t)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != tt.epoch {
+			t.Errorf("case %d: got epoch %d, want %d", i, n, tt.epoch)
+		}
+		id, err := tt.s.IdentityAt([]byte("alice"), "sign", tt.t)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !id.NotBefore.Equal(tt.notBefore) || !id.ValidAt(tt.t) {
+			t.Errorf("case %d: got %v - %v", i, id.NotBefore, id.NotAfter)
+		}
+	}
+	if _, err := (&sm9.EpochSchedule{}).Epoch(time.Now()); err == nil {
+		t.Fatal("expected error for empty schedule")
+	}
+}
+
+// TestEpochScheduleFarTimes checks the times which are more than a
+// time.Duration away from Origin, for which t.Sub saturates.
+func TestEpochScheduleFarTimes(t *testing.T) {
+	origin := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	minutes := &sm9.EpochSchedule{Origin: origin, Length: time.Minute}
+	quarters := &sm9.EpochSchedule{Origin: origin, Months: 3}
+	var tests = []struct {
+		s     *sm9.EpochSchedule
+		t     time.Time
+		epoch int64
+	}{
+		{minutes, time.Time{}, -1065047040},
+		{minutes, time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC), 4193917919},
+		{quarters, time.Time{}, -8100},
+		{quarters, time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC), 31895},
+	}
+	for i, tt := range tests {
+		n, err := tt.s.Epoch(tt.t)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != tt.epoch {
+			t.Errorf("case %d: got epoch %d, want %d", i, n, tt.epoch)
+		}
+		if id := tt.s.Identity([]byte("alice"), "sign", n); !id.ValidAt(tt.t) {
+			t.Errorf("case %d: %v is not in %v - %v", i, tt.t, id.NotBefore, id.NotAfter)
+		}
+	}
+	// the index of a nanosecond epoch doesn't fit in an int64
+	if _, err := (&sm9.EpochSchedule{Origin: origin, Length: time.Nanosecond}).Epoch(time.Time{}); err == nil {
+		t.Fatal("expected error for an epoch index out of range")
+	}
+}
+
+func TestEpochUserKeys(t *testing.T) {
+	masterKey, err := sm9.GenerateSignMasterKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &sm9.EpochSchedule{Origin: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), Months: 3}
+	hid := byte(0x01)
+	id := []byte("alice@corp")
+	keys, err := masterKey.GenerateEpochUserKeys(s, id, "sign", hid, time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 2 || !keys[1].Identity.NotBefore.Equal(time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected epoch keys")
+	}
+	hash := sm3.Sum([]byte("message"))
+	sig, err := keys[0].Key.Sign(rand.Reader, hash[:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := masterKey.PublicKey()
+	if !pub.VerifyAt(s, id, "sign", hid, time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC), hash[:], sig) {
+		t.Fatal("verification failed in the epoch of the key")
+	}
+	if pub.VerifyAt(s, id, "sign", hid, time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC), hash[:], sig) {
+		t.Fatal("verification should fail in the next epoch")
+	}
+	if pub.VerifyAt(s, id, "auth", hid, time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC), hash[:], sig) {
+		t.Fatal("verification should fail for another usage")
+	}
+
+	encMasterKey, err := sm9.GenerateEncryptMasterKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	encKeys, err := encMasterKey.GenerateEpochUserKeys(s, id, "enc", 0x03, now, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, count := range []int{0, -1} {
+		if _, err := encMasterKey.GenerateEpochUserKeys(s, id, "enc", 0x03, now, count); err == nil {
+			t.Errorf("expected error for %d epochs", count)
+		}
+		if _, err := masterKey.GenerateEpochUserKeys(s, id, "sign", hid, now, count); err == nil {
+			t.Errorf("expected error for %d epochs", count)
+		}
+	}
+	uid, err := s.UIDAt(id, "enc", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := encMasterKey.PublicKey().Encrypt(rand.Reader, uid, 0x03, []byte("plaintext"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := encKeys[0].Key.DecryptASN1(uid, ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plaintext) != "plaintext" {
+		t.Fatalf("got %s", plaintext)
+	}
+}
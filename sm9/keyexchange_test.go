@@ -0,0 +1,143 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package sm9_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/emmansun/gmsm/sm9"
+)
+
+func keyExchangeKeys(t *testing.T, hid byte, userA, userB []byte) (*sm9.EncryptPrivateKey, *sm9.EncryptPrivateKey) {
+	t.Helper()
+	masterKey, err := sm9.GenerateEncryptMasterKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyA, err := masterKey.GenerateUserKey(userA, hid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyB, err := masterKey.GenerateUserKey(userB, hid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keyA, keyB
+}
+
+func TestKeyExchangeASN1WithState(t *testing.T) {
+	hid := byte(0x02)
+	userA := []byte("Alice")
+	userB := []byte("Bob")
+	keyA, keyB := keyExchangeKeys(t, hid, userA, userB)
+	now := time.Now()
+	notAfter := now.Add(time.Minute)
+
+	for _, genSignature := range []bool{true, false} {
+		initiator := keyA.NewKeyExchange(userA, userB, 16, genSignature)
+		rA, err := initiator.InitKeyExchangeASN1(rand.Reader, hid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the initiator is restored from its state when RB arrives
+		state, err := initiator.MarshalState(notAfter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		initiator.Destroy()
+		if initiator, err = keyA.UnmarshalKeyExchangeState(state, now); err != nil {
+			t.Fatal(err)
+		}
+
+		responder := keyB.NewKeyExchange(userB, userA, 16, genSignature)
+		response, err := responder.RespondKeyExchangeASN1(rand.Reader, hid, rA)
+		if err != nil {
+			t.Fatal(err)
+		}
+		state, err = responder.MarshalState(notAfter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := keyB.UnmarshalKeyExchangeState(state, notAfter); !errors.Is(err, sm9.ErrKeyExchangeExpired) {
+			t.Fatalf("expected expired error, got %v", err)
+		}
+		if responder, err = keyB.UnmarshalKeyExchangeState(state, now); err != nil {
+			t.Fatal(err)
+		}
+
+		key1, sA, err := initiator.ConfirmResponderASN1(response)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (sA != nil) != genSignature {
+			t.Fatalf("genSignature=%v, got SA %x", genSignature, sA)
+		}
+		if _, err := initiator.MarshalState(notAfter); !errors.Is(err, sm9.ErrKeyExchangeState) {
+			t.Fatalf("expected state error, got %v", err)
+		}
+		key2, err := responder.ConfirmInitiatorASN1(sA)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(key1, key2) {
+			t.Fatal("got different key")
+		}
+	}
+}
+
+func TestKeyExchangeErrors(t *testing.T) {
+	hid := byte(0x02)
+	userA := []byte("Alice")
+	userB := []byte("Bob")
+	keyA, keyB := keyExchangeKeys(t, hid, userA, userB)
+
+	initiator := keyA.NewKeyExchange(userA, userB, 16, true)
+	responder := keyB.NewKeyExchange(userB, userA, 16, true)
+	if _, _, err := initiator.ConfirmResponder(nil, nil); !errors.Is(err, sm9.ErrKeyExchangeState) {
+		t.Fatalf("expected state error, got %v", err)
+	}
+	if _, err := responder.MarshalState(time.Now()); !errors.Is(err, sm9.ErrKeyExchangeState) {
+		t.Fatalf("expected state error, got %v", err)
+	}
+	if _, err := responder.RespondKeyExchangeASN1(rand.Reader, hid, []byte{0x04, 0x00}); !errors.Is(err, sm9.ErrKeyExchangeMessage) {
+		t.Fatalf("expected message error, got %v", err)
+	}
+	if _, _, err := responder.RespondKeyExchange(rand.Reader, hid, make([]byte, 65)); !errors.Is(err, sm9.ErrKeyExchangeMessage) {
+		t.Fatalf("expected message error, got %v", err)
+	}
+
+	rA, err := initiator.InitKeyExchange(rand.Reader, hid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := initiator.InitKeyExchange(rand.Reader, hid); !errors.Is(err, sm9.ErrKeyExchangeState) {
+		t.Fatalf("expected state error, got %v", err)
+	}
+	rB, sB, err := responder.RespondKeyExchange(rand.Reader, hid, rA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	badSB := bytes.Clone(sB)
+	badSB[0] ^= 1
+	if _, _, err := initiator.ConfirmResponder(rB, badSB); !errors.Is(err, sm9.ErrKeyExchangeConfirmation) {
+		t.Fatalf("expected confirmation error, got %v", err)
+	}
+	_, sA, err := initiator.ConfirmResponder(rB, sB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sA[0] ^= 1
+	if _, err := responder.ConfirmInitiator(sA); !errors.Is(err, sm9.ErrKeyExchangeConfirmation) {
+		t.Fatalf("expected confirmation error, got %v", err)
+	}
+
+	if _, err := keyA.UnmarshalKeyExchangeState([]byte{0x30, 0x00}, time.Now()); err == nil {
+		t.Fatal("expected error for invalid state")
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package sm9
+
+import (
+	"bytes"
+	"crypto/cipher"
+	goSubtle "crypto/subtle"
+	"errors"
+	"io"
+
+	"github.com/emmansun/gmsm/sm3"
+	"github.com/emmansun/gmsm/sm4"
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// encTypeGCM is the EncType of GCM mode in SM9MultiRecipientCipher, it is not
+// defined by the SM9 cryptographic algorithm application specification.
+const encTypeGCM encryptType = 16
+
+// multiCheckSize is the size of the check value of a wrapped payload key, it
+// lets a recipient find its entry without trying to decrypt the payload.
+const multiCheckSize = 16
+
+var uidTag = asn1.Tag(0).ContextSpecific()
+
+// Recipient is a receiver of a multi-recipient ciphertext.
+type Recipient struct {
+	UID []byte // receiver's identity
+	HID byte   // encryption private key generation function identifier
+}
+
+// EncryptMulti encrypts plaintext for all the recipients: the plaintext is
+// encrypted once with a random payload key, and the payload key is wrapped for
+// every recipient with [WrapKey]. Every recipient can decrypt the ciphertext
+// with [EncryptPrivateKey.DecryptMulti].
+//
+// opts must be a block cipher mode such as [SM4GCMEncrypterOpts] or
+// [SM4CBCEncrypterOpts], XOR mode is not supported because its key is as long
+// as the plaintext. If anonymous is true, the identities are not included in
+// the ciphertext, a recipient has to try the wrapped keys one by one (one
+// pairing per entry).
+//
+// The ciphertext is in ASN.1 format:
+//
+//	SM9MultiRecipientCipher ::= SEQUENCE {
+//	    EncType     INTEGER,             -- same as SM9Cipher
+//	    Recipients  SEQUENCE OF SEQUENCE {
+//	        UID          [0] IMPLICIT OCTET STRING OPTIONAL,
+//	        C1           BIT STRING,     -- the cipher of WrapKey
+//	        EncryptedKey OCTET STRING    -- payload key XOR K1, followed by the check value K2
+//	    },
+//	    C3          OCTET STRING,        -- SM3(CipherText || MAC key)
+//	    CipherText  OCTET STRING
+//	}
+//
+// where K1 || K2 is the key wrapped for the recipient, and the payload key is
+// the encryption key followed by the 32 bytes MAC key.
+func (pub *EncryptMasterPublicKey) EncryptMulti(rand io.Reader, recipients []Recipient, plaintext []byte, opts EncrypterOpts, anonymous bool) ([]byte, error) {
+	if opts == nil || opts.GetEncryptType() == ENC_TYPE_XOR {
+		return nil, errors.New("sm9: XOR mode is not supported by multi-recipient encryption")
+	}
+	if len(recipients) == 0 {
+		return nil, errors.New("sm9: no recipients")
+	}
+	if len(plaintext) == 0 {
+		return nil, ErrEmptyPlaintext
+	}
+	key1Len := opts.GetKeySize(plaintext)
+	key := make([]byte, key1Len+sm3.Size)
+	if _, err := io.ReadFull(rand, key); err != nil {
+		return nil, err
+	}
+	c2, err := opts.Encrypt(rand, key[:key1Len], plaintext)
+	if err != nil {
+		return nil, err
+	}
+	hash := sm3.New()
+	hash.Write(c2)
+	hash.Write(key[key1Len:])
+	c3 := hash.Sum(nil)
+
+	var b cryptobyte.Builder
+	b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1Int64(int64(opts.GetEncryptType()))
+		b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			for _, r := range recipients {
+				k, c1, err := WrapKey(rand, pub, r.UID, r.HID, len(key)+multiCheckSize)
+				if err != nil {
+					b.SetError(err)
+					return
+				}
+				goSubtle.XORBytes(k, k[:len(key)], key)
+				b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+					if !anonymous {
+						b.AddASN1(uidTag, func(b *cryptobyte.Builder) {
+							b.AddBytes(r.UID)
+						})
+					}
+					b.AddASN1BitString(c1)
+					b.AddASN1OctetString(k)
+				})
+			}
+		})
+		b.AddASN1OctetString(c3)
+		b.AddASN1OctetString(c2)
+	})
+	return b.Bytes()
+}
+
+// DecryptMulti decrypts a ciphertext produced by [EncryptMasterPublicKey.EncryptMulti]
+// with the private key of uid. Entries carrying another identity are skipped,
+// anonymous entries are tried in order.
+func (priv *EncryptPrivateKey) DecryptMulti(uid, ciphertext []byte) ([]byte, error) {
+	var (
+		encType    int
+		recipients cryptobyte.String
+		c3, c2     []byte
+		inner      cryptobyte.String
+	)
+	input := cryptobyte.String(ciphertext)
+	if !input.ReadASN1(&inner, asn1.SEQUENCE) ||
+		!input.Empty() ||
+		!inner.ReadASN1Integer(&encType) ||
+		!inner.ReadASN1(&recipients, asn1.SEQUENCE) ||
+		!inner.ReadASN1Bytes(&c3, asn1.OCTET_STRING) ||
+		!inner.ReadASN1Bytes(&c2, asn1.OCTET_STRING) ||
+		!inner.Empty() {
+		return nil, errors.New("sm9: invalid multi-recipient ciphertext asn.1 data")
+	}
+	opts := multiEncrypterOpts(encryptType(encType))
+	if opts == nil || opts.GetEncryptType() == ENC_TYPE_XOR {
+		return nil, ErrDecryption
+	}
+	key1Len := opts.GetKeySize(c2)
+	keyLen := key1Len + sm3.Size
+
+	for !recipients.Empty() {
+		var (
+			entry, entryUID cryptobyte.String
+			hasUID          bool
+			c1, encKey      []byte
+		)
+		if !recipients.ReadASN1(&entry, asn1.SEQUENCE) ||
+			!entry.ReadOptionalASN1(&entryUID, &hasUID, uidTag) ||
+			!entry.ReadASN1BitStringAsBytes(&c1) ||
+			!entry.ReadASN1Bytes(&encKey, asn1.OCTET_STRING) ||
+			!entry.Empty() {
+			return nil, errors.New("sm9: invalid multi-recipient ciphertext asn.1 data")
+		}
+		if hasUID && !bytes.Equal(entryUID, uid) || len(encKey) != keyLen+multiCheckSize {
+			continue
+		}
+		k, err := UnwrapKey(priv, uid, c1, len(encKey))
+		if err != nil {
+			continue
+		}
+		if goSubtle.ConstantTimeCompare(k[keyLen:], encKey[keyLen:]) != 1 {
+			continue
+		}
+		goSubtle.XORBytes(k, k[:keyLen], encKey[:keyLen])
+		return decryptPayload(k[:keyLen], key1Len, c2, c3, opts)
+	}
+	return nil, ErrDecryption
+}
+
+// GCMEncrypterOpts represents GCM (Galois/Counter Mode) mode.
+//
+// GCM mode is not defined by the SM9 cryptographic algorithm application
+// specification, so it can only be used by multi-recipient encryption, see
+// [EncryptMasterPublicKey.EncryptMulti].
+type GCMEncrypterOpts struct {
+	baseBlockEncrypterOpts
+}
+
+func NewGCMEncrypterOpts(newCipher newCipher, keySize int) EncrypterOpts {
+	opts := new(GCMEncrypterOpts)
+	opts.encryptType = encTypeGCM
+	opts.newCipher = newCipher
+	opts.cipherKeySize = keySize
+	return opts
+}
+
+func (opts *GCMEncrypterOpts) aead(key []byte) (cipher.AEAD, error) {
+	block, err := opts.newCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypt encrypts the plaintext with the key, includes generated nonce at the beginning of the ciphertext.
+func (opts *GCMEncrypterOpts) Encrypt(rand io.Reader, key, plaintext []byte) ([]byte, error) {
+	aead, err := opts.aead(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := io.ReadFull(rand, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (opts *GCMEncrypterOpts) Decrypt(key, ciphertext []byte) ([]byte, error) {
+	aead, err := opts.aead(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aead.NonceSize()+aead.Overhead() {
+		return nil, ErrDecryption
+	}
+	plaintext, err := aead.Open(nil, ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrDecryption
+	}
+	return plaintext, nil
+}
+
+// SM4GCMEncrypterOpts option represents SM4 GCM mode
+var SM4GCMEncrypterOpts = NewGCMEncrypterOpts(sm4.NewCipher, sm4.BlockSize)
+
+// multiEncrypterOpts is like shangMiEncrypterOpts, and also supports GCM mode.
+func multiEncrypterOpts(encType encryptType) EncrypterOpts {
+	if encType == encTypeGCM {
+		return SM4GCMEncrypterOpts
+	}
+	return shangMiEncrypterOpts(encType)
+}
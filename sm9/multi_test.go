@@ -0,0 +1,91 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package sm9_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/emmansun/gmsm/sm9"
+)
+
+func TestEncryptMulti(t *testing.T) {
+	masterKey, err := sm9.GenerateEncryptMasterKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hid := byte(0x03)
+	var recipients []sm9.Recipient
+	var userKeys []*sm9.EncryptPrivateKey
+	for i := range 3 {
+		uid := []byte(fmt.Sprintf("user%d", i))
+		userKey, err := masterKey.GenerateUserKey(uid, hid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		recipients = append(recipients, sm9.Recipient{UID: uid, HID: hid})
+		userKeys = append(userKeys, userKey)
+	}
+	outsider, err := masterKey.GenerateUserKey([]byte("outsider"), hid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext := []byte("Chinese IBE standard")
+	for _, opts := range []sm9.EncrypterOpts{sm9.SM4GCMEncrypterOpts, sm9.SM4CBCEncrypterOpts, sm9.SM4OFBEncrypterOpts} {
+		for _, anonymous := range []bool{false, true} {
+			ciphertext, err := masterKey.PublicKey().EncryptMulti(rand.Reader, recipients, plaintext, opts, anonymous)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := bytes.Contains(ciphertext, recipients[0].UID); got == anonymous {
+				t.Errorf("anonymous=%v, ciphertext contains uid: %v", anonymous, got)
+			}
+			for i, userKey := range userKeys {
+				got, err := userKey.DecryptMulti(recipients[i].UID, ciphertext)
+				if err != nil {
+					t.Fatalf("recipient %d: %v", i, err)
+				}
+				if !bytes.Equal(got, plaintext) {
+					t.Fatalf("recipient %d: decryption mismatch", i)
+				}
+			}
+			if _, err := outsider.DecryptMulti([]byte("outsider"), ciphertext); err != sm9.ErrDecryption {
+				t.Fatalf("expected decryption error, got %v", err)
+			}
+			// the private key only works with its own identity
+			if _, err := userKeys[0].DecryptMulti(recipients[1].UID, ciphertext); err != sm9.ErrDecryption {
+				t.Fatalf("expected decryption error, got %v", err)
+			}
+			// tampered payload
+			ciphertext[len(ciphertext)-1] ^= 1
+			if _, err := userKeys[0].DecryptMulti(recipients[0].UID, ciphertext); err != sm9.ErrDecryption {
+				t.Fatalf("expected decryption error, got %v", err)
+			}
+		}
+	}
+
+	if _, err := masterKey.PublicKey().EncryptMulti(rand.Reader, recipients, plaintext, sm9.DefaultEncrypterOpts, false); err == nil {
+		t.Fatal("expected error for XOR mode")
+	}
+	if _, err := masterKey.PublicKey().EncryptMulti(rand.Reader, nil, plaintext, sm9.SM4GCMEncrypterOpts, false); err == nil {
+		t.Fatal("expected error for no recipients")
+	}
+}
+
+func TestEncryptGCM(t *testing.T) {
+	masterKey, err := sm9.GenerateEncryptMasterKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// SM9Cipher has no EncType for GCM mode
+	if _, err := masterKey.PublicKey().Encrypt(rand.Reader, []byte("emmansun"), 0x03, []byte("Chinese IBE standard"), sm9.SM4GCMEncrypterOpts); err == nil {
+		t.Fatal("expected error for GCM mode")
+	}
+	if _, err := sm9.SM4GCMEncrypterOpts.Decrypt(make([]byte, 16), make([]byte, 27)); err != sm9.ErrDecryption {
+		t.Fatalf("expected decryption error, got %v", err)
+	}
+}
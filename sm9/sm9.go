@@ -7,6 +7,7 @@ import (
 	"errors"
 	"io"
 	"math/big"
+	"time"
 
 	"github.com/emmansun/gmsm/internal/sm9"
 	"github.com/emmansun/gmsm/sm3"
@@ -252,6 +253,9 @@ func (pub *EncryptMasterPublicKey) Encrypt(rand io.Reader, uid []byte, hid byte,
 	if opts == nil {
 		opts = DefaultEncrypterOpts
 	}
+	if opts.GetEncryptType() == encTypeGCM {
+		return nil, errors.New("sm9: GCM mode is only supported by multi-recipient encryption")
+	}
 	c1, c2, c3, err := encrypt(rand, pub, uid, hid, plaintext, opts)
 	if err != nil {
 		return nil, err
@@ -286,17 +290,18 @@ func decrypt(priv *EncryptPrivateKey, uid, c1, c2, c3 []byte, opts EncrypterOpts
 	if err != nil {
 		return nil, err
 	}
-	_ = key[key1Len] // bounds check elimination hint
+	return decryptPayload(key, key1Len, c2, c3, opts)
+}
 
+// decryptPayload checks C3 and decrypts C2 with the key, which is the
+// encryption key followed by the MAC key.
+func decryptPayload(key []byte, key1Len int, c2, c3 []byte, opts EncrypterOpts) ([]byte, error) {
 	hash := sm3.New()
 	hash.Write(c2)
 	hash.Write(key[key1Len:])
-	c32 := hash.Sum(nil)
-
-	if goSubtle.ConstantTimeCompare(c3, c32) != 1 {
+	if goSubtle.ConstantTimeCompare(c3, hash.Sum(nil)) != 1 {
 		return nil, ErrDecryption
 	}
-
 	return opts.Decrypt(key[:key1Len], c2)
 }
 
@@ -426,40 +431,162 @@ type KeyExchange interface {
 	ConfirmInitiator(peerData []byte) ([]byte, error)
 }
 
-// keyExchange represents key exchange struct, include internal stat in whole key exchange flow.
+var (
+	// ErrKeyExchangeMessage is returned when a key exchange message of the peer is malformed.
+	ErrKeyExchangeMessage = sm9.ErrKeyExchangeMessage
+	// ErrKeyExchangeConfirmation is returned when the confirmation (SB or SA) of the peer
+	// does not match, which means the peer does not own the claimed identity or
+	// the messages were tampered with.
+	ErrKeyExchangeConfirmation = sm9.ErrKeyExchangeConfirmation
+	// ErrKeyExchangeState is returned when a key exchange step is called out of order.
+	ErrKeyExchangeState = sm9.ErrKeyExchangeState
+	// ErrKeyExchangeExpired is returned when a saved key exchange state has expired.
+	ErrKeyExchangeExpired = sm9.ErrKeyExchangeExpired
+)
+
+// KeyExchangeSession represents key exchange struct, include internal stat in whole key exchange flow.
 // Initiator's flow will be: NewKeyExchange -> InitKeyExchange -> transmission -> ConfirmResponder
 // Responder's flow will be: NewKeyExchange -> waiting ... -> RepondKeyExchange -> transmission -> ConfirmInitiator
-type keyExchange struct {
+//
+// The ASN1 variants of the methods encode the messages as:
+//
+//	RA ::= BIT STRING                  -- initiator's ephemeral public key
+//	SM9KeyExchangeResponse ::= SEQUENCE {
+//	    RB BIT STRING,                 -- responder's ephemeral public key
+//	    SB OCTET STRING OPTIONAL       -- responder's confirmation
+//	}
+//	SA ::= OCTET STRING                -- initiator's confirmation
+//
+// A stateless server can save the intermediate state with [KeyExchangeSession.MarshalState]
+// and continue with [EncryptPrivateKey.UnmarshalKeyExchangeState].
+type KeyExchangeSession struct {
 	ke *sm9.KeyExchange
 }
 
+var _ KeyExchange = (*KeyExchangeSession)(nil)
+
 // NewKeyExchange initializes a new key exchange process using the provided user IDs and key length.
-// It returns a pointer to a keyExchange struct which contains the key exchange instance.
-func (priv *EncryptPrivateKey) NewKeyExchange(uid, peerUID []byte, keyLen int, genSignature bool) *keyExchange {
-	return &keyExchange{ke: priv.internal.NewKeyExchange(uid, peerUID, keyLen, genSignature)}
+// It returns a pointer to a KeyExchangeSession struct which contains the key exchange instance.
+func (priv *EncryptPrivateKey) NewKeyExchange(uid, peerUID []byte, keyLen int, genSignature bool) *KeyExchangeSession {
+	return &KeyExchangeSession{ke: priv.internal.NewKeyExchange(uid, peerUID, keyLen, genSignature)}
 }
 
 // Destroy securely wipes the key exchange data from memory.
-func (ke *keyExchange) Destroy() {
+func (ke *KeyExchangeSession) Destroy() {
 	ke.ke.Destroy()
 }
 
 // InitKeyExchange generates random with responder uid, for initiator's step A1-A4
-func (ke *keyExchange) InitKeyExchange(rand io.Reader, hid byte) ([]byte, error) {
+func (ke *KeyExchangeSession) InitKeyExchange(rand io.Reader, hid byte) ([]byte, error) {
 	return ke.ke.InitKeyExchange(rand, hid)
 }
 
 // RespondKeyExchange when responder receive rA, for responder's step B1-B7
-func (ke *keyExchange) RespondKeyExchange(rand io.Reader, hid byte, peerData []byte) ([]byte, []byte, error) {
+func (ke *KeyExchangeSession) RespondKeyExchange(rand io.Reader, hid byte, peerData []byte) ([]byte, []byte, error) {
 	return ke.ke.RespondKeyExchange(rand, hid, peerData)
 }
 
 // ConfirmResponder for initiator's step A5-A7
-func (ke *keyExchange) ConfirmResponder(rB, sB []byte) ([]byte, []byte, error) {
+func (ke *KeyExchangeSession) ConfirmResponder(rB, sB []byte) ([]byte, []byte, error) {
 	return ke.ke.ConfirmResponder(rB, sB)
 }
 
 // ConfirmInitiator for responder's step B8
-func (ke *keyExchange) ConfirmInitiator(peerData []byte) ([]byte, error) {
+func (ke *KeyExchangeSession) ConfirmInitiator(peerData []byte) ([]byte, error) {
 	return ke.ke.ConfirmInitiator(peerData)
 }
+
+// InitKeyExchangeASN1 is like InitKeyExchange, but returns RA in ASN.1 format.
+func (ke *KeyExchangeSession) InitKeyExchangeASN1(rand io.Reader, hid byte) ([]byte, error) {
+	rA, err := ke.ke.InitKeyExchange(rand, hid)
+	if err != nil {
+		return nil, err
+	}
+	var b cryptobyte.Builder
+	b.AddASN1BitString(rA)
+	return b.Bytes()
+}
+
+// RespondKeyExchangeASN1 is like RespondKeyExchange, but takes RA in ASN.1 format
+// and returns RB and the optional SB as SM9KeyExchangeResponse.
+func (ke *KeyExchangeSession) RespondKeyExchangeASN1(rand io.Reader, hid byte, rADER []byte) ([]byte, error) {
+	var rA []byte
+	input := cryptobyte.String(rADER)
+	if !input.ReadASN1BitStringAsBytes(&rA) || !input.Empty() {
+		return nil, ErrKeyExchangeMessage
+	}
+	rB, sB, err := ke.ke.RespondKeyExchange(rand, hid, rA)
+	if err != nil {
+		return nil, err
+	}
+	var b cryptobyte.Builder
+	b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1BitString(rB)
+		if len(sB) > 0 {
+			b.AddASN1OctetString(sB)
+		}
+	})
+	return b.Bytes()
+}
+
+// ConfirmResponderASN1 is like ConfirmResponder, but takes SM9KeyExchangeResponse
+// and returns the shared key and SA in ASN.1 format, SA is nil if no
+// confirmation is generated.
+func (ke *KeyExchangeSession) ConfirmResponderASN1(responseDER []byte) ([]byte, []byte, error) {
+	var (
+		rB, sB []byte
+		inner  cryptobyte.String
+	)
+	input := cryptobyte.String(responseDER)
+	if !input.ReadASN1(&inner, asn1.SEQUENCE) ||
+		!input.Empty() ||
+		!inner.ReadASN1BitStringAsBytes(&rB) {
+		return nil, nil, ErrKeyExchangeMessage
+	}
+	if !inner.Empty() && (!inner.ReadASN1Bytes(&sB, asn1.OCTET_STRING) || !inner.Empty()) {
+		return nil, nil, ErrKeyExchangeMessage
+	}
+	key, sA, err := ke.ke.ConfirmResponder(rB, sB)
+	if err != nil || sA == nil {
+		return key, nil, err
+	}
+	var b cryptobyte.Builder
+	b.AddASN1OctetString(sA)
+	sADER, err := b.Bytes()
+	if err != nil {
+		return nil, nil, err
+	}
+	return key, sADER, nil
+}
+
+// ConfirmInitiatorASN1 is like ConfirmInitiator, but takes SA in ASN.1 format,
+// sADER can be nil if the initiator does not send the confirmation.
+func (ke *KeyExchangeSession) ConfirmInitiatorASN1(sADER []byte) ([]byte, error) {
+	var sA []byte
+	if sADER != nil {
+		input := cryptobyte.String(sADER)
+		if !input.ReadASN1Bytes(&sA, asn1.OCTET_STRING) || !input.Empty() {
+			return nil, ErrKeyExchangeMessage
+		}
+	}
+	return ke.ke.ConfirmInitiator(sA)
+}
+
+// MarshalState encodes the intermediate state of an initiator waiting for RB, or
+// a responder waiting for SA, the state expires after notAfter. It contains the
+// ephemeral private key, so it must be kept confidential (e.g. encrypted with a
+// server key) when it is stored or sent out.
+func (ke *KeyExchangeSession) MarshalState(notAfter time.Time) ([]byte, error) {
+	return ke.ke.MarshalState(notAfter)
+}
+
+// UnmarshalKeyExchangeState restores a key exchange of priv from the state
+// encoded by [KeyExchangeSession.MarshalState], it returns [ErrKeyExchangeExpired]
+// if the state has expired at now.
+func (priv *EncryptPrivateKey) UnmarshalKeyExchangeState(state []byte, now time.Time) (*KeyExchangeSession, error) {
+	ke, err := priv.internal.UnmarshalKeyExchangeState(state, now)
+	if err != nil {
+		return nil, err
+	}
+	return &KeyExchangeSession{ke: ke}, nil
+}
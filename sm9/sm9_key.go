@@ -151,6 +151,17 @@ func (pub *SignMasterPublicKey) Equal(x crypto.PublicKey) bool {
 	return subtle.ConstantTimeCompare(pub.publicKey, xx.publicKey) == 1
 }
 
+// Precompute computes the values which only depend on the master public key,
+// the pairing value g = e(P1, Ppub-s) and the fixed-base tables of g, now
+// instead of on first use.
+//
+// The values are cached and shared by all the keys with the same master public
+// key, including keys parsed separately, so calling Precompute is optional; it
+// is useful to avoid the latency of the first operation, e.g. when a server starts.
+func (pub *SignMasterPublicKey) Precompute() {
+	pub.internal.Prepared().Precompute()
+}
+
 // Bytes returns the byte representation of the SignMasterPublicKey.
 // It calls the Bytes method on the underlying publicKey field.
 func (pub *SignMasterPublicKey) Bytes() []byte {
@@ -402,6 +413,13 @@ func (pub *EncryptMasterPublicKey) Equal(x crypto.PublicKey) bool {
 	return subtle.ConstantTimeCompare(pub.publicKey, xx.publicKey) == 1
 }
 
+// Precompute computes the values which only depend on the master public key,
+// the pairing value g = e(Ppub-e, P2), the fixed-base tables of g and of Ppub-e,
+// now instead of on first use. See [SignMasterPublicKey.Precompute].
+func (pub *EncryptMasterPublicKey) Precompute() {
+	pub.internal.Prepared().Precompute()
+}
+
 // Bytes returns the byte representation of the EncryptMasterPublicKey.
 // It delegates the call to the Bytes method of the underlying publicKey.
 func (pub *EncryptMasterPublicKey) Bytes() []byte {
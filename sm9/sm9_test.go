@@ -618,6 +618,36 @@ func BenchmarkEncrypt(b *testing.B) {
 	}
 }
 
+func BenchmarkEncryptParsedMasterKey(b *testing.B) {
+	plaintext := []byte("Chinese IBE standard")
+	masterKey, err := sm9.GenerateEncryptMasterKey(rand.Reader)
+	hid := byte(0x01)
+	uid := []byte("emmansun")
+	if err != nil {
+		b.Fatal(err)
+	}
+	der, err := masterKey.PublicKey().MarshalASN1()
+	if err != nil {
+		b.Fatal(err)
+	}
+	masterKey.PublicKey().Precompute()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// the precomputed values are shared by master public keys with the same value
+		pub, err := sm9.UnmarshalEncryptMasterPublicKeyASN1(der)
+		if err != nil {
+			b.Fatal(err)
+		}
+		cipher, err := sm9.Encrypt(rand.Reader, pub, uid, hid, plaintext, nil)
+		if err != nil {
+			b.Fatal(err)
+		}
+		// Prevent the compiler from optimizing out the operation.
+		plaintext[0] = cipher[0]
+	}
+}
+
 func BenchmarkDecrypt(b *testing.B) {
 	plaintext := []byte("Chinese IBE standard")
 	masterKey, err := sm9.GenerateEncryptMasterKey(rand.Reader)
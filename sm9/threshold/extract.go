@@ -0,0 +1,440 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package threshold
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+
+	"github.com/emmansun/gmsm/internal/bigmod"
+	sm9internal "github.com/emmansun/gmsm/internal/sm9"
+	"github.com/emmansun/gmsm/internal/sm9/bn256"
+	"github.com/emmansun/gmsm/sm3"
+	"github.com/emmansun/gmsm/sm9"
+)
+
+// The user key is dA = s/(h1+s)*P1 (or de = s/(h1+s)*P2 for encryption), which
+// equals P - h1/(h1+s)*P. The nodes compute 1/(h1+s)*P without revealing s:
+//
+//  1. every issuing node deals a random r with threshold t and a sharing of zero
+//     z with threshold 2t-1, with the Pedersen commitments r*P + ρ*H and
+//     z*P + ζ*H in the group of the user key, and proves that the constant
+//     commitment of z is a multiple of H, so that z shares zero;
+//  2. node i opens v_i = (h1+s_i)*r_i + z_i and w_i = (h1+s_i)*ρ_i + ζ_i, v_i is
+//     a share of u = (h1+s)*r with threshold 2t-1, (v_i, w_i) is verified with
+//     pairings against the commitments of s, r and z, so that a cheating node
+//     is identified;
+//  3. u is interpolated from 2t-1 product shares, and node i returns the
+//     partial key (r_i/u)*P, a share of 1/(h1+s)*P with threshold t.
+//
+// u reveals nothing about s, because r is uniformly random and z masks the
+// product shares. The commitments of r must be hiding: from the Feldman
+// commitment r*P anyone would compute 1/(h1+s)*P = (r*P)/u and the user key.
+// The blinding ζ of the constant term is random, so that w doesn't reveal
+// (h1+s)*ρ either.
+
+// UserKeyDealing is the randomness dealt by one issuing node for the
+// extraction of a user key.
+type UserKeyDealing struct {
+	R     *Dealing // a random sharing with threshold t
+	Z     *Dealing // a sharing of zero with threshold 2t-1
+	Proof []byte   // proof that the constant commitment of Z is a multiple of H
+}
+
+// UserKeyContribution is what the node with the given index receives from a
+// [UserKeyDealing].
+type UserKeyContribution struct {
+	R     *Contribution
+	Z     *Contribution
+	Proof []byte
+}
+
+// Contribution returns the contribution of the dealing for the node with
+// index j (1-based).
+func (d *UserKeyDealing) Contribution(j int) *UserKeyContribution {
+	return &UserKeyContribution{R: d.R.Contribution(j), Z: d.Z.Contribution(j), Proof: d.Proof}
+}
+
+func (node *Node) checkIssuance() error {
+	if 2*node.threshold-1 > node.parties {
+		return errors.New("sm9/threshold: user key extraction needs 2t-1 nodes")
+	}
+	return nil
+}
+
+// DealUserKey is the first round of a user key extraction, every issuing node
+// deals fresh randomness to all the nodes. It requires n >= 2t-1.
+func (node *Node) DealUserKey(rand io.Reader) (*UserKeyDealing, error) {
+	if err := node.checkIssuance(); err != nil {
+		return nil, err
+	}
+	if node.keyType == SignKey {
+		return dealUserKey[bn256.G1](rand, node.threshold, node.parties)
+	}
+	return dealUserKey[bn256.G2](rand, node.threshold, node.parties)
+}
+
+func dealUserKey[T any, P point[T]](rand io.Reader, t, n int) (*UserKeyDealing, error) {
+	r, err := randomScalar(rand)
+	if err != nil {
+		return nil, err
+	}
+	rho, err := randomScalar(rand)
+	if err != nil {
+		return nil, err
+	}
+	zeta, err := randomScalar(rand)
+	if err != nil {
+		return nil, err
+	}
+	d := new(UserKeyDealing)
+	if d.R, err = dealPedersen[T, P](rand, r, rho, t-1, n); err != nil {
+		return nil, err
+	}
+	if d.Z, err = dealPedersen[T, P](rand, zeroScalar(), zeta, 2*t-2, n); err != nil {
+		return nil, err
+	}
+	if d.Proof, err = proveBlinding[T, P](rand, d.Z.Commitments[0], zeta); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// blindingChallenge returns the challenge of the proof of knowledge of the
+// discrete logarithm of c to the base H.
+func blindingChallenge(c, k []byte) *bigmod.Nat {
+	h := sm3.New()
+	h.Write([]byte(pedersenDST))
+	h.Write(c)
+	h.Write(k)
+	e, err := bigmod.NewNat().SetOverflowingBytes(h.Sum(nil), orderNat)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// proveBlinding returns the Schnorr proof of knowledge of zeta with
+// c = zeta*H, which shows that c commits to zero: K || zeta*e + k, where
+// K = k*H and e is the challenge.
+func proveBlinding[T any, P point[T]](rand io.Reader, c []byte, zeta *bigmod.Nat) ([]byte, error) {
+	k, err := randomScalar(rand)
+	if err != nil {
+		return nil, err
+	}
+	kPoint := scalarMult[T](pedersenBase[T, P](), k).MarshalUncompressed()
+	e := blindingChallenge(c, kPoint)
+	z := e.Mul(zeta, orderNat).Add(k, orderNat)
+	return append(kPoint, z.Bytes(orderNat)...), nil
+}
+
+// verifyBlinding checks the proof of [proveBlinding] for the commitment c.
+func verifyBlinding[T any, P point[T]](c []byte, proof []byte) error {
+	errProof := errors.New("sm9/threshold: masking dealing does not share zero")
+	n := orderNat.Size()
+	if len(proof) <= n {
+		return errProof
+	}
+	kPoint, err := unmarshalPoint[T, P](proof[:len(proof)-n])
+	if err != nil {
+		return errProof
+	}
+	z, err := parseScalar(proof[len(proof)-n:])
+	if err != nil {
+		return errProof
+	}
+	cPoint, err := unmarshalPoint[T, P](c)
+	if err != nil {
+		return err
+	}
+	e := blindingChallenge(c, proof[:len(proof)-n])
+	// z*H == K + e*C
+	want := P(P(new(T)).Add(kPoint, scalarMult[T](cPoint, e)))
+	if !equalPoints[T](scalarMult[T](pedersenBase[T, P](), z), want) {
+		return errProof
+	}
+	return nil
+}
+
+// Issuance is the state of a node during the extraction of one user key.
+type Issuance struct {
+	node         *Node
+	h1           *bigmod.Nat
+	r, rho       *bigmod.Nat
+	z, zeta      *bigmod.Nat
+	rCommitments [][]byte
+	zCommitments [][]byte
+}
+
+// ProductShare is the value opened by an issuing node in the second round of
+// a user key extraction.
+type ProductShare struct {
+	Index    int
+	Value    []byte // (h1+s_i)*r_i + z_i
+	Blinding []byte // (h1+s_i)*ρ_i + ζ_i
+}
+
+// PartialKey is the share of a user key returned by an issuing node.
+type PartialKey struct {
+	Index int
+	Point []byte // uncompressed point, in G1 for signature keys and G2 for encryption keys
+}
+
+// NewIssuance starts the extraction of the user key for uid and hid with the
+// contributions received from the dealers of [Node.DealUserKey]. All the
+// issuing nodes must use the dealings of the same set of dealers.
+func (node *Node) NewIssuance(uid []byte, hid byte, contributions []*UserKeyContribution) (*Issuance, error) {
+	if err := node.checkIssuance(); err != nil {
+		return nil, err
+	}
+	if node.keyType == SignKey {
+		return newIssuance[bn256.G1](node, uid, hid, contributions)
+	}
+	return newIssuance[bn256.G2](node, uid, hid, contributions)
+}
+
+func newIssuance[T any, P point[T]](node *Node, uid []byte, hid byte, contributions []*UserKeyContribution) (*Issuance, error) {
+	rs := make([]*Contribution, len(contributions))
+	zs := make([]*Contribution, len(contributions))
+	for i, c := range contributions {
+		if len(c.Z.Commitments) == 0 {
+			return nil, errors.New("sm9/threshold: invalid number of commitments")
+		}
+		if err := verifyBlinding[T, P](c.Z.Commitments[0], c.Proof); err != nil {
+			return nil, err
+		}
+		rs[i], zs[i] = c.R, c.Z
+	}
+	r, rho, rcs, err := combine[T, P](rs, node.threshold-1, node.index, true)
+	if err != nil {
+		return nil, err
+	}
+	z, zeta, zcs, err := combine[T, P](zs, 2*node.threshold-2, node.index, true)
+	if err != nil {
+		return nil, err
+	}
+	h1, err := parseScalar(sm9internal.HashUserID(uid, hid))
+	if err != nil {
+		return nil, err
+	}
+	return &Issuance{
+		node:         node,
+		h1:           h1,
+		r:            r,
+		rho:          rho,
+		z:            z,
+		zeta:         zeta,
+		rCommitments: marshalPoints[T](rcs),
+		zCommitments: marshalPoints[T](zcs),
+	}, nil
+}
+
+// ProductShare returns the values (h1+s_i)*r_i+z_i and (h1+s_i)*ρ_i+ζ_i of
+// this node, they must be broadcast to the other issuing nodes.
+func (is *Issuance) ProductShare() *ProductShare {
+	k := bigmod.NewNat().Set(is.h1).Add(is.node.share, orderNat)
+	v := bigmod.NewNat().Set(k).Mul(is.r, orderNat).Add(is.z, orderNat)
+	w := k.Mul(is.rho, orderNat).Add(is.zeta, orderNat)
+	return &ProductShare{Index: is.node.index, Value: v.Bytes(orderNat), Blinding: w.Bytes(orderNat)}
+}
+
+// VerifyProductShare checks the product share of another issuing node
+// against the public commitments.
+func (is *Issuance) VerifyProductShare(ps *ProductShare) error {
+	if err := checkIndices([]int{ps.Index}, is.node.parties); err != nil {
+		return err
+	}
+	v, err := parseScalar(ps.Value)
+	if err != nil {
+		return err
+	}
+	w, err := parseScalar(ps.Blinding)
+	if err != nil {
+		return err
+	}
+	// -v and -w, to compute Z_j - v*P - w*H
+	negV := zeroScalar().Sub(v, orderNat)
+	negW := zeroScalar().Sub(w, orderNat)
+
+	var gt *bn256.GT
+	if is.node.keyType == SignKey {
+		// e(R_j, h1*P2 + S_j) * e(Z_j - v*P1 - w*H, P2) == 1
+		r, z, err := userKeyCommitments[bn256.G1](is, ps.Index)
+		if err != nil {
+			return err
+		}
+		s, err := masterCommitment[bn256.G2](is.node, ps.Index)
+		if err != nil {
+			return err
+		}
+		q := new(bn256.G2).Add(baseMult[bn256.G2](is.h1), s)
+		zv := new(bn256.G1).Add(z, baseMult[bn256.G1](negV))
+		zv.Add(zv, scalarMult[bn256.G1](pedersenG1(), negW))
+		gt = new(bn256.GT).Add(bn256.Miller(r, q), bn256.Miller(zv, bn256.Gen2))
+	} else {
+		// e(h1*P1 + S_j, R_j) * e(P1, Z_j - v*P2 - w*H) == 1
+		r, z, err := userKeyCommitments[bn256.G2](is, ps.Index)
+		if err != nil {
+			return err
+		}
+		s, err := masterCommitment[bn256.G1](is.node, ps.Index)
+		if err != nil {
+			return err
+		}
+		q := new(bn256.G1).Add(baseMult[bn256.G1](is.h1), s)
+		zv := new(bn256.G2).Add(z, baseMult[bn256.G2](negV))
+		zv.Add(zv, scalarMult[bn256.G2](pedersenG2(), negW))
+		gt = new(bn256.GT).Add(bn256.Miller(q, r), bn256.Miller(bn256.Gen1, zv))
+	}
+	if !bytes.Equal(gt.Finalize().Marshal(), new(bn256.GT).SetOne().Marshal()) {
+		return errors.New("sm9/threshold: invalid product share")
+	}
+	return nil
+}
+
+func userKeyCommitments[T any, P point[T]](is *Issuance, j int) (r, z P, err error) {
+	rcs, err := unmarshalPoints[T, P](is.rCommitments)
+	if err != nil {
+		return nil, nil, err
+	}
+	zcs, err := unmarshalPoints[T, P](is.zCommitments)
+	if err != nil {
+		return nil, nil, err
+	}
+	return evalCommitments[T](rcs, j), evalCommitments[T](zcs, j), nil
+}
+
+func masterCommitment[T any, P point[T]](node *Node, j int) (P, error) {
+	cs, err := unmarshalPoints[T, P](node.commitments)
+	if err != nil {
+		return nil, err
+	}
+	return evalCommitments[T](cs, j), nil
+}
+
+// PartialKey verifies the product shares of the issuing nodes, which must
+// include at least 2t-1 nodes, and returns the partial key of this node. The
+// partial key must be sent to the user over a private channel.
+func (is *Issuance) PartialKey(products []*ProductShare) (*PartialKey, error) {
+	if len(products) < 2*is.node.threshold-1 {
+		return nil, errors.New("sm9/threshold: not enough product shares")
+	}
+	indices := make([]int, len(products))
+	for i, ps := range products {
+		indices[i] = ps.Index
+	}
+	if err := checkIndices(indices, is.node.parties); err != nil {
+		return nil, err
+	}
+	for _, ps := range products {
+		if err := is.VerifyProductShare(ps); err != nil {
+			return nil, err
+		}
+	}
+	u := zeroScalar()
+	for i, l := range lagrangeAtZero(indices) {
+		v, _ := parseScalar(products[i].Value)
+		u.Add(v.Mul(l, orderNat), orderNat)
+	}
+	if u.IsZero() == 1 {
+		// h1 + s == 0, the master key must be regenerated.
+		return nil, errors.New("sm9/threshold: need to re-generate master key")
+	}
+	k := inverse(u).Mul(is.r, orderNat)
+	var p []byte
+	if is.node.keyType == SignKey {
+		p = baseMult[bn256.G1](k).MarshalUncompressed()
+	} else {
+		p = baseMult[bn256.G2](k).MarshalUncompressed()
+	}
+	return &PartialKey{Index: is.node.index, Point: p}, nil
+}
+
+// combinePartialKeys returns P - h1*sum(l_i*D_i) = s/(h1+s)*P.
+func combinePartialKeys[T any, P point[T]](h1 *bigmod.Nat, partials []*PartialKey) (P, error) {
+	if len(partials) == 0 {
+		return nil, errors.New("sm9/threshold: no partial keys")
+	}
+	indices := make([]int, len(partials))
+	for i, pk := range partials {
+		indices[i] = pk.Index
+	}
+	// The user does not need to know the number of nodes.
+	if err := checkIndices(indices, math.MaxInt); err != nil {
+		return nil, err
+	}
+	sum := baseMult[T, P](zeroScalar())
+	for i, l := range lagrangeAtZero(indices) {
+		d, err := unmarshalPoint[T, P](partials[i].Point)
+		if err != nil {
+			return nil, err
+		}
+		sum = P(new(T)).Add(sum, scalarMult[T](d, l))
+	}
+	sum = scalarMult[T](sum, h1)
+	return P(new(T)).Add(baseMult[T, P](indexScalar(1)), P(new(T)).Neg(sum)), nil
+}
+
+// CombineSignPrivateKey combines the partial keys of at least t issuing nodes
+// into the signature private key of uid and hid. The combined key is verified
+// against the master public key, an error is returned if any partial key is
+// invalid or there are not enough of them.
+func CombineSignPrivateKey(master *sm9.SignMasterPublicKey, uid []byte, hid byte, partials []*PartialKey) (*sm9.SignPrivateKey, error) {
+	h1, err := parseScalar(sm9internal.HashUserID(uid, hid))
+	if err != nil {
+		return nil, err
+	}
+	d, err := combinePartialKeys[bn256.G1](h1, partials)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := unmarshalPoint[bn256.G2](master.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	// e(dA, h1*P2 + Ppub-s) == e(P1, Ppub-s)
+	q := new(bn256.G2).Add(baseMult[bn256.G2](h1), pub)
+	if !bytes.Equal(bn256.Pair(d, q).Marshal(), bn256.Pair(bn256.Gen1, pub).Marshal()) {
+		return nil, errors.New("sm9/threshold: invalid partial keys")
+	}
+	priv, err := sm9.UnmarshalSignPrivateKeyRaw(d.MarshalUncompressed())
+	if err != nil {
+		return nil, err
+	}
+	priv.SetMasterPublic(master)
+	return priv, nil
+}
+
+// CombineEncryptPrivateKey combines the partial keys of at least t issuing
+// nodes into the encryption private key of uid and hid. The combined key is
+// verified against the master public key, an error is returned if any partial
+// key is invalid or there are not enough of them.
+func CombineEncryptPrivateKey(master *sm9.EncryptMasterPublicKey, uid []byte, hid byte, partials []*PartialKey) (*sm9.EncryptPrivateKey, error) {
+	h1, err := parseScalar(sm9internal.HashUserID(uid, hid))
+	if err != nil {
+		return nil, err
+	}
+	d, err := combinePartialKeys[bn256.G2](h1, partials)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := unmarshalPoint[bn256.G1](master.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	// e(h1*P1 + Ppub-e, de) == e(Ppub-e, P2)
+	q := new(bn256.G1).Add(baseMult[bn256.G1](h1), pub)
+	if !bytes.Equal(bn256.Pair(q, d).Marshal(), bn256.Pair(pub, bn256.Gen2).Marshal()) {
+		return nil, errors.New("sm9/threshold: invalid partial keys")
+	}
+	priv, err := sm9.UnmarshalEncryptPrivateKeyRaw(d.MarshalUncompressed())
+	if err != nil {
+		return nil, err
+	}
+	priv.SetMasterPublic(master)
+	return priv, nil
+}
@@ -0,0 +1,144 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package threshold
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+
+	"github.com/emmansun/gmsm/internal/bigmod"
+	"github.com/emmansun/gmsm/internal/sm9/bn256"
+)
+
+// point is the common interface of bn256.G1 and bn256.G2.
+type point[T any] interface {
+	*T
+	ScalarBaseMult(scalar []byte) (*T, error)
+	ScalarMult(a *T, scalar []byte) (*T, error)
+	Add(a, b *T) *T
+	Neg(a *T) *T
+	MarshalUncompressed() []byte
+	Unmarshal(m []byte) ([]byte, error)
+}
+
+// unmarshalPoint parses an uncompressed point, the point at infinity is
+// encoded as 0x04 followed by zeros.
+func unmarshalPoint[T any, P point[T]](b []byte) (P, error) {
+	if len(b) == 0 || b[0] != 4 {
+		return nil, errors.New("sm9/threshold: invalid point encoding")
+	}
+	p := P(new(T))
+	rest, err := p.Unmarshal(b[1:])
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("sm9/threshold: invalid point encoding")
+	}
+	return p, nil
+}
+
+func unmarshalPoints[T any, P point[T]](bs [][]byte) ([]P, error) {
+	ps := make([]P, len(bs))
+	for i, b := range bs {
+		p, err := unmarshalPoint[T, P](b)
+		if err != nil {
+			return nil, err
+		}
+		ps[i] = p
+	}
+	return ps, nil
+}
+
+func baseMult[T any, P point[T]](k *bigmod.Nat) P {
+	p, err := P(new(T)).ScalarBaseMult(k.Bytes(orderNat))
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func scalarMult[T any, P point[T]](a P, k *bigmod.Nat) P {
+	p, err := P(new(T)).ScalarMult(a, k.Bytes(orderNat))
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func equalPoints[T any, P point[T]](a, b P) bool {
+	return bytes.Equal(a.MarshalUncompressed(), b.MarshalUncompressed())
+}
+
+func isInfinity[T any, P point[T]](a P) bool {
+	return equalPoints[T](a, baseMult[T, P](zeroScalar()))
+}
+
+// commit returns the Feldman commitments of the polynomial coefficients.
+func commit[T any, P point[T]](p polynomial) [][]byte {
+	cs := make([][]byte, len(p))
+	for i, a := range p {
+		cs[i] = baseMult[T, P](a).MarshalUncompressed()
+	}
+	return cs
+}
+
+// commitPedersen returns the Pedersen commitments a_k*P + b_k*H of the
+// coefficients of the polynomials a and b.
+func commitPedersen[T any, P point[T]](a, b polynomial) [][]byte {
+	h := pedersenBase[T, P]()
+	cs := make([][]byte, len(a))
+	for i := range a {
+		cs[i] = P(P(new(T)).Add(baseMult[T, P](a[i]), scalarMult[T](h, b[i]))).MarshalUncompressed()
+	}
+	return cs
+}
+
+const pedersenDST = "SM9-THRESHOLD-KGC_PEDERSEN-GENERATOR_"
+
+var (
+	pedersenG1 = sync.OnceValue(func() *bn256.G1 { return bn256.HashToG1(nil, []byte(pedersenDST)) })
+	pedersenG2 = sync.OnceValue(func() *bn256.G2 { return bn256.HashToG2(nil, []byte(pedersenDST)) })
+)
+
+// pedersenBase returns the generator H of the Pedersen commitments in the
+// group of T. H is hashed to the curve, so nobody knows its discrete logarithm
+// to the base P1 or P2. The returned point must not be modified.
+func pedersenBase[T any, P point[T]]() P {
+	switch any(P(nil)).(type) {
+	case *bn256.G1:
+		return any(pedersenG1()).(P)
+	default:
+		return any(pedersenG2()).(P)
+	}
+}
+
+// evalCommitments returns sum(C_k * x^k), the commitment of the share of
+// party x.
+func evalCommitments[T any, P point[T]](cs []P, x int) P {
+	xNat := indexScalar(x)
+	v := baseMult[T, P](zeroScalar())
+	for i := len(cs) - 1; i >= 0; i-- {
+		v = scalarMult[T](v, xNat)
+		v = P(new(T)).Add(v, cs[i])
+	}
+	return v
+}
+
+// addCommitments adds the commitments b to a coefficient-wise.
+func addCommitments[T any, P point[T]](a, b []P) {
+	for i := range a {
+		a[i] = P(new(T)).Add(a[i], b[i])
+	}
+}
+
+func marshalPoints[T any, P point[T]](ps []P) [][]byte {
+	bs := make([][]byte, len(ps))
+	for i, p := range ps {
+		bs[i] = p.MarshalUncompressed()
+	}
+	return bs
+}
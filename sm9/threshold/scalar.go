@@ -0,0 +1,112 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package threshold
+
+import (
+	"errors"
+	"io"
+
+	"github.com/emmansun/gmsm/internal/bigmod"
+	"github.com/emmansun/gmsm/internal/sm9/bn256"
+)
+
+var orderNat, _ = bigmod.NewModulus(bn256.OrderBytes)
+
+// randomScalar returns a uniformly random scalar in [1, N-1].
+func randomScalar(rand io.Reader) (*bigmod.Nat, error) {
+	b := make([]byte, orderNat.Size())
+	k := bigmod.NewNat()
+	for {
+		if _, err := io.ReadFull(rand, b); err != nil {
+			return nil, err
+		}
+		if _, err := k.SetBytes(b, orderNat); err == nil && k.IsZero() == 0 {
+			return k, nil
+		}
+	}
+}
+
+func zeroScalar() *bigmod.Nat {
+	return bigmod.NewNat().ExpandFor(orderNat)
+}
+
+func indexScalar(i int) *bigmod.Nat {
+	return bigmod.NewNat().SetUint(uint(i), orderNat)
+}
+
+func parseScalar(b []byte) (*bigmod.Nat, error) {
+	if len(b) != orderNat.Size() {
+		return nil, errors.New("sm9/threshold: invalid scalar length")
+	}
+	return bigmod.NewNat().SetBytes(b, orderNat)
+}
+
+func inverse(k *bigmod.Nat) *bigmod.Nat {
+	return bigmod.NewNat().Exp(k, bn256.OrderMinus2Bytes, orderNat)
+}
+
+// polynomial is a polynomial over Z_N, p[i] is the coefficient of x^i.
+type polynomial []*bigmod.Nat
+
+// randomPolynomial returns a random polynomial of the given degree whose
+// constant term is secret.
+func randomPolynomial(rand io.Reader, secret *bigmod.Nat, degree int) (polynomial, error) {
+	p := make(polynomial, degree+1)
+	p[0] = secret
+	for i := 1; i <= degree; i++ {
+		k, err := randomScalar(rand)
+		if err != nil {
+			return nil, err
+		}
+		p[i] = k
+	}
+	return p, nil
+}
+
+// eval evaluates p at x with Horner's method.
+func (p polynomial) eval(x int) *bigmod.Nat {
+	xNat := indexScalar(x)
+	v := zeroScalar()
+	for i := len(p) - 1; i >= 0; i-- {
+		v.Mul(xNat, orderNat).Add(p[i], orderNat)
+	}
+	return v
+}
+
+// lagrangeAtZero returns the Lagrange coefficients for interpolating at 0 from
+// the values at indices, which must be distinct and in [1, N-1].
+func lagrangeAtZero(indices []int) []*bigmod.Nat {
+	coeffs := make([]*bigmod.Nat, len(indices))
+	for k, i := range indices {
+		num := indexScalar(1)
+		den := indexScalar(1)
+		xi := indexScalar(i)
+		for _, j := range indices {
+			if j == i {
+				continue
+			}
+			xj := indexScalar(j)
+			num.Mul(xj, orderNat)
+			den.Mul(bigmod.NewNat().Set(xj).Sub(xi, orderNat), orderNat)
+		}
+		coeffs[k] = num.Mul(inverse(den), orderNat)
+	}
+	return coeffs
+}
+
+// checkIndices reports an error if the indices are not distinct or not in [1, n].
+func checkIndices(indices []int, n int) error {
+	seen := make(map[int]bool, len(indices))
+	for _, i := range indices {
+		if i < 1 || i > n {
+			return errors.New("sm9/threshold: party index out of range")
+		}
+		if seen[i] {
+			return errors.New("sm9/threshold: duplicate party index")
+		}
+		seen[i] = true
+	}
+	return nil
+}
@@ -0,0 +1,351 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package threshold implements a t-of-n threshold SM9 key generation centre (KGC).
+//
+// The master private key is never assembled: n nodes run a Feldman verifiable
+// distributed key generation, every node ends up with a share of the master
+// private key and all the nodes agree on the master public key, which is a
+// normal [sm9.SignMasterPublicKey] or [sm9.EncryptMasterPublicKey].
+//
+// A user private key is extracted by any 2t-1 nodes in an interactive protocol
+// (see [Node.DealUserKey]), every issuing node returns a partial key and the
+// user combines t of them into a normal [sm9.SignPrivateKey] or
+// [sm9.EncryptPrivateKey]. Neither the nodes nor the user learn the master
+// private key.
+//
+// The shares can be refreshed proactively with [DealRefresh] and
+// [Node.Refresh], the master public key and the user keys stay unchanged.
+//
+// The protocol messages carry secret shares, the caller is responsible for
+// delivering [Dealing.Shares] over private and authenticated channels, and for
+// making sure that all the honest nodes use the same set of dealings.
+package threshold
+
+import (
+	"errors"
+	"io"
+
+	"github.com/emmansun/gmsm/internal/bigmod"
+	"github.com/emmansun/gmsm/internal/sm9/bn256"
+	"github.com/emmansun/gmsm/sm9"
+)
+
+// KeyType selects the kind of SM9 master key which is shared.
+type KeyType int
+
+const (
+	// SignKey is the signature master key, its public key is in G2.
+	SignKey KeyType = iota + 1
+	// EncryptKey is the encryption master key, its public key is in G1.
+	EncryptKey
+)
+
+var (
+	errKeyType     = errors.New("sm9/threshold: invalid key type")
+	errThreshold   = errors.New("sm9/threshold: invalid threshold")
+	errInvalidDeal = errors.New("sm9/threshold: share does not match the commitments")
+)
+
+// Dealing is the output of one dealer in a round of verifiable secret sharing.
+//
+// Commitments are the commitments of the dealer's polynomial, they must be
+// broadcast to every node. Shares[j-1] is the secret share of the node with
+// index j, it must be sent to that node only.
+//
+// The master key dealings use Feldman commitments a_k*P. The dealings of a
+// user key extraction use the hiding Pedersen commitments a_k*P + b_k*H, where
+// H is a generator whose discrete logarithm is unknown, and Blindings[j-1] is
+// the share of the blinding polynomial of the node with index j, which must be
+// sent along with Shares[j-1].
+type Dealing struct {
+	Commitments [][]byte
+	Shares      [][]byte
+	Blindings   [][]byte
+}
+
+// Contribution is what the node with the given index receives from a dealer.
+type Contribution struct {
+	Commitments [][]byte
+	Share       []byte
+	Blinding    []byte // nil for Feldman commitments
+}
+
+// Contribution returns the contribution of the dealing for the node with
+// index j (1-based).
+func (d *Dealing) Contribution(j int) *Contribution {
+	c := &Contribution{Commitments: d.Commitments, Share: d.Shares[j-1]}
+	if d.Blindings != nil {
+		c.Blinding = d.Blindings[j-1]
+	}
+	return c
+}
+
+func checkParameters(keyType KeyType, t, n int) error {
+	if keyType != SignKey && keyType != EncryptKey {
+		return errKeyType
+	}
+	if t < 1 || n < t {
+		return errThreshold
+	}
+	return nil
+}
+
+// deal shares secret with a polynomial of the given degree among n parties,
+// committing the coefficients in the group of T.
+func deal[T any, P point[T]](rand io.Reader, secret *bigmod.Nat, degree, n int) (*Dealing, error) {
+	p, err := randomPolynomial(rand, secret, degree)
+	if err != nil {
+		return nil, err
+	}
+	d := &Dealing{Commitments: commit[T, P](p), Shares: make([][]byte, n)}
+	for j := 1; j <= n; j++ {
+		d.Shares[j-1] = p.eval(j).Bytes(orderNat)
+	}
+	return d, nil
+}
+
+// dealPedersen shares secret with a polynomial of the given degree among n
+// parties, with the Pedersen commitments of the coefficients in the group of
+// T. The constant term of the blinding polynomial is blinding.
+func dealPedersen[T any, P point[T]](rand io.Reader, secret, blinding *bigmod.Nat, degree, n int) (*Dealing, error) {
+	p, err := randomPolynomial(rand, secret, degree)
+	if err != nil {
+		return nil, err
+	}
+	b, err := randomPolynomial(rand, blinding, degree)
+	if err != nil {
+		return nil, err
+	}
+	d := &Dealing{Commitments: commitPedersen[T, P](p, b), Shares: make([][]byte, n), Blindings: make([][]byte, n)}
+	for j := 1; j <= n; j++ {
+		d.Shares[j-1] = p.eval(j).Bytes(orderNat)
+		d.Blindings[j-1] = b.eval(j).Bytes(orderNat)
+	}
+	return d, nil
+}
+
+// masterDeal deals secret for the master key of keyType.
+func masterDeal(rand io.Reader, keyType KeyType, secret *bigmod.Nat, t, n int) (*Dealing, error) {
+	if err := checkParameters(keyType, t, n); err != nil {
+		return nil, err
+	}
+	if keyType == SignKey {
+		return deal[bn256.G2](rand, secret, t-1, n)
+	}
+	return deal[bn256.G1](rand, secret, t-1, n)
+}
+
+// Deal is the first round of the distributed key generation, every node deals
+// a random secret with threshold t among n nodes. The master private key is
+// the sum of the secrets of all the dealings accepted by [NewNode].
+func Deal(rand io.Reader, keyType KeyType, t, n int) (*Dealing, error) {
+	secret, err := randomScalar(rand)
+	if err != nil {
+		return nil, err
+	}
+	return masterDeal(rand, keyType, secret, t, n)
+}
+
+// DealRefresh deals a sharing of zero with threshold t among n nodes, it is
+// used to refresh the shares of the master private key, see [Node.Refresh].
+func DealRefresh(rand io.Reader, keyType KeyType, t, n int) (*Dealing, error) {
+	return masterDeal(rand, keyType, zeroScalar(), t, n)
+}
+
+// verifyShare checks the share of party j against the Feldman commitments, or
+// the Pedersen commitments if pedersen is true, and returns the parsed share,
+// blinding share and commitments.
+func verifyShare[T any, P point[T]](c *Contribution, degree, j int, pedersen bool) (share, blinding *bigmod.Nat, cs []P, err error) {
+	if len(c.Commitments) != degree+1 {
+		return nil, nil, nil, errors.New("sm9/threshold: invalid number of commitments")
+	}
+	if cs, err = unmarshalPoints[T, P](c.Commitments); err != nil {
+		return nil, nil, nil, err
+	}
+	if share, err = parseScalar(c.Share); err != nil {
+		return nil, nil, nil, err
+	}
+	want := baseMult[T, P](share)
+	if pedersen {
+		if blinding, err = parseScalar(c.Blinding); err != nil {
+			return nil, nil, nil, err
+		}
+		want = P(new(T)).Add(want, scalarMult[T](pedersenBase[T, P](), blinding))
+	}
+	if !equalPoints[T](want, evalCommitments[T](cs, j)) {
+		return nil, nil, nil, errInvalidDeal
+	}
+	return share, blinding, cs, nil
+}
+
+// combine verifies and sums the contributions for party j, the blinding share
+// is nil unless pedersen is true.
+func combine[T any, P point[T]](contributions []*Contribution, degree, j int, pedersen bool) (share, blinding *bigmod.Nat, commitments []P, err error) {
+	if len(contributions) == 0 {
+		return nil, nil, nil, errors.New("sm9/threshold: no contributions")
+	}
+	share = zeroScalar()
+	if pedersen {
+		blinding = zeroScalar()
+	}
+	for _, c := range contributions {
+		s, b, cs, err := verifyShare[T, P](c, degree, j, pedersen)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		share.Add(s, orderNat)
+		if pedersen {
+			blinding.Add(b, orderNat)
+		}
+		if commitments == nil {
+			commitments = cs
+		} else {
+			addCommitments[T](commitments, cs)
+		}
+	}
+	return share, blinding, commitments, nil
+}
+
+// Node is a member of the threshold KGC, it holds a share of the master
+// private key.
+type Node struct {
+	keyType     KeyType
+	threshold   int
+	parties     int
+	index       int
+	share       *bigmod.Nat
+	commitments [][]byte // joint Feldman commitments of the master key polynomial
+}
+
+// NewNode completes the distributed key generation for the node with index
+// (1-based) out of n nodes with threshold t. The contributions are the ones
+// received from the qualified dealers, every contribution is verified against
+// its commitments and an error is returned if any of them is invalid, in which
+// case the dealer should be disqualified by all the nodes.
+func NewNode(keyType KeyType, t, n, index int, contributions []*Contribution) (*Node, error) {
+	if err := checkParameters(keyType, t, n); err != nil {
+		return nil, err
+	}
+	if err := checkIndices([]int{index}, n); err != nil {
+		return nil, err
+	}
+	node := &Node{keyType: keyType, threshold: t, parties: n, index: index}
+	if err := node.accept(contributions, false); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// accept verifies the contributions and adds them to the share and the
+// commitments of the node. When refresh is true, the contributions must
+// share zero in total.
+func (node *Node) accept(contributions []*Contribution, refresh bool) error {
+	var (
+		share       *bigmod.Nat
+		commitments [][]byte
+		err         error
+	)
+	if node.keyType == SignKey {
+		share, commitments, err = accept[bn256.G2](node, contributions, refresh)
+	} else {
+		share, commitments, err = accept[bn256.G1](node, contributions, refresh)
+	}
+	if err != nil {
+		return err
+	}
+	node.share, node.commitments = share, commitments
+	return nil
+}
+
+func accept[T any, P point[T]](node *Node, contributions []*Contribution, refresh bool) (*bigmod.Nat, [][]byte, error) {
+	share, _, cs, err := combine[T, P](contributions, node.threshold-1, node.index, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	if refresh {
+		// The master public key must be kept.
+		if !isInfinity[T](cs[0]) {
+			return nil, nil, errors.New("sm9/threshold: refresh dealings do not share zero")
+		}
+		old, err := unmarshalPoints[T, P](node.commitments)
+		if err != nil {
+			return nil, nil, err
+		}
+		addCommitments[T](cs, old)
+		share.Add(node.share, orderNat)
+	} else if isInfinity[T](cs[0]) {
+		return nil, nil, errors.New("sm9/threshold: master public key is the point at infinity")
+	}
+	return share, marshalPoints[T](cs), nil
+}
+
+// Refresh updates the share of the node with the contributions of a refresh
+// round, see [DealRefresh]. All the nodes must refresh with the same set of
+// dealings, the shares of different refresh epochs must not be mixed.
+func (node *Node) Refresh(contributions []*Contribution) error {
+	return node.accept(contributions, true)
+}
+
+// KeyType returns the kind of the shared master key.
+func (node *Node) KeyType() KeyType {
+	return node.keyType
+}
+
+// Index returns the index (1-based) of the node.
+func (node *Node) Index() int {
+	return node.index
+}
+
+// Threshold returns the number of nodes whose shares determine the master key.
+func (node *Node) Threshold() int {
+	return node.threshold
+}
+
+// Parties returns the number of nodes.
+func (node *Node) Parties() int {
+	return node.parties
+}
+
+// Commitments returns the joint Feldman commitments of the master key
+// polynomial, they are public and the same for all the nodes.
+func (node *Node) Commitments() [][]byte {
+	return node.commitments
+}
+
+// PublicShare returns the public key of the master key share of the node with
+// index j, encoded in uncompressed form.
+func (node *Node) PublicShare(j int) ([]byte, error) {
+	if err := checkIndices([]int{j}, node.parties); err != nil {
+		return nil, err
+	}
+	if node.keyType == SignKey {
+		return publicShare[bn256.G2](node.commitments, j)
+	}
+	return publicShare[bn256.G1](node.commitments, j)
+}
+
+func publicShare[T any, P point[T]](commitments [][]byte, j int) ([]byte, error) {
+	cs, err := unmarshalPoints[T, P](commitments)
+	if err != nil {
+		return nil, err
+	}
+	return evalCommitments[T](cs, j).MarshalUncompressed(), nil
+}
+
+// SignMasterPublicKey returns the jointly generated signature master public key.
+func (node *Node) SignMasterPublicKey() (*sm9.SignMasterPublicKey, error) {
+	if node.keyType != SignKey {
+		return nil, errKeyType
+	}
+	return sm9.UnmarshalSignMasterPublicKeyRaw(node.commitments[0])
+}
+
+// EncryptMasterPublicKey returns the jointly generated encryption master public key.
+func (node *Node) EncryptMasterPublicKey() (*sm9.EncryptMasterPublicKey, error) {
+	if node.keyType != EncryptKey {
+		return nil, errKeyType
+	}
+	return sm9.UnmarshalEncryptMasterPublicKeyRaw(node.commitments[0])
+}
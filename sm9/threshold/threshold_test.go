@@ -0,0 +1,393 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package threshold_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	sm9internal "github.com/emmansun/gmsm/internal/sm9"
+	"github.com/emmansun/gmsm/sm3"
+	"github.com/emmansun/gmsm/sm9"
+	"github.com/emmansun/gmsm/sm9/bn256"
+	"github.com/emmansun/gmsm/sm9/threshold"
+)
+
+func setup(t *testing.T, keyType threshold.KeyType, th, n int) []*threshold.Node {
+	t.Helper()
+	dealings := make([]*threshold.Dealing, n)
+	for i := range dealings {
+		d, err := threshold.Deal(rand.Reader, keyType, th, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dealings[i] = d
+	}
+	nodes := make([]*threshold.Node, n)
+	for j := 1; j <= n; j++ {
+		var contributions []*threshold.Contribution
+		for _, d := range dealings {
+			contributions = append(contributions, d.Contribution(j))
+		}
+		node, err := threshold.NewNode(keyType, th, n, j, contributions)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nodes[j-1] = node
+	}
+	return nodes
+}
+
+func refresh(t *testing.T, nodes []*threshold.Node) {
+	t.Helper()
+	n, th := nodes[0].Parties(), nodes[0].Threshold()
+	var dealings []*threshold.Dealing
+	for range nodes {
+		d, err := threshold.DealRefresh(rand.Reader, nodes[0].KeyType(), th, n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dealings = append(dealings, d)
+	}
+	for _, node := range nodes {
+		var contributions []*threshold.Contribution
+		for _, d := range dealings {
+			contributions = append(contributions, d.Contribution(node.Index()))
+		}
+		if err := node.Refresh(contributions); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// extract runs the user key extraction with the issuing nodes.
+func extract(t *testing.T, issuers []*threshold.Node, uid []byte, hid byte) []*threshold.PartialKey {
+	t.Helper()
+	_, _, partials := extractTranscript(t, issuers, uid, hid)
+	return partials
+}
+
+// extractTranscript runs the user key extraction with the issuing nodes and
+// returns the broadcast messages with the partial keys.
+func extractTranscript(t *testing.T, issuers []*threshold.Node, uid []byte, hid byte) ([]*threshold.UserKeyDealing, []*threshold.ProductShare, []*threshold.PartialKey) {
+	t.Helper()
+	var dealings []*threshold.UserKeyDealing
+	for _, node := range issuers {
+		d, err := node.DealUserKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dealings = append(dealings, d)
+	}
+	issuances := make([]*threshold.Issuance, len(issuers))
+	products := make([]*threshold.ProductShare, len(issuers))
+	for i, node := range issuers {
+		var contributions []*threshold.UserKeyContribution
+		for _, d := range dealings {
+			contributions = append(contributions, d.Contribution(node.Index()))
+		}
+		is, err := node.NewIssuance(uid, hid, contributions)
+		if err != nil {
+			t.Fatal(err)
+		}
+		issuances[i] = is
+		products[i] = is.ProductShare()
+	}
+	partials := make([]*threshold.PartialKey, len(issuers))
+	for i, is := range issuances {
+		pk, err := is.PartialKey(products)
+		if err != nil {
+			t.Fatal(err)
+		}
+		partials[i] = pk
+	}
+	return dealings, products, partials
+}
+
+func TestThresholdSign(t *testing.T) {
+	const th, n = 3, 5
+	uid := []byte("Alice")
+	hid := byte(0x01)
+	nodes := setup(t, threshold.SignKey, th, n)
+
+	master, err := nodes[0].SignMasterPublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := master.MarshalASN1()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, node := range nodes[1:] {
+		other, err := node.SignMasterPublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		otherDER, _ := other.MarshalASN1()
+		if !bytes.Equal(der, otherDER) {
+			t.Fatal("nodes disagree on the master public key")
+		}
+	}
+	parsed, err := sm9.UnmarshalSignMasterPublicKeyASN1(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Equal(master) {
+		t.Fatal("master public key does not round trip")
+	}
+	if _, err := nodes[0].EncryptMasterPublicKey(); err == nil {
+		t.Fatal("expected key type error")
+	}
+
+	partials := extract(t, nodes[:2*th-1], uid, hid)
+	priv, err := threshold.CombineSignPrivateKey(parsed, uid, hid, []*threshold.PartialKey{partials[1], partials[3], partials[4]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := sm3.Sum([]byte("threshold"))
+	sig, err := priv.Sign(rand.Reader, hash[:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Verify(uid, hid, hash[:], sig) {
+		t.Fatal("signature verification failed")
+	}
+
+	// Any t partial keys give the same key, and refreshing the shares keeps it.
+	other, err := threshold.CombineSignPrivateKey(parsed, uid, hid, partials[:th])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !other.Equal(priv) {
+		t.Fatal("combined keys mismatch")
+	}
+	refresh(t, nodes)
+	refreshed, err := nodes[2].SignMasterPublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !refreshed.Equal(master) {
+		t.Fatal("refresh changed the master public key")
+	}
+	partials = extract(t, nodes[n-(2*th-1):], uid, hid)
+	other, err = threshold.CombineSignPrivateKey(parsed, uid, hid, partials[2:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !other.Equal(priv) {
+		t.Fatal("combined keys mismatch after refresh")
+	}
+
+	if _, err := threshold.CombineSignPrivateKey(parsed, uid, hid, partials[:th-1]); err == nil {
+		t.Fatal("expected error with less than t partial keys")
+	}
+}
+
+func TestThresholdEncrypt(t *testing.T) {
+	const th, n = 2, 3
+	uid := []byte("Bob")
+	hid := byte(0x03)
+	nodes := setup(t, threshold.EncryptKey, th, n)
+	master, err := nodes[1].EncryptMasterPublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	partials := extract(t, nodes, uid, hid)
+	priv, err := threshold.CombineEncryptPrivateKey(master, uid, hid, partials[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext := []byte("threshold key generation centre")
+	ciphertext, err := master.Encrypt(rand.Reader, uid, hid, plaintext, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := priv.DecryptASN1(uid, ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatal("decryption mismatch")
+	}
+
+	partials[0].Point = partials[1].Point
+	if _, err := threshold.CombineEncryptPrivateKey(master, uid, hid, partials[:2]); err == nil {
+		t.Fatal("expected error for an invalid partial key")
+	}
+}
+
+func TestInvalidShares(t *testing.T) {
+	const th, n = 2, 3
+	d1, err := threshold.Deal(rand.Reader, threshold.SignKey, th, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d2, err := threshold.Deal(rand.Reader, threshold.SignKey, th, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bad := d2.Contribution(2)
+	bad.Share = bytes.Clone(bad.Share)
+	bad.Share[31] ^= 1
+	if _, err := threshold.NewNode(threshold.SignKey, th, n, 2, []*threshold.Contribution{d1.Contribution(2), bad}); err == nil {
+		t.Fatal("expected error for an invalid share")
+	}
+	if _, err := threshold.NewNode(threshold.SignKey, th, n, 2, []*threshold.Contribution{d1.Contribution(1)}); err == nil {
+		t.Fatal("expected error for the share of another node")
+	}
+	if _, err := threshold.Deal(rand.Reader, threshold.SignKey, 4, n); err == nil {
+		t.Fatal("expected error for t > n")
+	}
+
+	// A refresh must not change the master key.
+	nodes := setup(t, threshold.SignKey, th, n)
+	d3, err := threshold.Deal(rand.Reader, threshold.SignKey, th, n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := nodes[0].Refresh([]*threshold.Contribution{d3.Contribution(1)}); err == nil {
+		t.Fatal("expected error for a refresh dealing of a non-zero secret")
+	}
+}
+
+func TestInvalidProductShare(t *testing.T) {
+	const th, n = 2, 3
+	uid := []byte("Carol")
+	hid := byte(0x01)
+	nodes := setup(t, threshold.SignKey, th, n)
+	var dealings []*threshold.UserKeyDealing
+	for _, node := range nodes {
+		d, err := node.DealUserKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dealings = append(dealings, d)
+	}
+	// a masking dealing without a valid proof that it shares zero
+	proof := dealings[1].Proof
+	dealings[1].Proof = append([]byte{}, proof...)
+	dealings[1].Proof[len(proof)-1] ^= 1
+	if _, err := nodes[0].NewIssuance(uid, hid, []*threshold.UserKeyContribution{dealings[0].Contribution(1), dealings[1].Contribution(1)}); err == nil {
+		t.Fatal("expected error for an invalid masking dealing")
+	}
+	dealings[1].Proof = proof
+
+	var issuances []*threshold.Issuance
+	var products []*threshold.ProductShare
+	for _, node := range nodes {
+		var contributions []*threshold.UserKeyContribution
+		for _, d := range dealings {
+			contributions = append(contributions, d.Contribution(node.Index()))
+		}
+		is, err := node.NewIssuance(uid, hid, contributions)
+		if err != nil {
+			t.Fatal(err)
+		}
+		issuances = append(issuances, is)
+		products = append(products, is.ProductShare())
+	}
+	for _, ps := range products {
+		if err := issuances[0].VerifyProductShare(ps); err != nil {
+			t.Fatal(err)
+		}
+	}
+	products[2].Blinding[31] ^= 1
+	if err := issuances[0].VerifyProductShare(products[2]); err == nil {
+		t.Fatal("expected error for an invalid blinding")
+	}
+	products[1].Value[31] ^= 1
+	if err := issuances[0].VerifyProductShare(products[1]); err == nil {
+		t.Fatal("expected error for an invalid product share")
+	}
+	if _, err := issuances[0].PartialKey(products); err == nil {
+		t.Fatal("expected error for an invalid product share")
+	}
+	if _, err := issuances[0].PartialKey(products[:2*th-2]); err == nil {
+		t.Fatal("expected error for too few product shares")
+	}
+}
+
+// interpolatedProduct returns u = (h1+s)*r interpolated from the public
+// product shares.
+func interpolatedProduct(products []*threshold.ProductShare) *big.Int {
+	n := new(big.Int).SetBytes(bn256.Order())
+	u := new(big.Int)
+	for _, pi := range products {
+		num, den := big.NewInt(1), big.NewInt(1)
+		for _, pj := range products {
+			if pj.Index != pi.Index {
+				num.Mul(num, big.NewInt(int64(pj.Index)))
+				den.Mul(den, big.NewInt(int64(pj.Index-pi.Index)))
+			}
+		}
+		l := num.Mul(num, den.ModInverse(den.Mod(den, n), n))
+		u.Add(u, l.Mul(l, new(big.Int).SetBytes(pi.Value)))
+	}
+	return u.Mod(u, n)
+}
+
+// TestTranscriptHidesUserKey checks that the user key can't be computed from
+// the broadcast messages: with commitments r*P in the group of the user key,
+// (h1+s)^-1*P = r*P/u and the key is P - h1*(h1+s)^-1*P.
+func TestTranscriptHidesUserKey(t *testing.T) {
+	const th, n = 2, 3
+	uid := []byte("Dave")
+	n256 := new(big.Int).SetBytes(bn256.Order())
+	h1 := new(big.Int).SetBytes(sm9internal.HashUserID(uid, 0x01))
+	scalar := func(k *big.Int) []byte {
+		return new(big.Int).Mod(k, n256).FillBytes(make([]byte, 32))
+	}
+
+	nodes := setup(t, threshold.SignKey, th, n)
+	master, _ := nodes[0].SignMasterPublicKey()
+	dealings, products, partials := extractTranscript(t, nodes, uid, 0x01)
+	priv, err := threshold.CombineSignPrivateKey(master, uid, 0x01, partials)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uInv := new(big.Int).ModInverse(interpolatedProduct(products), n256)
+	r := bn256.NewG1()
+	for _, d := range dealings {
+		c, err := new(bn256.G1).SetBytes(d.R.Commitments[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Add(r, c)
+	}
+	k, _ := new(bn256.G1).ScalarMult(r, scalar(uInv.Mul(uInv, h1)))
+	d := new(bn256.G1).Add(bn256.NewG1Generator(), k.Neg(k))
+	if bytes.Equal(d.Bytes(), priv.Bytes()) {
+		t.Error("the signature user key is computed from the transcript")
+	}
+
+	nodes = setup(t, threshold.EncryptKey, th, n)
+	encMaster, _ := nodes[0].EncryptMasterPublicKey()
+	dealings, products, partials = extractTranscript(t, nodes, uid, 0x01)
+	encPriv, err := threshold.CombineEncryptPrivateKey(encMaster, uid, 0x01, partials)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uInv = new(big.Int).ModInverse(interpolatedProduct(products), n256)
+	r2 := bn256.NewG2()
+	for _, d := range dealings {
+		c, err := new(bn256.G2).SetBytes(d.R.Commitments[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		r2.Add(r2, c)
+	}
+	k2, _ := new(bn256.G2).ScalarMult(r2, scalar(uInv.Mul(uInv, h1)))
+	d2 := new(bn256.G2).Add(bn256.NewG2Generator(), k2.Neg(k2))
+	guess, err := sm9.UnmarshalEncryptPrivateKeyRaw(d2.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	guess.SetMasterPublic(encMaster)
+	if guess.Equal(encPriv) {
+		t.Error("the encryption user key is computed from the transcript")
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package sm9
+
+// SignedItem is a signature to be verified by [VerifyManyASN1] or
+// [FindInvalidSignaturesASN1].
+type SignedItem struct {
+	UID       []byte // signer's identity
+	HID       byte   // signature private key generation function identifier
+	Hash      []byte // the signed message (digest)
+	Signature []byte // ASN.1 encoded SM9Signature
+}
+
+// VerifyManyASN1 reports whether all the signatures in items are valid under
+// the signature master public key pub. It stops at the first invalid signature,
+// use [FindInvalidSignaturesASN1] to identify all the invalid signatures.
+//
+// The signatures are verified one by one, this is not a batch verification
+// which combines the signatures with random coefficients: every SM9 signature
+// needs its own pairing, because the verifier has to recompute the exact value
+// w hashed by the signer. The verification shares the precomputed values of
+// pub, and computes the public key of every identity and the lines of its
+// pairings only once, so it is faster than calling [VerifyASN1] for every item
+// when identities repeat (e.g. a few devices signing many messages).
+func VerifyManyASN1(pub *SignMasterPublicKey, items []SignedItem) bool {
+	v := pub.internal.NewBatchVerifier()
+	for _, item := range items {
+		h, s, err := parseSignature(item.Signature)
+		if err != nil || !v.Verify(item.UID, item.HID, item.Hash, h, s) {
+			return false
+		}
+	}
+	return true
+}
+
+// FindInvalidSignaturesASN1 verifies all the signatures in items under the
+// signature master public key pub, and returns the indices of the invalid ones
+// in ascending order. It returns nil if all the signatures are valid.
+func FindInvalidSignaturesASN1(pub *SignMasterPublicKey, items []SignedItem) []int {
+	var invalid []int
+	v := pub.internal.NewBatchVerifier()
+	for i, item := range items {
+		h, s, err := parseSignature(item.Signature)
+		if err != nil || !v.Verify(item.UID, item.HID, item.Hash, h, s) {
+			invalid = append(invalid, i)
+		}
+	}
+	return invalid
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package sm9_test
+
+import (
+	"crypto/rand"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/emmansun/gmsm/sm9"
+)
+
+func newSignedItems(tb testing.TB, master *sm9.SignMasterPrivateKey, identities, n int) []sm9.SignedItem {
+	hid := byte(0x01)
+	keys := make([]*sm9.SignPrivateKey, identities)
+	for i := range keys {
+		key, err := master.GenerateUserKey([]byte(fmt.Sprintf("device-%d", i)), hid)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		keys[i] = key
+	}
+	items := make([]sm9.SignedItem, n)
+	for i := range items {
+		hash := []byte(fmt.Sprintf("message %d", i))
+		sig, err := sm9.SignASN1(rand.Reader, keys[i%identities], hash)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		items[i] = sm9.SignedItem{
+			UID:       []byte(fmt.Sprintf("device-%d", i%identities)),
+			HID:       hid,
+			Hash:      hash,
+			Signature: sig,
+		}
+	}
+	return items
+}
+
+func TestVerifyManyASN1(t *testing.T) {
+	master, err := sm9.GenerateSignMasterKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := newSignedItems(t, master, 3, 8)
+	pub := master.PublicKey()
+	if !sm9.VerifyManyASN1(pub, items) {
+		t.Fatal("valid signatures rejected")
+	}
+	if invalid := sm9.FindInvalidSignaturesASN1(pub, items); invalid != nil {
+		t.Fatalf("unexpected invalid signatures %v", invalid)
+	}
+	if !sm9.VerifyManyASN1(pub, nil) {
+		t.Error("no signatures rejected")
+	}
+
+	// tamper with some items
+	items[1].Hash = []byte("forged")
+	items[4].UID = []byte("device-2")
+	items[6].Signature = items[6].Signature[:10]
+	if sm9.VerifyManyASN1(pub, items) {
+		t.Fatal("invalid signatures accepted")
+	}
+	if invalid := sm9.FindInvalidSignaturesASN1(pub, items); !slices.Equal(invalid, []int{1, 4, 6}) {
+		t.Fatalf("got invalid signatures %v, want [1 4 6]", invalid)
+	}
+}
+
+func benchmarkVerifyMany(b *testing.B, identities, n int) {
+	master, err := sm9.GenerateSignMasterKey(rand.Reader)
+	if err != nil {
+		b.Fatal(err)
+	}
+	items := newSignedItems(b, master, identities, n)
+	pub := master.PublicKey()
+	b.Run("Many", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if !sm9.VerifyManyASN1(pub, items) {
+				b.Fatal("verify failed")
+			}
+		}
+	})
+	b.Run("Single", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, item := range items {
+				if !sm9.VerifyASN1(pub, item.UID, item.HID, item.Hash, item.Signature) {
+					b.Fatal("verify failed")
+				}
+			}
+		}
+	})
+}
+
+func BenchmarkVerifyMany16Of1(b *testing.B) {
+	benchmarkVerifyMany(b, 1, 16)
+}
+
+func BenchmarkVerifyMany16Of16(b *testing.B) {
+	benchmarkVerifyMany(b, 16, 16)
+}
@@ -0,0 +1,187 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package spake2plus
+
+import (
+	"crypto/hmac"
+	"errors"
+	"io"
+
+	"github.com/emmansun/gmsm/internal/bigmod"
+	"github.com/emmansun/gmsm/internal/ecgroup"
+)
+
+var (
+	errState        = errors.New("spake2plus: invalid protocol state")
+	errConfirmation = errors.New("spake2plus: key confirmation failed")
+)
+
+// session holds the parameters and the keys common to both parties.
+type session struct {
+	suite                         *Suite
+	context, idProver, idVerifier []byte
+	kConfirmP, kConfirmV          []byte
+	kShared                       []byte
+	confirmed                     bool
+}
+
+// keySchedule computes the transcript TT and the keys of RFC 9383, sections
+// 3.3 and 3.4.
+func (ss *session) keySchedule(shareP, shareV []byte, Z, V *ecgroup.Element, w0 *bigmod.Nat) {
+	s := ss.suite
+	tt := appendLengthPrefixed(nil, ss.context)
+	tt = appendLengthPrefixed(tt, ss.idProver)
+	tt = appendLengthPrefixed(tt, ss.idVerifier)
+	tt = appendLengthPrefixed(tt, s.m.Bytes())
+	tt = appendLengthPrefixed(tt, s.nP.Bytes())
+	tt = appendLengthPrefixed(tt, shareP)
+	tt = appendLengthPrefixed(tt, shareV)
+	tt = appendLengthPrefixed(tt, Z.Bytes())
+	tt = appendLengthPrefixed(tt, V.Bytes())
+	tt = appendLengthPrefixed(tt, w0.Bytes(s.n))
+
+	h := s.hash()
+	h.Write(tt)
+	kMain := h.Sum(nil)
+	size := h.Size()
+	confirmationKeys := s.kdf(kMain, "ConfirmationKeys", 2*size)
+	ss.kConfirmP, ss.kConfirmV = confirmationKeys[:size], confirmationKeys[size:]
+	ss.kShared = s.kdf(kMain, "SharedKey", size)
+}
+
+func (ss *session) mac(key, msg []byte) []byte {
+	m := hmac.New(ss.suite.hash, key)
+	m.Write(msg)
+	return m.Sum(nil)
+}
+
+// SharedKey returns the shared key K_shared, once the key confirmation of the
+// peer has been verified.
+func (ss *session) SharedKey() ([]byte, error) {
+	if !ss.confirmed {
+		return nil, errState
+	}
+	return ss.kShared, nil
+}
+
+// Prover is the prover side of a SPAKE2+ session, the party which knows the
+// password. A Prover is used for one session only.
+type Prover struct {
+	session
+	secrets *Secrets
+	x       *bigmod.Nat
+	shareP  []byte
+}
+
+// NewProver returns a prover with the secrets of the password. The context
+// binds the session to the application, the identities are optional and must
+// be the same as the ones of the verifier.
+func (sec *Secrets) NewProver(context, idProver, idVerifier []byte) *Prover {
+	return &Prover{
+		session: session{suite: sec.suite, context: context, idProver: idProver, idVerifier: idVerifier},
+		secrets: sec,
+	}
+}
+
+// Start returns the first message shareP = x*P + w0*M.
+func (p *Prover) Start(rand io.Reader) ([]byte, error) {
+	if p.x != nil {
+		return nil, errState
+	}
+	s := p.suite
+	x, err := s.group.RandomScalar(rand)
+	if err != nil {
+		return nil, err
+	}
+	p.x = x
+	p.shareP = s.group.ScalarBaseMult(x).Add(s.m.ScalarMult(p.secrets.w0)).Bytes()
+	return p.shareP, nil
+}
+
+// Finish processes the message of the verifier, verifies its key
+// confirmation confirmV and returns the key confirmation confirmP to send to
+// the verifier.
+func (p *Prover) Finish(shareV, confirmV []byte) ([]byte, error) {
+	if p.x == nil || p.kShared != nil {
+		return nil, errState
+	}
+	s := p.suite
+	Y, err := s.group.DecodeUncompressed(shareV)
+	if err != nil {
+		return nil, err
+	}
+	// Z = h*x*(Y - w0*N), V = h*w1*(Y - w0*N), the cofactor h is 1
+	T := s.subtractMult(Y, s.nP, p.secrets.w0)
+	Z := T.ScalarMult(p.x)
+	V := T.ScalarMult(p.secrets.w1)
+	if Z.IsIdentity() || V.IsIdentity() {
+		return nil, errors.New("spake2plus: invalid share")
+	}
+	p.keySchedule(p.shareP, shareV, Z, V, p.secrets.w0)
+	if !hmac.Equal(confirmV, p.mac(p.kConfirmV, p.shareP)) {
+		return nil, errConfirmation
+	}
+	p.confirmed = true
+	return p.mac(p.kConfirmP, shareV), nil
+}
+
+// Verifier is the verifier side of a SPAKE2+ session, the party which stores
+// the registration record. A Verifier is used for one session only.
+type Verifier struct {
+	session
+	record    *Record
+	responded bool
+	shareV    []byte
+}
+
+// NewVerifier returns a verifier with the registration record of the prover.
+// The context and the identities must be the same as the ones of the prover.
+func (r *Record) NewVerifier(context, idProver, idVerifier []byte) *Verifier {
+	return &Verifier{
+		session: session{suite: r.suite, context: context, idProver: idProver, idVerifier: idVerifier},
+		record:  r,
+	}
+}
+
+// Respond processes the first message of the prover, and returns the message
+// shareV = y*P + w0*N with the key confirmation confirmV.
+func (v *Verifier) Respond(rand io.Reader, shareP []byte) (shareV, confirmV []byte, err error) {
+	if v.responded {
+		return nil, nil, errState
+	}
+	s := v.suite
+	X, err := s.group.DecodeUncompressed(shareP)
+	if err != nil {
+		return nil, nil, err
+	}
+	y, err := s.group.RandomScalar(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	v.responded = true
+	v.shareV = s.group.ScalarBaseMult(y).Add(s.nP.ScalarMult(v.record.w0)).Bytes()
+	// Z = h*y*(X - w0*M), V = h*y*L, the cofactor h is 1
+	Z := s.subtractMult(X, s.m, v.record.w0).ScalarMult(y)
+	V := v.record.l.ScalarMult(y)
+	if Z.IsIdentity() || V.IsIdentity() {
+		return nil, nil, errors.New("spake2plus: invalid share")
+	}
+	v.keySchedule(shareP, v.shareV, Z, V, v.record.w0)
+	return v.shareV, v.mac(v.kConfirmV, shareP), nil
+}
+
+// Finish verifies the key confirmation confirmP of the prover.
+func (v *Verifier) Finish(confirmP []byte) error {
+	if v.kShared == nil || v.confirmed {
+		return errState
+	}
+	if !hmac.Equal(confirmP, v.mac(v.kConfirmP, v.shareV)) {
+		// the session is aborted, there is no second attempt
+		v.kShared = nil
+		return errConfirmation
+	}
+	v.confirmed = true
+	return nil
+}
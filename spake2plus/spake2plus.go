@@ -0,0 +1,212 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package spake2plus implements the augmented password-authenticated key
+// exchange SPAKE2+ specified in RFC 9383.
+//
+// The protocol is written once over a prime-order elliptic curve group, and
+// instantiated with the ciphersuite SPAKE2+-P256-SHA256-HKDF-SHA256-HMAC-SHA256
+// of RFC 9383, section 4, and the ciphersuite SPAKE2+-SM2-SM3-KDF-SM3-HMAC-SM3
+// defined by this package: the SM2 curve, SM3 as the hash function, the key
+// derivation function of GB/T 32918.4 with SM3, where KDF(nil, ikm, info, L)
+// is KDF(ikm || info, L), and HMAC-SM3 as the MAC. The points M and N of the
+// SM2 suite are generated as in RFC 9382, section 6, with SM3 and the seeds
+// "1.2.156.10197.1.301 point generation seed (M)" and "... (N)".
+//
+// The prover knows the password, the verifier only stores a registration
+// [Record] from which the password can't be recovered without an offline
+// dictionary attack. The protocol has three messages:
+//
+//	Prover                              Verifier
+//	shareP = Prover.Start()        ->
+//	                               <-   shareV, confirmV = Verifier.Respond(shareP)
+//	confirmP = Prover.Finish(shareV, confirmV)
+//	                               ->   Verifier.Finish(confirmP)
+//
+// Each party can read the shared key once it has verified the key confirmation
+// of its peer.
+package spake2plus
+
+import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"hash"
+
+	"github.com/emmansun/gmsm/internal/bigmod"
+	"github.com/emmansun/gmsm/internal/ecgroup"
+	"github.com/emmansun/gmsm/kdf"
+	"github.com/emmansun/gmsm/pkcs"
+	"github.com/emmansun/gmsm/sm3"
+)
+
+// The identifiers of the supported ciphersuites.
+const (
+	SM2SM3     = "SPAKE2+-SM2-SM3-KDF-SM3-HMAC-SM3"
+	P256SHA256 = "SPAKE2+-P256-SHA256-HKDF-SHA256-HMAC-SHA256"
+)
+
+// wsSize is the length in bytes of w0s and w1s, ceil(log2(p) / 8) + k / 8 with
+// k = 64, so that the bias of the reduction modulo the order is negligible.
+const wsSize = 40
+
+// Suite is a SPAKE2+ ciphersuite.
+type Suite struct {
+	id    string
+	group *ecgroup.Group
+	n     *bigmod.Modulus
+	m, nP *ecgroup.Element // the points M and N
+	hash  func() hash.Hash
+	kdf   func(ikm []byte, info string, length int) []byte
+}
+
+var suites = map[string]*Suite{
+	SM2SM3: newSuite(SM2SM3, ecgroup.SM2,
+		"025b4c3e920b9844d30d79fd0e00f6335ac66d92700c8c3b35cb8924aea90c815a",
+		"03375c84cf65a60159b75ffd37478a547dc96a916218a726361fb06862cb0455c9",
+		sm3.New, func(ikm []byte, info string, length int) []byte {
+			return kdf.Kdf(sm3.New, append(append([]byte{}, ikm...), info...), length)
+		}),
+	P256SHA256: newSuite(P256SHA256, ecgroup.P256,
+		"02886e2f97ace46e55ba9dd7242579f2993b64e16ef3dcab95afd497333d8fa12f",
+		"03d8bbd6c639c62937b04d997f38c3770719c629d7014d49a24b4f98baa1292b49",
+		sha256.New, func(ikm []byte, info string, length int) []byte {
+			key, err := hkdf.Key(sha256.New, ikm, nil, info, length)
+			if err != nil {
+				panic("spake2plus: internal error: " + err.Error())
+			}
+			return key
+		}),
+}
+
+func newSuite(id string, group *ecgroup.Group, mHex, nHex string, h func() hash.Hash, kdf func([]byte, string, int) []byte) *Suite {
+	decode := func(s string) *ecgroup.Element {
+		b, _ := hex.DecodeString(s)
+		e, err := group.DecodeCompressed(b)
+		if err != nil {
+			panic("spake2plus: invalid constant point")
+		}
+		return e
+	}
+	return &Suite{id: id, group: group, n: group.Order(), m: decode(mHex), nP: decode(nHex), hash: h, kdf: kdf}
+}
+
+// NewSuite returns the ciphersuite of the identifier id.
+func NewSuite(id string) (*Suite, error) {
+	s, ok := suites[id]
+	if !ok {
+		return nil, errors.New("spake2plus: unsupported suite " + id)
+	}
+	return s, nil
+}
+
+// ID returns the identifier of the suite.
+func (s *Suite) ID() string {
+	return s.id
+}
+
+// subtractMult returns e - k*p.
+func (s *Suite) subtractMult(e, p *ecgroup.Element, k *bigmod.Nat) *ecgroup.Element {
+	negK := bigmod.NewNat().ExpandFor(s.n).Sub(k, s.n)
+	return e.Add(p.ScalarMult(negK))
+}
+
+// appendLengthPrefixed appends the 8-byte little-endian length of b and b.
+func appendLengthPrefixed(dst, b []byte) []byte {
+	return append(binary.LittleEndian.AppendUint64(dst, uint64(len(b))), b...)
+}
+
+// Secrets are the values w0 and w1 derived from the password, held by the
+// prover.
+type Secrets struct {
+	suite  *Suite
+	w0, w1 *bigmod.Nat
+}
+
+// Record is the registration record (w0, L) stored by the verifier.
+type Record struct {
+	suite *Suite
+	w0    *bigmod.Nat
+	l     *ecgroup.Element
+}
+
+// DeriveSecrets derives the secrets of the prover from the password and the
+// identities, as in RFC 9383, section 3.2:
+//
+//	w0s || w1s = PBKDF(len(pw) || pw || len(idProver) || idProver || len(idVerifier) || idVerifier)
+//
+// The PBKDF is given by opts with the salt, for example PBKDF2 with HMAC-SM3
+// ([pkcs.NewSMPBKDF2Opts]) or scrypt ([pkcs.NewScryptOpts]). The salt and the
+// PBKDF parameters are typically stored with the [Record] and sent to the
+// prover before the protocol starts.
+func (s *Suite) DeriveSecrets(opts pkcs.KDFOpts, salt, password, idProver, idVerifier []byte) (*Secrets, error) {
+	input := appendLengthPrefixed(nil, password)
+	input = appendLengthPrefixed(input, idProver)
+	input = appendLengthPrefixed(input, idVerifier)
+	ws, _, err := opts.DeriveKey(input, salt, 2*wsSize)
+	if err != nil {
+		return nil, err
+	}
+	return &Secrets{
+		suite: s,
+		w0:    bigmod.NewNat().SetReducedBytes(ws[:wsSize], s.n),
+		w1:    bigmod.NewNat().SetReducedBytes(ws[wsSize:], s.n),
+	}, nil
+}
+
+func (s *Suite) parseScalar(b []byte) (*bigmod.Nat, error) {
+	if len(b) != s.n.Size() {
+		return nil, errors.New("spake2plus: invalid scalar length")
+	}
+	k, err := bigmod.NewNat().SetBytes(b, s.n)
+	if err != nil {
+		return nil, errors.New("spake2plus: invalid scalar")
+	}
+	return k, nil
+}
+
+// NewSecrets returns the secrets of the 32-byte big-endian encodings of w0
+// and w1.
+func (s *Suite) NewSecrets(w0, w1 []byte) (*Secrets, error) {
+	k0, err := s.parseScalar(w0)
+	if err != nil {
+		return nil, err
+	}
+	k1, err := s.parseScalar(w1)
+	if err != nil {
+		return nil, err
+	}
+	return &Secrets{suite: s, w0: k0, w1: k1}, nil
+}
+
+// Record returns the registration record (w0, L = w1*P) of the secrets.
+func (sec *Secrets) Record() *Record {
+	return &Record{suite: sec.suite, w0: sec.w0, l: sec.suite.group.ScalarBaseMult(sec.w1)}
+}
+
+// NewRecord returns the record of the 32-byte big-endian encoding of w0 and
+// the uncompressed encoding of L.
+func (s *Suite) NewRecord(w0, l []byte) (*Record, error) {
+	k0, err := s.parseScalar(w0)
+	if err != nil {
+		return nil, err
+	}
+	e, err := s.group.DecodeUncompressed(l)
+	if err != nil {
+		return nil, err
+	}
+	return &Record{suite: s, w0: k0, l: e}, nil
+}
+
+// W0 returns the 32-byte big-endian encoding of w0.
+func (r *Record) W0() []byte {
+	return r.w0.Bytes(r.suite.n)
+}
+
+// L returns the uncompressed encoding of L.
+func (r *Record) L() []byte {
+	return r.l.Bytes()
+}
@@ -0,0 +1,319 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package spake2plus
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"testing"
+
+	"github.com/emmansun/gmsm/internal/ecgroup"
+	"github.com/emmansun/gmsm/pkcs"
+	"github.com/emmansun/gmsm/sm3"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// The P256SHA256 vector is the one of RFC 9383, appendix C. The SM2SM3 vector
+// runs the same inputs through the SM2 suite and records its transcript.
+var vectors = []struct {
+	suite                           string
+	context                         string
+	w0, w1, l, x, shareP, y, shareV string
+	confirmP, confirmV, kShared     string
+}{
+	{
+		suite:    P256SHA256,
+		context:  "SPAKE2+-P256-SHA256-HKDF-SHA256-HMAC-SHA256 Test Vectors",
+		w0:       "bb8e1bbcf3c48f62c08db243652ae55d3e5586053fca77102994f23ad95491b3",
+		w1:       "7e945f34d78785b8a3ef44d0df5a1a97d6b3b460409a345ca7830387a74b1dba",
+		l:        "04eb7c9db3d9a9eb1f8adab81b5794c1f13ae3e225efbe91ea487425854c7fc00f00bfedcbd09b2400142d40a14f2064ef31dfaa903b91d1faea7093d835966efd",
+		x:        "d1232c8e8693d02368976c174e2088851b8365d0d79a9eee709c6a05a2fad539",
+		shareP:   "04ef3bd051bf78a2234ec0df197f7828060fe9856503579bb1733009042c15c0c1de127727f418b5966afadfdd95a6e4591d171056b333dab97a79c7193e341727",
+		y:        "717a72348a182085109c8d3917d6c43d59b224dc6a7fc4f0483232fa6516d8b3",
+		shareV:   "04c0f65da0d11927bdf5d560c69e1d7d939a05b0e88291887d679fcadea75810fb5cc1ca7494db39e82ff2f50665255d76173e09986ab46742c798a9a68437b048",
+		confirmP: "926cc713504b9b4d76c9162ded04b5493e89109f6d89462cd33adc46fda27527",
+		confirmV: "9747bcc4f8fe9f63defee53ac9b07876d907d55047e6ff2def2e7529089d3e68",
+		kShared:  "0c5f8ccd1413423a54f6c1fb26ff01534a87f893779c6e68666d772bfd91f3e7",
+	},
+	{
+		suite:    SM2SM3,
+		context:  "SPAKE2+-SM2-SM3-KDF-SM3-HMAC-SM3 Test Vectors",
+		w0:       "bb8e1bbcf3c48f62c08db243652ae55d3e5586053fca77102994f23ad95491b3",
+		w1:       "7e945f34d78785b8a3ef44d0df5a1a97d6b3b460409a345ca7830387a74b1dba",
+		l:        "04401e1d416a72bbfad5854c9157ce3d8a702febd757364daa06e6cde3cb60b268a6070b2856bd01a0fb2ddb2441d20c1711dffa22752e71f1cf4e4a0fd8e1bd53",
+		x:        "d1232c8e8693d02368976c174e2088851b8365d0d79a9eee709c6a05a2fad539",
+		shareP:   "048186a594f412a9017ea7576d59bf63e7dced3ef3b6a00e506b3de4523e9c5d5c3c2a84de15bf82042c4b026c273ba40c97f4c1a602c0848d8862265285cc1ec0",
+		y:        "717a72348a182085109c8d3917d6c43d59b224dc6a7fc4f0483232fa6516d8b3",
+		shareV:   "04ad105735bb629f30a0af5dfd32f81375b39e6a3ce5ccd36aa5c5515cfab8f96861f6d3cd2736a53d225d1c01c344108ce42b46d22e0832d566485a49e5ad84ef",
+		confirmP: "45c7e4ab546be9755fc00b98222c155d368102f3269c6e0738af031eceb01375",
+		confirmV: "105a3910ca58445e262488e039b1c6318a8051df50632aafef37a690ce130f21",
+		kShared:  "e04fbb42898338e8f2c39b5fa7d833635aaecc8874101692ab56435e52018733",
+	},
+}
+
+func TestVectors(t *testing.T) {
+	for _, v := range vectors {
+		s, err := NewSuite(v.suite)
+		if err != nil {
+			t.Fatal(err)
+		}
+		secrets, err := s.NewSecrets(decodeHex(t, v.w0), decodeHex(t, v.w1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		record := secrets.Record()
+		if got := hex.EncodeToString(record.L()); got != v.l {
+			t.Fatalf("%s: L = %s, want %s", v.suite, got, v.l)
+		}
+		context := []byte(v.context)
+		prover := secrets.NewProver(context, []byte("client"), []byte("server"))
+		verifier := record.NewVerifier(context, []byte("client"), []byte("server"))
+
+		shareP, err := prover.Start(bytes.NewReader(decodeHex(t, v.x)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(shareP); got != v.shareP {
+			t.Errorf("%s: shareP = %s, want %s", v.suite, got, v.shareP)
+		}
+		shareV, confirmV, err := verifier.Respond(bytes.NewReader(decodeHex(t, v.y)), shareP)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(shareV); got != v.shareV {
+			t.Errorf("%s: shareV = %s, want %s", v.suite, got, v.shareV)
+		}
+		if got := hex.EncodeToString(confirmV); got != v.confirmV {
+			t.Errorf("%s: confirmV = %s, want %s", v.suite, got, v.confirmV)
+		}
+		if _, err := verifier.SharedKey(); err == nil {
+			t.Fatal("expected error for the shared key before confirmation")
+		}
+		confirmP, err := prover.Finish(shareV, confirmV)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(confirmP); got != v.confirmP {
+			t.Errorf("%s: confirmP = %s, want %s", v.suite, got, v.confirmP)
+		}
+		if err := verifier.Finish(confirmP); err != nil {
+			t.Fatal(err)
+		}
+		for _, ss := range []interface{ SharedKey() ([]byte, error) }{prover, verifier} {
+			key, err := ss.SharedKey()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(key); got != v.kShared {
+				t.Errorf("%s: K_shared = %s, want %s", v.suite, got, v.kShared)
+			}
+		}
+	}
+}
+
+// iteratedHash and generatePoint follow the generation of M and N of RFC 9382,
+// section 6, for compressed points.
+func iteratedHash(h func() hash.Hash, seed []byte, n int) []byte {
+	out := seed
+	for i := 0; i < n; i++ {
+		d := h()
+		d.Write(out)
+		out = d.Sum(nil)
+	}
+	return out
+}
+
+func generatePoint(g *ecgroup.Group, h func() hash.Hash, seed string) []byte {
+	size := len(g.Generator().BytesCompressed())
+	for i := 1; i < 1000; i++ {
+		var b []byte
+		for j := i; len(b) < size; j++ {
+			b = append(b, iteratedHash(h, []byte(seed), j)...)
+		}
+		b = b[:size]
+		b[0] = b[0]&1 | 2
+		if _, err := g.DecodeCompressed(b); err == nil {
+			return b
+		}
+	}
+	return nil
+}
+
+func TestConstants(t *testing.T) {
+	for _, tc := range []struct {
+		suite string
+		oid   string
+		hash  func() hash.Hash
+	}{
+		{P256SHA256, "1.2.840.10045.3.1.7", sha256.New},
+		{SM2SM3, "1.2.156.10197.1.301", sm3.New},
+	} {
+		s, _ := NewSuite(tc.suite)
+		m := generatePoint(s.group, tc.hash, tc.oid+" point generation seed (M)")
+		n := generatePoint(s.group, tc.hash, tc.oid+" point generation seed (N)")
+		if !bytes.Equal(m, s.m.BytesCompressed()) {
+			t.Errorf("%s: M mismatch", tc.suite)
+		}
+		if !bytes.Equal(n, s.nP.BytesCompressed()) {
+			t.Errorf("%s: N mismatch", tc.suite)
+		}
+	}
+}
+
+func run(t *testing.T, secrets *Secrets, record *Record) ([]byte, error) {
+	t.Helper()
+	context := []byte("commissioning")
+	prover := secrets.NewProver(context, nil, []byte("device"))
+	verifier := record.NewVerifier(context, nil, []byte("device"))
+	shareP, err := prover.Start(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	shareV, confirmV, err := verifier.Respond(rand.Reader, shareP)
+	if err != nil {
+		return nil, err
+	}
+	confirmP, err := prover.Finish(shareV, confirmV)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifier.Finish(confirmP); err != nil {
+		return nil, err
+	}
+	k1, _ := prover.SharedKey()
+	k2, _ := verifier.SharedKey()
+	if !bytes.Equal(k1, k2) {
+		t.Fatal("shared keys mismatch")
+	}
+	return k1, nil
+}
+
+func TestRegistration(t *testing.T) {
+	salt := []byte("0123456789abcdef")
+	for _, id := range []string{SM2SM3, P256SHA256} {
+		s, _ := NewSuite(id)
+		for _, opts := range []pkcs.KDFOpts{
+			pkcs.NewSMPBKDF2Opts(16, 1000),
+			pkcs.NewScryptOpts(16, 1024, 8, 1),
+		} {
+			secrets, err := s.DeriveSecrets(opts, salt, []byte("20202021"), nil, []byte("device"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			record, err := s.NewRecord(secrets.Record().W0(), secrets.Record().L())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := run(t, secrets, record); err != nil {
+				t.Fatalf("%s: %v", id, err)
+			}
+			wrong, _ := s.DeriveSecrets(opts, salt, []byte("20202022"), nil, []byte("device"))
+			if _, err := run(t, wrong, record); err != errConfirmation {
+				t.Fatalf("%s: expected confirmation error for a wrong password, got %v", id, err)
+			}
+		}
+	}
+}
+
+func TestProtocolErrors(t *testing.T) {
+	s, _ := NewSuite(SM2SM3)
+	secrets, _ := s.DeriveSecrets(pkcs.NewSMPBKDF2Opts(16, 1000), []byte("salt"), []byte("password"), nil, nil)
+	record := secrets.Record()
+
+	prover := secrets.NewProver(nil, nil, nil)
+	if _, err := prover.Finish(nil, nil); err != errState {
+		t.Fatal("expected state error for Finish before Start")
+	}
+	shareP, _ := prover.Start(rand.Reader)
+	if _, err := prover.Start(rand.Reader); err != errState {
+		t.Fatal("expected state error for a second Start")
+	}
+	verifier := record.NewVerifier(nil, nil, nil)
+	if err := verifier.Finish(nil); err != errState {
+		t.Fatal("expected state error for Finish before Respond")
+	}
+	bad := bytes.Clone(shareP)
+	bad[64] ^= 1
+	if _, _, err := verifier.Respond(rand.Reader, bad); err == nil {
+		t.Fatal("expected error for an invalid share")
+	}
+	shareV, confirmV, err := verifier.Respond(rand.Reader, shareP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := verifier.Respond(rand.Reader, shareP); err != errState {
+		t.Fatal("expected state error for a second Respond")
+	}
+	badConfirm := bytes.Clone(confirmV)
+	badConfirm[0] ^= 1
+	if _, err := prover.Finish(shareV, badConfirm); err != errConfirmation {
+		t.Fatal("expected confirmation error")
+	}
+	if _, err := prover.SharedKey(); err != errState {
+		t.Fatal("expected state error for the shared key of an aborted session")
+	}
+
+	prover = secrets.NewProver(nil, nil, nil)
+	verifier = record.NewVerifier(nil, nil, nil)
+	shareP, _ = prover.Start(rand.Reader)
+	shareV, confirmV, _ = verifier.Respond(rand.Reader, shareP)
+	confirmP, _ := prover.Finish(shareV, confirmV)
+	badConfirm = bytes.Clone(confirmP)
+	badConfirm[0] ^= 1
+	if err := verifier.Finish(badConfirm); err != errConfirmation {
+		t.Fatal("expected confirmation error")
+	}
+	if err := verifier.Finish(confirmP); err != errState {
+		t.Fatal("expected state error for a second attempt")
+	}
+
+	// a verifier with the identities of another session doesn't confirm
+	prover = secrets.NewProver([]byte("a"), nil, nil)
+	verifier = record.NewVerifier([]byte("b"), nil, nil)
+	shareP, _ = prover.Start(rand.Reader)
+	shareV, confirmV, _ = verifier.Respond(rand.Reader, shareP)
+	if _, err := prover.Finish(shareV, confirmV); err != errConfirmation {
+		t.Fatal("expected confirmation error for another context")
+	}
+
+	if _, err := s.NewRecord(record.W0(), make([]byte, 65)); err == nil {
+		t.Fatal("expected error for an invalid L")
+	}
+	if _, err := s.NewSecrets(make([]byte, 31), make([]byte, 32)); err == nil {
+		t.Fatal("expected error for a short w0")
+	}
+	if _, err := NewSuite("SPAKE2+-P384-SHA256-HKDF-SHA256-HMAC-SHA256"); err == nil {
+		t.Fatal("expected error for an unsupported suite")
+	}
+}
+
+func BenchmarkSession(b *testing.B) {
+	s, _ := NewSuite(SM2SM3)
+	secrets, _ := s.NewSecrets(bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32))
+	record := secrets.Record()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		prover := secrets.NewProver(nil, nil, nil)
+		verifier := record.NewVerifier(nil, nil, nil)
+		shareP, _ := prover.Start(rand.Reader)
+		shareV, confirmV, _ := verifier.Respond(rand.Reader, shareP)
+		confirmP, _ := prover.Finish(shareV, confirmV)
+		if err := verifier.Finish(confirmP); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
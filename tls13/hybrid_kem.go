@@ -64,6 +64,7 @@ type ClassicalKeyPair interface {
 type classicalKEX interface {
 	publicKeySize() int
 	generateKeyPair(rand io.Reader) (ClassicalKeyPair, error)
+	newKeyPair(priv []byte) (ClassicalKeyPair, error)
 	serverECDH(rand io.Reader, clientPubBytes []byte) (sharedSecret, serverPubBytes []byte, err error)
 }
 
@@ -109,6 +110,41 @@ func newMLKEM768EncapKey(b []byte) (mlkemEncapKey, error) {
 	return &encapKey768{ek}, nil
 }
 
+// NewKeySharePrivateKeys returns the private keys of a client key share of the
+// named group from the encoding of the classical private key, as accepted by
+// NewPrivateKey of crypto/ecdh, and the 64-byte ML-KEM seed of the hybrid
+// groups, which must be nil for the pure ECDH groups. It lets the protocols
+// built on the key exchanges, such as HPKE or a generic KEM, store the private
+// keys.
+func NewKeySharePrivateKeys(id CurveID, classicalKey, mlkemSeed []byte) (*KeySharePrivateKeys, error) {
+	ke, err := NewKeyExchange(id)
+	if err != nil {
+		return nil, err
+	}
+	switch ke := ke.(type) {
+	case *ecdhKEX:
+		if mlkemSeed != nil {
+			return nil, errors.New("tls13: ML-KEM seed with a pure ECDH named group")
+		}
+		kp, err := ke.classical.newKeyPair(classicalKey)
+		if err != nil {
+			return nil, err
+		}
+		return &KeySharePrivateKeys{ECDHE: kp}, nil
+	case *hybridKEX:
+		kp, err := ke.ecdh.classical.newKeyPair(classicalKey)
+		if err != nil {
+			return nil, err
+		}
+		dk, err := ke.newMLKEMDecapKey(mlkemSeed)
+		if err != nil {
+			return nil, err
+		}
+		return &KeySharePrivateKeys{ECDHE: kp, MLKEM: dk}, nil
+	}
+	panic("tls13: internal error: unexpected key exchange")
+}
+
 // NewKeyExchange returns a [KeyExchange] for the given named group, or an error
 // if the named group is not supported.
 func NewKeyExchange(id CurveID) (KeyExchange, error) {
@@ -246,6 +282,14 @@ func (s *stdlibCurveKEX) generateKeyPair(rand io.Reader) (ClassicalKeyPair, erro
 	return &stdlibKeyPair{curve: s.curve, priv: priv}, nil
 }
 
+func (s *stdlibCurveKEX) newKeyPair(b []byte) (ClassicalKeyPair, error) {
+	priv, err := s.curve.NewPrivateKey(b)
+	if err != nil {
+		return nil, err
+	}
+	return &stdlibKeyPair{curve: s.curve, priv: priv}, nil
+}
+
 func (s *stdlibCurveKEX) serverECDH(rand io.Reader, clientPubBytes []byte) ([]byte, []byte, error) {
 	serverPriv, err := s.curve.GenerateKey(rand)
 	if err != nil {
@@ -294,6 +338,14 @@ func (s *sm2CurveKEX) generateKeyPair(rand io.Reader) (ClassicalKeyPair, error)
 	return &sm2KeyPair{curve: s.curve, priv: priv}, nil
 }
 
+func (s *sm2CurveKEX) newKeyPair(b []byte) (ClassicalKeyPair, error) {
+	priv, err := s.curve.NewPrivateKey(b)
+	if err != nil {
+		return nil, err
+	}
+	return &sm2KeyPair{curve: s.curve, priv: priv}, nil
+}
+
 func (s *sm2CurveKEX) serverECDH(rand io.Reader, clientPubBytes []byte) ([]byte, []byte, error) {
 	serverPriv, err := s.curve.GenerateKey(rand)
 	if err != nil {
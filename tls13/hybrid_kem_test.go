@@ -150,6 +150,67 @@ func TestHybridKeySharesIncludeFallback(t *testing.T) {
 	}
 }
 
+func TestNewKeySharePrivateKeys(t *testing.T) {
+	tests := []struct {
+		id         tls13.CurveID
+		keySize    int
+		withMLKEM  bool
+		mlkemFirst bool
+	}{
+		{tls13.CurveSM2, 32, false, false},
+		{tls13.CurveX25519, 32, false, false},
+		{tls13.X25519MLKEM768, 32, true, true},
+		{tls13.SecP256r1MLKEM768, 32, true, false},
+		{tls13.SecP384r1MLKEM1024, 48, true, false},
+		{tls13.SM2MLKEM768, 32, true, false},
+	}
+	for _, tc := range tests {
+		key := bytes.Repeat([]byte{0x42}, tc.keySize)
+		var seed []byte
+		if tc.withMLKEM {
+			seed = bytes.Repeat([]byte{0x24}, 64)
+		}
+		priv, err := tls13.NewKeySharePrivateKeys(tc.id, key, seed)
+		if err != nil {
+			t.Fatalf("%v: %v", tc.id, err)
+		}
+		share := priv.ECDHE.PublicKeyBytes()
+		if tc.withMLKEM {
+			ek := priv.MLKEM.EncapsulationKeyBytes()
+			if tc.mlkemFirst {
+				share = append(ek, share...)
+			} else {
+				share = append(share, ek...)
+			}
+		}
+		ke, _ := tls13.NewKeyExchange(tc.id)
+		serverSecret, serverShare, err := ke.ServerSharedSecret(rand.Reader, share)
+		if err != nil {
+			t.Fatalf("%v: ServerSharedSecret: %v", tc.id, err)
+		}
+		clientSecret, err := ke.ClientSharedSecret(priv, serverShare.Data)
+		if err != nil {
+			t.Fatalf("%v: ClientSharedSecret: %v", tc.id, err)
+		}
+		if !bytes.Equal(clientSecret, serverSecret) {
+			t.Errorf("%v: shared secrets do not match", tc.id)
+		}
+	}
+
+	if _, err := tls13.NewKeySharePrivateKeys(tls13.CurveID(0xFFFF), make([]byte, 32), nil); err == nil {
+		t.Error("expected error for unsupported named group")
+	}
+	if _, err := tls13.NewKeySharePrivateKeys(tls13.CurveSM2, make([]byte, 32), nil); err == nil {
+		t.Error("expected error for a zero private key")
+	}
+	if _, err := tls13.NewKeySharePrivateKeys(tls13.CurveSM2, bytes.Repeat([]byte{1}, 32), make([]byte, 64)); err == nil {
+		t.Error("expected error for an ML-KEM seed with a pure ECDH group")
+	}
+	if _, err := tls13.NewKeySharePrivateKeys(tls13.SM2MLKEM768, bytes.Repeat([]byte{1}, 32), make([]byte, 32)); err == nil {
+		t.Error("expected error for a short ML-KEM seed")
+	}
+}
+
 func TestHybridKeyExchangeUnsupported(t *testing.T) {
 	_, err := tls13.NewKeyExchange(tls13.CurveID(0xFFFF))
 	if err == nil {
@@ -0,0 +1,164 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package xwing
+
+import (
+	"errors"
+	"io"
+
+	"github.com/emmansun/gmsm/ecdh"
+	"github.com/emmansun/gmsm/kdf"
+	"github.com/emmansun/gmsm/mlkem"
+	"github.com/emmansun/gmsm/sm2/sm2ec"
+	"github.com/emmansun/gmsm/sm3"
+)
+
+const (
+	// EncapsulationKeySizeSM2 is the size of an SM2-Wing encapsulation key.
+	EncapsulationKeySizeSM2 = mlkem.EncapsulationKeySize768 + sm2PublicKeySize
+	// CiphertextSizeSM2 is the size of an SM2-Wing ciphertext.
+	CiphertextSizeSM2 = mlkem.CiphertextSize768 + sm2PublicKeySize
+
+	sm2PublicKeySize = 65
+	sm2UniformSize   = 48
+)
+
+// A DecapsulationKeySM2 is an SM2-Wing decapsulation key, which must be kept
+// secret.
+type DecapsulationKeySM2 struct {
+	seed [SeedSize]byte
+	m    *mlkem.DecapsulationKey768
+	s    *ecdh.PrivateKey
+}
+
+// An EncapsulationKeySM2 is an SM2-Wing encapsulation key.
+type EncapsulationKeySM2 struct {
+	m *mlkem.EncapsulationKey768
+	s *ecdh.PublicKey
+}
+
+// GenerateKeySM2 generates a new SM2-Wing decapsulation key.
+func GenerateKeySM2(rand io.Reader) (*DecapsulationKeySM2, error) {
+	var seed [SeedSize]byte
+	if _, err := io.ReadFull(rand, seed[:]); err != nil {
+		return nil, err
+	}
+	return NewDecapsulationKeyFromSeedSM2(seed[:])
+}
+
+// NewDecapsulationKeyFromSeedSM2 returns the SM2-Wing decapsulation key of a
+// 32-byte seed, which must be uniformly random. It returns an error, with a
+// negligible probability, if the derived SM2 private key is invalid.
+func NewDecapsulationKeyFromSeedSM2(seed []byte) (*DecapsulationKeySM2, error) {
+	if len(seed) != SeedSize {
+		return nil, errors.New("xwing: invalid seed length")
+	}
+	expanded := kdf.Kdf(sm3.New, seed, mlkem.SeedSize+sm2UniformSize)
+	dk := &DecapsulationKeySM2{}
+	copy(dk.seed[:], seed)
+	var err error
+	if dk.m, err = mlkem.NewDecapsulationKeyFromSeed768(expanded[:mlkem.SeedSize]); err != nil {
+		return nil, err
+	}
+	d, err := sm2ec.NewScalar().SetUniformBytes(expanded[mlkem.SeedSize:])
+	if err != nil {
+		return nil, err
+	}
+	if dk.s, err = ecdh.P256().NewPrivateKey(d.Bytes()); err != nil {
+		return nil, err
+	}
+	return dk, nil
+}
+
+// Bytes returns the decapsulation key as its 32-byte seed.
+func (dk *DecapsulationKeySM2) Bytes() []byte {
+	return append([]byte{}, dk.seed[:]...)
+}
+
+// EncapsulationKey returns the encapsulation key of the decapsulation key.
+func (dk *DecapsulationKeySM2) EncapsulationKey() *EncapsulationKeySM2 {
+	return &EncapsulationKeySM2{m: dk.m.EncapsulationKey(), s: dk.s.PublicKey()}
+}
+
+// Decapsulate returns the shared key encapsulated in the ciphertext. As with
+// ML-KEM, a ciphertext which was not produced for this key gives a
+// random-looking shared key, but an invalid SM2 point is rejected.
+func (dk *DecapsulationKeySM2) Decapsulate(ciphertext []byte) (sharedKey []byte, err error) {
+	if len(ciphertext) != CiphertextSizeSM2 {
+		return nil, errors.New("xwing: invalid ciphertext length")
+	}
+	ctM, ctS := ciphertext[:mlkem.CiphertextSize768], ciphertext[mlkem.CiphertextSize768:]
+	ssM, err := dk.m.Decapsulate(ctM)
+	if err != nil {
+		return nil, err
+	}
+	peer, err := ecdh.P256().NewPublicKey(ctS)
+	if err != nil {
+		return nil, err
+	}
+	ssS, err := dk.s.ECDH(peer)
+	if err != nil {
+		return nil, err
+	}
+	return combineSM2(ssM, ssS, ctS, dk.s.PublicKey().Bytes()), nil
+}
+
+// NewEncapsulationKeySM2 parses an SM2-Wing encapsulation key, ML-KEM-768
+// encapsulation key || uncompressed SM2 public key.
+func NewEncapsulationKeySM2(encapsulationKey []byte) (*EncapsulationKeySM2, error) {
+	if len(encapsulationKey) != EncapsulationKeySizeSM2 {
+		return nil, errors.New("xwing: invalid encapsulation key length")
+	}
+	m, err := mlkem.NewEncapsulationKey768(encapsulationKey[:mlkem.EncapsulationKeySize768])
+	if err != nil {
+		return nil, err
+	}
+	s, err := ecdh.P256().NewPublicKey(encapsulationKey[mlkem.EncapsulationKeySize768:])
+	if err != nil {
+		return nil, err
+	}
+	return &EncapsulationKeySM2{m: m, s: s}, nil
+}
+
+// Bytes returns the encapsulation key as a byte slice.
+func (ek *EncapsulationKeySM2) Bytes() []byte {
+	return append(ek.m.Bytes(), ek.s.Bytes()...)
+}
+
+// Encapsulate generates a shared key and its ciphertext for the encapsulation
+// key. The shared key must be kept secret.
+func (ek *EncapsulationKeySM2) Encapsulate(rand io.Reader) (sharedKey, ciphertext []byte, err error) {
+	var m [32]byte
+	if _, err := io.ReadFull(rand, m[:]); err != nil {
+		return nil, nil, err
+	}
+	ephemeral, err := ecdh.P256().GenerateKey(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ek.encapsulate(&m, ephemeral)
+}
+
+// encapsulate is the derandomized Encapsulate, with the ML-KEM-768 message m
+// and the SM2 ephemeral private key.
+func (ek *EncapsulationKeySM2) encapsulate(m *[32]byte, ephemeral *ecdh.PrivateKey) (sharedKey, ciphertext []byte, err error) {
+	ssS, err := ephemeral.ECDH(ek.s)
+	if err != nil {
+		return nil, nil, err
+	}
+	ssM, ctM := ek.m.EncapsulateInternal(m)
+	ctS := ephemeral.PublicKey().Bytes()
+	return combineSM2(ssM, ssS, ctS, ek.s.Bytes()), append(ctM, ctS...), nil
+}
+
+func combineSM2(ssM, ssS, ctS, pkS []byte) []byte {
+	h := sm3.New()
+	h.Write(ssM)
+	h.Write(ssS)
+	h.Write(ctS)
+	h.Write(pkS)
+	h.Write(label)
+	return h.Sum(nil)
+}
@@ -0,0 +1,161 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package xwing
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+
+	"github.com/emmansun/gmsm/ecdh"
+	"github.com/emmansun/gmsm/kdf"
+	"github.com/emmansun/gmsm/mlkem"
+	"github.com/emmansun/gmsm/sm3"
+)
+
+func sm3Hex(b []byte) string {
+	h := sm3.Sum(b)
+	return hex.EncodeToString(h[:])
+}
+
+// TestSM2Vector checks SM2-Wing against its components: the ML-KEM-768 and
+// SM2 keys derived from the expanded seed, and the combiner over their shared
+// secrets. SM2-Wing has no published vectors, so the encodings are pinned.
+func TestSM2Vector(t *testing.T) {
+	seed := make([]byte, SeedSize)
+	var m [32]byte
+	for i := range seed {
+		seed[i] = byte(i)
+		m[i] = byte(0x20 + i)
+	}
+	ephemeral, err := ecdh.P256().NewPrivateKey(bytes.Repeat([]byte{0x42}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dk, err := NewDecapsulationKeyFromSeedSM2(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ek := dk.EncapsulationKey()
+	ss, ct, err := ek.encapsulate(&m, ephemeral)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expanded := kdf.Kdf(sm3.New, seed, 112)
+	dkM, err := mlkem.NewDecapsulationKeyFromSeed768(expanded[:64])
+	if err != nil {
+		t.Fatal(err)
+	}
+	pk := ek.Bytes()
+	if !bytes.Equal(pk[:mlkem.EncapsulationKeySize768], dkM.EncapsulationKey().Bytes()) {
+		t.Error("ML-KEM-768 encapsulation key mismatch")
+	}
+	ssM, ctM := dkM.EncapsulationKey().EncapsulateInternal(&m)
+	if !bytes.Equal(ct[:mlkem.CiphertextSize768], ctM) {
+		t.Error("ML-KEM-768 ciphertext mismatch")
+	}
+	ctS, pkS := ct[mlkem.CiphertextSize768:], pk[mlkem.EncapsulationKeySize768:]
+	// the SM2 public key of the expanded seed, recorded from this package
+	if got := hex.EncodeToString(pkS); got != "04ecacdc2b399d593a3c9f48454b759c8fc2f8926b1da1cb779e4be8b875873769c6acc6f1dc6e141454d4ea44998d324a606aada432975f5504b1cfe2683ab7b9" {
+		t.Errorf("SM2 public key = %s", got)
+	}
+	if !bytes.Equal(ctS, ephemeral.PublicKey().Bytes()) {
+		t.Error("SM2 ciphertext mismatch")
+	}
+	peer, err := ecdh.P256().NewPublicKey(pkS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ssS, err := ephemeral.ECDH(peer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := sm3.Sum(bytes.Join([][]byte{ssM, ssS, ctS, pkS, []byte(`\.//^\`)}, nil))
+	if !bytes.Equal(ss, want[:]) {
+		t.Errorf("ss = %x, want %x", ss, want)
+	}
+
+	// the encodings of this implementation
+	for _, tc := range []struct{ name, got, want string }{
+		{"SM3(pk)", sm3Hex(pk), "de700af81ed264367e6346d4a36476357caed491024412117ad2e4d8249588b8"},
+		{"SM3(ct)", sm3Hex(ct), "059e1bde5c2fd59179024c98035d6e2ae095a3c5aeee9bbd5ac43351dfba8b95"},
+		{"ss", hex.EncodeToString(ss), "0896779f1b56f1172383b5e29f17ea8987bd854123d9002d2bf54fc8c25237bb"},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s = %s, want %s", tc.name, tc.got, tc.want)
+		}
+	}
+	got, err := dk.Decapsulate(ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, ss) {
+		t.Errorf("decapsulated %x, want %x", got, ss)
+	}
+}
+
+func TestSM2RoundTrip(t *testing.T) {
+	dk, err := GenerateKeySM2(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ek, err := NewEncapsulationKeySM2(dk.EncapsulationKey().Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ek.Bytes()) != EncapsulationKeySizeSM2 {
+		t.Errorf("encapsulation key size = %d", len(ek.Bytes()))
+	}
+	ss, ct, err := ek.Encapsulate(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ss) != SharedKeySize || len(ct) != CiphertextSizeSM2 {
+		t.Errorf("shared key size = %d, ciphertext size = %d", len(ss), len(ct))
+	}
+	dk, err = NewDecapsulationKeyFromSeedSM2(dk.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := dk.Decapsulate(ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, ss) {
+		t.Errorf("decapsulated %x, want %x", got, ss)
+	}
+	ct[0] ^= 1
+	got, err = dk.Decapsulate(ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(got, ss) {
+		t.Error("same shared key with a modified ciphertext")
+	}
+}
+
+func BenchmarkEncapsulateSM2(b *testing.B) {
+	dk, _ := GenerateKeySM2(rand.Reader)
+	ek := dk.EncapsulationKey()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := ek.Encapsulate(rand.Reader); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecapsulateSM2(b *testing.B) {
+	dk, _ := GenerateKeySM2(rand.Reader)
+	_, ct, _ := dk.EncapsulationKey().Encapsulate(rand.Reader)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := dk.Decapsulate(ct); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package xwing implements the X-Wing hybrid KEM of
+// draft-connolly-cfrg-xwing-kem, the combination of X25519 and ML-KEM-768, and
+// SM2-Wing, its analogue with the ECDH over the SM2 curve and SM3.
+//
+// The shared key of a hybrid KEM is secure as long as one of its components
+// is. Unlike the TLS 1.3 hybrid groups of package tls13, the keys, the
+// ciphertexts and the shared keys are those of a plain KEM, for the file
+// encryption, CMS or HPKE.
+//
+// SM2-Wing is not standardized. It mirrors X-Wing:
+//
+//   - the decapsulation key is a 32-byte seed, expanded to 112 bytes with the
+//     SM3 KDF of GB/T 32918.4; the first 64 bytes are the ML-KEM-768 seed and
+//     the last 48 bytes, reduced modulo the order of the curve, the SM2
+//     private key;
+//   - the encapsulation key is the ML-KEM-768 encapsulation key || the
+//     uncompressed SM2 public key, and the ciphertext the ML-KEM-768
+//     ciphertext || the uncompressed SM2 ephemeral public key;
+//   - the shared key is SM3(ss_M || ss_S || ct_S || pk_S || label), where ss_S
+//     is the x-coordinate of the ECDH shared point, and label is the X-Wing
+//     label.
+package xwing
+
+import (
+	"crypto/ecdh"
+	"crypto/sha3"
+	"errors"
+	"io"
+
+	"github.com/emmansun/gmsm/mlkem"
+)
+
+const (
+	// SeedSize is the size of a decapsulation key seed.
+	SeedSize = 32
+	// SharedKeySize is the size of a shared key.
+	SharedKeySize = 32
+
+	// EncapsulationKeySize is the size of an X-Wing encapsulation key.
+	EncapsulationKeySize = mlkem.EncapsulationKeySize768 + x25519Size
+	// CiphertextSize is the size of an X-Wing ciphertext.
+	CiphertextSize = mlkem.CiphertextSize768 + x25519Size
+
+	x25519Size = 32
+	// encapsulationSeedSize is the size of the randomness of Encapsulate.
+	encapsulationSeedSize = 64
+)
+
+// label is the X-Wing label, `\.//^\`.
+var label = []byte{0x5c, 0x2e, 0x2f, 0x2f, 0x5e, 0x5c}
+
+// A DecapsulationKey is an X-Wing decapsulation key, which must be kept
+// secret.
+type DecapsulationKey struct {
+	seed [SeedSize]byte
+	m    *mlkem.DecapsulationKey768
+	x    *ecdh.PrivateKey
+}
+
+// An EncapsulationKey is an X-Wing encapsulation key.
+type EncapsulationKey struct {
+	m *mlkem.EncapsulationKey768
+	x *ecdh.PublicKey
+}
+
+// GenerateKey generates a new decapsulation key.
+func GenerateKey(rand io.Reader) (*DecapsulationKey, error) {
+	var seed [SeedSize]byte
+	if _, err := io.ReadFull(rand, seed[:]); err != nil {
+		return nil, err
+	}
+	return NewDecapsulationKeyFromSeed(seed[:])
+}
+
+// NewDecapsulationKeyFromSeed returns the decapsulation key of a 32-byte
+// seed, which must be uniformly random.
+func NewDecapsulationKeyFromSeed(seed []byte) (*DecapsulationKey, error) {
+	if len(seed) != SeedSize {
+		return nil, errors.New("xwing: invalid seed length")
+	}
+	expanded := sha3.SumSHAKE256(seed, mlkem.SeedSize+x25519Size)
+	dk := &DecapsulationKey{}
+	copy(dk.seed[:], seed)
+	var err error
+	if dk.m, err = mlkem.NewDecapsulationKeyFromSeed768(expanded[:mlkem.SeedSize]); err != nil {
+		return nil, err
+	}
+	if dk.x, err = ecdh.X25519().NewPrivateKey(expanded[mlkem.SeedSize:]); err != nil {
+		return nil, err
+	}
+	return dk, nil
+}
+
+// Bytes returns the decapsulation key as its 32-byte seed.
+func (dk *DecapsulationKey) Bytes() []byte {
+	return append([]byte{}, dk.seed[:]...)
+}
+
+// EncapsulationKey returns the encapsulation key of the decapsulation key.
+func (dk *DecapsulationKey) EncapsulationKey() *EncapsulationKey {
+	return &EncapsulationKey{m: dk.m.EncapsulationKey(), x: dk.x.PublicKey()}
+}
+
+// Decapsulate returns the shared key encapsulated in the ciphertext. As with
+// ML-KEM, a ciphertext which was not produced for this key gives a
+// random-looking shared key, but an X25519 component of low order is
+// rejected.
+func (dk *DecapsulationKey) Decapsulate(ciphertext []byte) (sharedKey []byte, err error) {
+	if len(ciphertext) != CiphertextSize {
+		return nil, errors.New("xwing: invalid ciphertext length")
+	}
+	ctM, ctX := ciphertext[:mlkem.CiphertextSize768], ciphertext[mlkem.CiphertextSize768:]
+	ssM, err := dk.m.Decapsulate(ctM)
+	if err != nil {
+		return nil, err
+	}
+	peer, err := ecdh.X25519().NewPublicKey(ctX)
+	if err != nil {
+		return nil, err
+	}
+	ssX, err := dk.x.ECDH(peer)
+	if err != nil {
+		return nil, err
+	}
+	return combine(ssM, ssX, ctX, dk.x.PublicKey().Bytes()), nil
+}
+
+// NewEncapsulationKey parses an encapsulation key, ML-KEM-768 encapsulation
+// key || X25519 public key.
+func NewEncapsulationKey(encapsulationKey []byte) (*EncapsulationKey, error) {
+	if len(encapsulationKey) != EncapsulationKeySize {
+		return nil, errors.New("xwing: invalid encapsulation key length")
+	}
+	m, err := mlkem.NewEncapsulationKey768(encapsulationKey[:mlkem.EncapsulationKeySize768])
+	if err != nil {
+		return nil, err
+	}
+	x, err := ecdh.X25519().NewPublicKey(encapsulationKey[mlkem.EncapsulationKeySize768:])
+	if err != nil {
+		return nil, err
+	}
+	return &EncapsulationKey{m: m, x: x}, nil
+}
+
+// Bytes returns the encapsulation key as a byte slice.
+func (ek *EncapsulationKey) Bytes() []byte {
+	return append(ek.m.Bytes(), ek.x.Bytes()...)
+}
+
+// Encapsulate generates a shared key and its ciphertext for the encapsulation
+// key. The shared key must be kept secret.
+func (ek *EncapsulationKey) Encapsulate(rand io.Reader) (sharedKey, ciphertext []byte, err error) {
+	var eseed [encapsulationSeedSize]byte
+	if _, err := io.ReadFull(rand, eseed[:]); err != nil {
+		return nil, nil, err
+	}
+	return ek.encapsulate(&eseed)
+}
+
+// encapsulate is the derandomized Encapsulate of the draft: the first 32
+// bytes of eseed are the ML-KEM-768 message and the last 32 the X25519
+// ephemeral private key.
+func (ek *EncapsulationKey) encapsulate(eseed *[encapsulationSeedSize]byte) (sharedKey, ciphertext []byte, err error) {
+	ephemeral, err := ecdh.X25519().NewPrivateKey(eseed[32:])
+	if err != nil {
+		return nil, nil, err
+	}
+	ssX, err := ephemeral.ECDH(ek.x)
+	if err != nil {
+		return nil, nil, err
+	}
+	ssM, ctM := ek.m.EncapsulateInternal((*[32]byte)(eseed[:32]))
+	ctX := ephemeral.PublicKey().Bytes()
+	return combine(ssM, ssX, ctX, ek.x.Bytes()), append(ctM, ctX...), nil
+}
+
+func combine(ssM, ssX, ctX, pkX []byte) []byte {
+	h := sha3.New256()
+	h.Write(ssM)
+	h.Write(ssX)
+	h.Write(ctX)
+	h.Write(pkX)
+	h.Write(label)
+	return h.Sum(nil)
+}
@@ -0,0 +1,164 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package xwing
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha3"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func writeHex(w io.Writer, prefix string, val []byte) {
+	const indent, width = "  ", 74
+	s := hex.EncodeToString(val)
+	if len(prefix)+len(s)+5 < width {
+		fmt.Fprintf(w, "%s     %s\n", prefix, s)
+		return
+	}
+	fmt.Fprintf(w, "%s\n", prefix)
+	for len(s) > 0 {
+		n := min(len(s), width-len(indent))
+		fmt.Fprintf(w, "%s%s\n", indent, s[:n])
+		s = s[n:]
+	}
+}
+
+// TestVectors regenerates spec/test-vectors.txt of
+// https://github.com/dconnolly/draft-connolly-cfrg-xwing-kem, whose seeds are
+// read from SHAKE128 of the empty string, and checks its SHAKE128 digest.
+func TestVectors(t *testing.T) {
+	h := sha3.NewSHAKE128()
+	w := new(bytes.Buffer)
+	for i := 0; i < 3; i++ {
+		seed := make([]byte, SeedSize)
+		h.Read(seed)
+		writeHex(w, "seed", seed)
+		dk, err := NewDecapsulationKeyFromSeed(seed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ek := dk.EncapsulationKey()
+		writeHex(w, "sk", dk.Bytes())
+		writeHex(w, "pk", ek.Bytes())
+
+		var eseed [encapsulationSeedSize]byte
+		h.Read(eseed[:])
+		writeHex(w, "eseed", eseed[:])
+		ss, ct, err := ek.encapsulate(&eseed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writeHex(w, "ct", ct)
+		writeHex(w, "ss", ss)
+		got, err := dk.Decapsulate(ct)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, ss) {
+			t.Fatalf("vector %d: decapsulated %x, want %x", i, got, ss)
+		}
+		fmt.Fprintf(w, "\n")
+	}
+	digest := sha3.SumSHAKE128(w.Bytes(), 32)
+	if got, want := hex.EncodeToString(digest), "1bcd0057d861d6b866239936cadcaeee1ec0164dedc181c386e9e54fe46156fe"; got != want {
+		t.Errorf("digest of the test vectors = %s, want %s\n%s", got, want, w)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	dk, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ek, err := NewEncapsulationKey(dk.EncapsulationKey().Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ek.Bytes()) != EncapsulationKeySize {
+		t.Errorf("encapsulation key size = %d", len(ek.Bytes()))
+	}
+	ss, ct, err := ek.Encapsulate(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ss) != SharedKeySize || len(ct) != CiphertextSize {
+		t.Errorf("shared key size = %d, ciphertext size = %d", len(ss), len(ct))
+	}
+	dk, err = NewDecapsulationKeyFromSeed(dk.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := dk.Decapsulate(ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, ss) {
+		t.Errorf("decapsulated %x, want %x", got, ss)
+	}
+	// implicit rejection of a modified ML-KEM ciphertext
+	ct[0] ^= 1
+	got, err = dk.Decapsulate(ct)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(got, ss) {
+		t.Error("same shared key with a modified ciphertext")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, err := NewDecapsulationKeyFromSeed(make([]byte, 31)); err == nil {
+		t.Error("expected error with a short seed")
+	}
+	if _, err := NewDecapsulationKeyFromSeedSM2(make([]byte, 64)); err == nil {
+		t.Error("expected error with a long seed")
+	}
+	if _, err := NewEncapsulationKey(make([]byte, EncapsulationKeySizeSM2)); err == nil {
+		t.Error("expected error with an SM2-Wing encapsulation key")
+	}
+	dk, _ := GenerateKey(rand.Reader)
+	if _, err := dk.Decapsulate(make([]byte, CiphertextSizeSM2)); err == nil {
+		t.Error("expected error with an SM2-Wing ciphertext")
+	}
+	// the X25519 component is the low-order point 0
+	if _, err := dk.Decapsulate(make([]byte, CiphertextSize)); err == nil {
+		t.Error("expected error with a low-order X25519 point")
+	}
+	dkSM2, _ := GenerateKeySM2(rand.Reader)
+	if _, err := dkSM2.Decapsulate(make([]byte, CiphertextSizeSM2)); err == nil {
+		t.Error("expected error with an invalid SM2 point")
+	}
+	b := dkSM2.EncapsulationKey().Bytes()
+	b[len(b)-1] ^= 1
+	if _, err := NewEncapsulationKeySM2(b); err == nil {
+		t.Error("expected error with an SM2 point not on the curve")
+	}
+}
+
+func BenchmarkEncapsulate(b *testing.B) {
+	dk, _ := GenerateKey(rand.Reader)
+	ek := dk.EncapsulationKey()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := ek.Encapsulate(rand.Reader); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecapsulate(b *testing.B) {
+	dk, _ := GenerateKey(rand.Reader)
+	_, ct, _ := dk.EncapsulationKey().Encapsulate(rand.Reader)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := dk.Decapsulate(ct); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
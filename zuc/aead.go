@@ -0,0 +1,164 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package zuc
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"errors"
+	"hash"
+
+	"github.com/emmansun/gmsm/internal/alias"
+	"github.com/emmansun/gmsm/internal/byteorder"
+	"github.com/emmansun/gmsm/internal/zuc"
+)
+
+const (
+	// TagSize128 is the tag size in bytes of the ZUC-128 AEAD.
+	TagSize128 = 4
+	// TagSize256 is the default tag size in bytes of the ZUC-256 AEAD.
+	TagSize256 = 16
+)
+
+var errOpen = errors.New("zuc: message authentication failed")
+
+type zucAEAD struct {
+	key     []byte
+	tagSize int
+}
+
+// NewAEAD returns a [cipher.AEAD] which combines the ZUC keystream (EEA) with
+// the ZUC MAC (EIA) in an encrypt-then-MAC construction.
+//
+// The key must be 16 bytes long (ZUC-128) or 32 bytes long (ZUC-256). The nonce
+// size is [IVSize128] or [IVSize256] accordingly. For ZUC-128 the tagSize must
+// be [TagSize128]; for ZUC-256 the tagSize must be 4, 8 or 16, the tag sizes
+// supported by [NewHash256].
+//
+// For every message, the ZUC keystream is generated from the key and nonce:
+//
+//   - the first len(key) bytes of the keystream are the MAC key,
+//   - the following keystream bytes are XORed with the plaintext.
+//
+// The tag is the ZUC MAC (with the MAC key and the same nonce as IV) over
+//
+//	AAD || pad16(AAD) || C || pad16(C) || uint64(bitlen(AAD)) || uint64(bitlen(C))
+//
+// where pad16 is zero padding to a multiple of 16 bytes and the lengths are
+// encoded as big-endian 64-bit integers. The sealed output is C || tag.
+//
+// A nonce must never be reused with the same key.
+func NewAEAD(key []byte, tagSize int) (cipher.AEAD, error) {
+	switch len(key) {
+	case 16:
+		if tagSize != TagSize128 {
+			return nil, errors.New("zuc: invalid tag size for ZUC-128 AEAD")
+		}
+	case 32:
+		if tagSize != 4 && tagSize != 8 && tagSize != 16 {
+			return nil, errors.New("zuc: invalid tag size for ZUC-256 AEAD")
+		}
+	default:
+		return nil, errors.New("zuc: invalid key size for AEAD")
+	}
+	c := &zucAEAD{tagSize: tagSize}
+	c.key = append(c.key, key...)
+	return c, nil
+}
+
+func (c *zucAEAD) NonceSize() int {
+	if len(c.key) == 16 {
+		return IVSize128
+	}
+	return IVSize256
+}
+
+func (c *zucAEAD) Overhead() int {
+	return c.tagSize
+}
+
+// setup derives the MAC and the keystream positioned right after the MAC key.
+func (c *zucAEAD) setup(nonce []byte) (cipher.Stream, hash.Hash) {
+	stream, err := zuc.NewCipher(c.key, nonce)
+	if err != nil {
+		panic(err)
+	}
+	macKey := make([]byte, len(c.key))
+	stream.XORKeyStream(macKey, macKey)
+
+	var mac hash.Hash
+	if len(c.key) == 16 {
+		mac, err = zuc.NewHash(macKey, nonce)
+	} else {
+		mac, err = zuc.NewHash256(macKey, nonce, c.tagSize)
+	}
+	if err != nil {
+		panic(err)
+	}
+	clear(macKey)
+	return stream, mac
+}
+
+func (c *zucAEAD) auth(out []byte, mac hash.Hash, ciphertext, additionalData []byte) {
+	var pad [16]byte
+	mac.Write(additionalData)
+	if r := len(additionalData) % 16; r != 0 {
+		mac.Write(pad[r:])
+	}
+	mac.Write(ciphertext)
+	if r := len(ciphertext) % 16; r != 0 {
+		mac.Write(pad[r:])
+	}
+	byteorder.BEPutUint64(pad[:8], uint64(len(additionalData))*8)
+	byteorder.BEPutUint64(pad[8:], uint64(len(ciphertext))*8)
+	mac.Write(pad[:])
+	copy(out, mac.Sum(nil))
+}
+
+func (c *zucAEAD) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != c.NonceSize() {
+		panic("zuc: incorrect nonce length given to AEAD")
+	}
+	ret, out := alias.SliceForAppend(dst, len(plaintext)+c.tagSize)
+	if alias.InexactOverlap(out, plaintext) {
+		panic("zuc: invalid buffer overlap of output and input")
+	}
+	if alias.AnyOverlap(out, additionalData) {
+		panic("zuc: invalid buffer overlap of output and additional data")
+	}
+
+	stream, mac := c.setup(nonce)
+	stream.XORKeyStream(out, plaintext)
+	c.auth(out[len(plaintext):], mac, out[:len(plaintext)], additionalData)
+	return ret
+}
+
+func (c *zucAEAD) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != c.NonceSize() {
+		panic("zuc: incorrect nonce length given to AEAD")
+	}
+	if len(ciphertext) < c.tagSize {
+		return nil, errOpen
+	}
+	tag := ciphertext[len(ciphertext)-c.tagSize:]
+	ciphertext = ciphertext[:len(ciphertext)-c.tagSize]
+
+	ret, out := alias.SliceForAppend(dst, len(ciphertext))
+	if alias.InexactOverlap(out, ciphertext) {
+		panic("zuc: invalid buffer overlap of output and input")
+	}
+	if alias.AnyOverlap(out, additionalData) {
+		panic("zuc: invalid buffer overlap of output and additional data")
+	}
+
+	stream, mac := c.setup(nonce)
+	var expectedTag [TagSize256]byte
+	c.auth(expectedTag[:], mac, ciphertext, additionalData)
+	if subtle.ConstantTimeCompare(expectedTag[:c.tagSize], tag) != 1 {
+		return nil, errOpen
+	}
+	stream.XORKeyStream(out, ciphertext)
+	return ret, nil
+}
@@ -0,0 +1,174 @@
+package zuc
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/emmansun/gmsm/internal/byteorder"
+	"github.com/emmansun/gmsm/internal/cryptotest"
+)
+
+// sealReference computes the AEAD output with the existing EEA/EIA primitives.
+func sealReference(t *testing.T, key, nonce, plaintext, additionalData []byte, tagSize int) []byte {
+	stream, err := NewCipher(key, nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyStream := make([]byte, len(key)+len(plaintext))
+	stream.XORKeyStream(keyStream, keyStream)
+	macKey := keyStream[:len(key)]
+	ciphertext := make([]byte, len(plaintext))
+	for i := range plaintext {
+		ciphertext[i] = plaintext[i] ^ keyStream[len(key)+i]
+	}
+
+	var mac EIA
+	if len(key) == 16 {
+		mac, err = NewHash(macKey, nonce)
+	} else {
+		mac, err = NewHash256(macKey, nonce, tagSize)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var macInput []byte
+	macInput = append(macInput, additionalData...)
+	macInput = append(macInput, make([]byte, (16-len(additionalData)%16)%16)...)
+	macInput = append(macInput, ciphertext...)
+	macInput = append(macInput, make([]byte, (16-len(ciphertext)%16)%16)...)
+	macInput = byteorder.BEAppendUint64(macInput, uint64(len(additionalData))*8)
+	macInput = byteorder.BEAppendUint64(macInput, uint64(len(ciphertext))*8)
+	mac.Write(macInput)
+	return mac.Sum(ciphertext)
+}
+
+func TestAEADReference(t *testing.T) {
+	cases := []struct {
+		keySize int
+		tagSize int
+	}{
+		{16, 4},
+		{32, 4},
+		{32, 8},
+		{32, 16},
+	}
+	for _, tc := range cases {
+		key := make([]byte, tc.keySize)
+		for i := range key {
+			key[i] = byte(i)
+		}
+		aead, err := NewAEAD(key, tc.tagSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nonce := make([]byte, aead.NonceSize())
+		for i := range nonce {
+			nonce[i] = byte(0x80 + i)
+		}
+		for _, ptLen := range []int{0, 1, 15, 16, 17, 100} {
+			for _, adLen := range []int{0, 3, 16, 33} {
+				plaintext := bytes.Repeat([]byte{0x5a}, ptLen)
+				additionalData := bytes.Repeat([]byte{0xa5}, adLen)
+				want := sealReference(t, key, nonce, plaintext, additionalData, tc.tagSize)
+				got := aead.Seal(nil, nonce, plaintext, additionalData)
+				if !bytes.Equal(got, want) {
+					t.Errorf("ZUC-%d/tag %d, pt %d, ad %d: got %x, want %x", tc.keySize*8, tc.tagSize, ptLen, adLen, got, want)
+				}
+			}
+		}
+	}
+}
+
+var aeadTests = []struct {
+	key        string
+	nonce      string
+	tagSize    int
+	plaintext  string
+	ad         string
+	ciphertext string
+}{
+	{
+		"000102030405060708090a0b0c0d0e0f",
+		"808182838485868788898a8b8c8d8e8f",
+		4,
+		"ZUC authenticated encryption",
+		"header",
+		"b99090f58d07f995062a920d918f585b8be16baae130b9e5acc9604b0cd55a19",
+	},
+	{
+		"000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f",
+		"808182838485868788898a8b8c8d8e8f90919293949596",
+		16,
+		"ZUC authenticated encryption",
+		"header",
+		"34aab900b90a58f1b022ce100951c0a44a244ccfa3c5a8b9d89cb1105a0adf8b68c80270c3df5abb30521d92",
+	},
+}
+
+func TestAEADVector(t *testing.T) {
+	for i, tt := range aeadTests {
+		key, _ := hex.DecodeString(tt.key)
+		nonce, _ := hex.DecodeString(tt.nonce)
+		want, _ := hex.DecodeString(tt.ciphertext)
+		aead, err := NewAEAD(key, tt.tagSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := aead.Seal(nil, nonce, []byte(tt.plaintext), []byte(tt.ad))
+		if !bytes.Equal(got, want) {
+			t.Fatalf("case %d: got %x, want %x", i, got, want)
+		}
+		opened, err := aead.Open(nil, nonce, got, []byte(tt.ad))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(opened) != tt.plaintext {
+			t.Fatalf("case %d: got %q, want %q", i, opened, tt.plaintext)
+		}
+		// tamper the ciphertext, tag and additional data
+		for j := range got {
+			tampered := bytes.Clone(got)
+			tampered[j] ^= 1
+			if _, err := aead.Open(nil, nonce, tampered, []byte(tt.ad)); err == nil {
+				t.Fatalf("case %d: tampered byte %d should fail", i, j)
+			}
+		}
+		if _, err := aead.Open(nil, nonce, got, []byte("Header")); err == nil {
+			t.Fatalf("case %d: tampered additional data should fail", i)
+		}
+	}
+}
+
+func TestAEADInvalidParameters(t *testing.T) {
+	cases := []struct {
+		keySize int
+		tagSize int
+	}{
+		{16, 8},
+		{16, 16},
+		{32, 12},
+		{32, 0},
+		{24, 16},
+	}
+	for _, tc := range cases {
+		if _, err := NewAEAD(make([]byte, tc.keySize), tc.tagSize); err == nil {
+			t.Errorf("key size %d, tag size %d: expected error", tc.keySize, tc.tagSize)
+		}
+	}
+}
+
+func TestAEADGeneric(t *testing.T) {
+	for _, tc := range []struct {
+		keySize int
+		tagSize int
+	}{{16, 4}, {32, 4}, {32, 8}, {32, 16}} {
+		t.Run(fmt.Sprintf("ZUC-%d/Tag-%d", tc.keySize*8, tc.tagSize), func(t *testing.T) {
+			cryptotest.TestAEAD(t, func() (cipher.AEAD, error) {
+				return NewAEAD(make([]byte, tc.keySize), tc.tagSize)
+			})
+		})
+	}
+}
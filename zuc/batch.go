@@ -0,0 +1,43 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package zuc
+
+import "github.com/emmansun/gmsm/internal/zuc"
+
+// XORKeyStreamBatch XORs every src[i] with the keystream of the ZUC instance
+// created from keys[i] and ivs[i] (starting from the first keystream byte),
+// and writes the result to dst[i]. ZUC-128 and ZUC-256 instances can be mixed.
+//
+// The instances are run in lockstep, several at a time, using SIMD lanes where
+// available; the result is the same as calling [NewCipher] for each instance.
+func XORKeyStreamBatch(keys, ivs, dst, src [][]byte) error {
+	return zuc.XORKeyStreamBatch(keys, ivs, dst, src)
+}
+
+// EEAXORKeyStreamBatch is like [XORKeyStreamBatch], but the ZUC-128 IVs are
+// constructed from counts, bearers and directions as in [NewEEACipher].
+func EEAXORKeyStreamBatch(keys [][]byte, counts, bearers, directions []uint32, dst, src [][]byte) error {
+	return zuc.EEAXORKeyStreamBatch(keys, counts, bearers, directions, dst, src)
+}
+
+// MACBatch computes the ZUC-128 MAC of msgs[i] with keys[i] and ivs[i] for
+// every i, the result is the same as [NewHash] for each instance.
+func MACBatch(keys, ivs, msgs [][]byte) ([][]byte, error) {
+	return zuc.MACBatch(keys, ivs, msgs, bitLengths(msgs))
+}
+
+// EIAMACBatch is like [MACBatch], but the ZUC-128 IVs are constructed from
+// counts, bearers and directions as in [NewEIAHash].
+func EIAMACBatch(keys [][]byte, counts, bearers, directions []uint32, msgs [][]byte) ([][]byte, error) {
+	return zuc.EIAMACBatch(keys, counts, bearers, directions, msgs, bitLengths(msgs))
+}
+
+func bitLengths(msgs [][]byte) []int {
+	nbits := make([]int, len(msgs))
+	for i, msg := range msgs {
+		nbits[i] = len(msg) * 8
+	}
+	return nbits
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package zuc
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestXORKeyStreamBatch(t *testing.T) {
+	n := 10
+	keys := make([][]byte, n)
+	ivs := make([][]byte, n)
+	src := make([][]byte, n)
+	dst := make([][]byte, n)
+	for i := range n {
+		keys[i] = bytes.Repeat([]byte{byte(i)}, 16)
+		ivs[i] = bytes.Repeat([]byte{byte(0x80 + i)}, IVSize128)
+		src[i] = bytes.Repeat([]byte{0x5a}, i*100+3)
+		dst[i] = make([]byte, len(src[i]))
+	}
+	if err := XORKeyStreamBatch(keys, ivs, dst, src); err != nil {
+		t.Fatal(err)
+	}
+	for i := range n {
+		c, err := NewCipher(keys[i], ivs[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := make([]byte, len(src[i]))
+		c.XORKeyStream(want, src[i])
+		if !bytes.Equal(dst[i], want) {
+			t.Errorf("instance %d mismatch", i)
+		}
+	}
+}
+
+func TestEEAXORKeyStreamAndEIAMACBatch(t *testing.T) {
+	n := 12
+	keys := make([][]byte, n)
+	counts := make([]uint32, n)
+	bearers := make([]uint32, n)
+	directions := make([]uint32, n)
+	src := make([][]byte, n)
+	dst := make([][]byte, n)
+	for i := range n {
+		keys[i] = bytes.Repeat([]byte{byte(i * 3)}, 16)
+		counts[i] = uint32(i * 1000)
+		bearers[i] = uint32(i % 32)
+		directions[i] = uint32(i % 2)
+		src[i] = bytes.Repeat([]byte{byte(i)}, i*37)
+		dst[i] = make([]byte, len(src[i]))
+	}
+	if err := EEAXORKeyStreamBatch(keys, counts, bearers, directions, dst, src); err != nil {
+		t.Fatal(err)
+	}
+	macs, err := EIAMACBatch(keys, counts, bearers, directions, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range n {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			c, err := NewEEACipher(keys[i], counts[i], bearers[i], directions[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := make([]byte, len(src[i]))
+			c.XORKeyStream(want, src[i])
+			if !bytes.Equal(dst[i], want) {
+				t.Fatal("EEA mismatch")
+			}
+			h, err := NewEIAHash(keys[i], counts[i], bearers[i], directions[i])
+			if err != nil {
+				t.Fatal(err)
+			}
+			h.Write(want)
+			if mac := h.Sum(nil); !bytes.Equal(macs[i], mac) {
+				t.Fatalf("EIA got %x, want %x", macs[i], mac)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package zuc
+
+import (
+	"errors"
+
+	"github.com/emmansun/gmsm/internal/zuc"
+)
+
+// EEA3 encrypts or decrypts the first nbits bits of src with the 128-EEA3
+// algorithm (3GPP TS 35.221) and writes the result to dst.
+//
+// Both src and dst must be at least (nbits+7)/8 bytes long, bits are numbered
+// from the most significant bit of the first byte. The unused trailing bits of
+// the last output byte are set to zero.
+func EEA3(key []byte, count, bearer, direction uint32, dst, src []byte, nbits int) error {
+	c, err := zuc.NewEEACipher(key, count, bearer, direction)
+	if err != nil {
+		return err
+	}
+	return zuc.XORKeyStreamBits(c, dst, src, nbits)
+}
+
+// EIA3 computes the 32-bit 128-EIA3 MAC (3GPP TS 35.221) of the first nbits
+// bits of msg, which must be at least (nbits+7)/8 bytes long.
+func EIA3(key []byte, count, bearer, direction uint32, msg []byte, nbits int) ([]byte, error) {
+	h, err := zuc.NewEIAHash(key, count, bearer, direction)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBits(msg, nbits); err != nil {
+		return nil, err
+	}
+	return h.Finish(msg, nbits), nil
+}
+
+func checkBits(msg []byte, nbits int) error {
+	if nbits < 0 || len(msg) < (nbits+7)/8 {
+		return errors.New("zuc: invalid message bit length")
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 Sun Yimin. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package zuc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/emmansun/gmsm/internal/byteorder"
+)
+
+// bit lengths of the 128-EEA3 test sets 1-3 in 3GPP TS 35.222,
+// the vectors of test set 3 are truncated to whole bytes.
+var zucEEATestBits = []int{193, 800, 4016}
+
+func TestEEA3Bits(t *testing.T) {
+	for i, test := range zucEEATests {
+		key, _ := hex.DecodeString(test.key)
+		in, _ := hex.DecodeString(test.in)
+		want, _ := hex.DecodeString(test.out)
+		nbits := zucEEATestBits[i]
+		// test set 1 is padded with zero words, the 193th output bit is 0.
+		for len(in) < (nbits+7)/8 {
+			in = append(in, 0)
+			want = append(want, 0)
+		}
+		out := make([]byte, len(in))
+		if err := EEA3(key, test.count, test.bearer, test.direction, out, in, nbits); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(out, want) {
+			t.Errorf("case %d, expected=%x, result=%x", i+1, want, out)
+		}
+	}
+
+	// trailing bits of the last byte are cleared
+	key, _ := hex.DecodeString(zucEEATests[0].key)
+	in := bytes.Repeat([]byte{0xff}, 4)
+	out := make([]byte, 4)
+	if err := EEA3(key, 1, 2, 1, out, in, 27); err != nil {
+		t.Fatal(err)
+	}
+	if out[3]&0x1f != 0 {
+		t.Errorf("unused bits are not cleared: %x", out)
+	}
+	if err := EEA3(key, 1, 2, 1, out, in, 33); err == nil {
+		t.Error("expected error for short input")
+	}
+}
+
+func TestEIA3Bits(t *testing.T) {
+	for i, test := range zucEIATests {
+		in := make([]byte, 0, len(test.in)*4)
+		for _, w := range test.in {
+			in = byteorder.BEAppendUint32(in, w)
+		}
+		mac, err := EIA3(test.key, test.count, test.bearer, test.direction, in, test.nbits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(mac) != test.mac {
+			t.Errorf("case %d, expected=%s, result=%x", i+1, test.mac, mac)
+		}
+	}
+	if _, err := EIA3(zucEIATests[0].key, 0, 0, 0, []byte{0}, 9); err == nil {
+		t.Error("expected error for short input")
+	}
+}
@@ -0,0 +1,112 @@
+package zuc
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DecryptingReaderAt is an [io.ReaderAt] which reads ciphertext encrypted by a
+// [ChunkedCipher] from an underlying [io.ReaderAt] and decrypts it on the fly.
+//
+// ZUC is a stream cipher, so ciphertext and plaintext share the same offsets
+// and length; ReadAt maps the requested byte range onto the affected chunks.
+type DecryptingReaderAt struct {
+	src    io.ReaderAt
+	size   int64
+	cipher ChunkedCipher
+}
+
+// NewDecryptingReaderAt returns a [DecryptingReaderAt] which reads at most size
+// bytes of ciphertext from src and decrypts them with cipher.
+func NewDecryptingReaderAt(src io.ReaderAt, size int64, cipher ChunkedCipher) *DecryptingReaderAt {
+	return &DecryptingReaderAt{src: src, size: size, cipher: cipher}
+}
+
+// Size returns the size of the plaintext in bytes.
+func (r *DecryptingReaderAt) Size() int64 {
+	return r.size
+}
+
+// ReadAt implements [io.ReaderAt], p is filled with the decrypted data
+// starting at the plaintext offset off.
+func (r *DecryptingReaderAt) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errors.New("zuc: DecryptingReaderAt.ReadAt: negative offset")
+	}
+	if off >= r.size {
+		return 0, io.EOF
+	}
+	if remain := r.size - off; int64(len(p)) > remain {
+		p = p[:remain]
+		err = io.EOF
+	}
+	n, rerr := r.src.ReadAt(p, off)
+	r.cipher.XORKeyStreamAt(p[:n], p[:n], uint64(off))
+	if rerr != nil {
+		err = rerr
+	}
+	return n, err
+}
+
+// EncryptingWriterAt is an [io.WriterAt] which encrypts data with a
+// [ChunkedCipher] before writing it at the same offset of the underlying
+// [io.WriterAt].
+type EncryptingWriterAt struct {
+	dst    io.WriterAt
+	cipher ChunkedCipher
+}
+
+// NewEncryptingWriterAt returns an [EncryptingWriterAt] which writes data
+// encrypted with cipher to dst.
+func NewEncryptingWriterAt(dst io.WriterAt, cipher ChunkedCipher) *EncryptingWriterAt {
+	return &EncryptingWriterAt{dst: dst, cipher: cipher}
+}
+
+// WriteAt implements [io.WriterAt], p is encrypted with the keystream at
+// offset off and written to the underlying writer. p is not modified.
+func (w *EncryptingWriterAt) WriteAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errors.New("zuc: EncryptingWriterAt.WriteAt: negative offset")
+	}
+	buf := make([]byte, min(len(p), w.cipher.ChunkSize()))
+	for len(p) > 0 {
+		m := copy(buf, p)
+		w.cipher.XORKeyStreamAt(buf[:m], buf[:m], uint64(off))
+		written, err := w.dst.WriteAt(buf[:m], off)
+		n += written
+		if err != nil {
+			return n, err
+		}
+		p = p[m:]
+		off += int64(m)
+	}
+	return n, nil
+}
+
+type decryptingHandler struct {
+	name    string
+	modtime time.Time
+	reader  *DecryptingReaderAt
+}
+
+// NewDecryptingHandler returns an [http.Handler] which serves the content of a
+// file encrypted with a [ChunkedCipher], decrypted on the fly.
+//
+// The content is served with [http.ServeContent], so Range, If-Match,
+// If-Unmodified-Since, If-None-Match, If-Modified-Since and If-Range requests
+// are handled accordingly; only the chunks covered by the requested ranges are
+// read and decrypted. The name is used to determine the Content-Type and
+// modtime for the Last-Modified header, see [http.ServeContent] for details.
+func NewDecryptingHandler(name string, modtime time.Time, src io.ReaderAt, size int64, cipher ChunkedCipher) http.Handler {
+	return &decryptingHandler{
+		name:    name,
+		modtime: modtime,
+		reader:  NewDecryptingReaderAt(src, size, cipher),
+	}
+}
+
+func (h *decryptingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	http.ServeContent(w, r, h.name, h.modtime, io.NewSectionReader(h.reader, 0, h.reader.size))
+}
@@ -0,0 +1,167 @@
+package zuc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// memWriterAt is a growable in-memory io.WriterAt.
+type memWriterAt struct {
+	buf []byte
+}
+
+func (m *memWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	if end := int(off) + len(p); end > len(m.buf) {
+		m.buf = append(m.buf, make([]byte, end-len(m.buf))...)
+	}
+	return copy(m.buf[off:], p), nil
+}
+
+func newTestChunkedCipher(t *testing.T) ChunkedCipher {
+	key := make([]byte, 16)
+	iv := make([]byte, IVSize128)
+	for i := range key {
+		key[i] = byte(i)
+		iv[i] = byte(0xf0 - i)
+	}
+	c, err := NewChunkedCipher(key, iv, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func newTestPlaintext() []byte {
+	plaintext := make([]byte, 1000)
+	for i := range plaintext {
+		plaintext[i] = byte(i * 7)
+	}
+	return plaintext
+}
+
+func TestEncryptingWriterAt(t *testing.T) {
+	c := newTestChunkedCipher(t)
+	plaintext := newTestPlaintext()
+	want := make([]byte, len(plaintext))
+	c.XORKeyStreamAt(want, plaintext, 0)
+
+	// write the plaintext out of order, with pieces crossing chunk boundaries
+	mem := &memWriterAt{}
+	w := NewEncryptingWriterAt(mem, c)
+	pieces := [][2]int{{950, 1000}, {0, 1}, {1, 99}, {350, 950}, {99, 350}}
+	for _, piece := range pieces {
+		src := bytes.Clone(plaintext[piece[0]:piece[1]])
+		n, err := w.WriteAt(src, int64(piece[0]))
+		if err != nil || n != len(src) {
+			t.Fatalf("WriteAt(%d bytes, %d) = %d, %v", len(src), piece[0], n, err)
+		}
+		if !bytes.Equal(src, plaintext[piece[0]:piece[1]]) {
+			t.Fatal("WriteAt modified the input")
+		}
+	}
+	if !bytes.Equal(mem.buf, want) {
+		t.Fatalf("got %x, want %x", mem.buf, want)
+	}
+	if _, err := w.WriteAt([]byte{1}, -1); err == nil {
+		t.Fatal("expected error for negative offset")
+	}
+}
+
+func TestDecryptingReaderAt(t *testing.T) {
+	c := newTestChunkedCipher(t)
+	plaintext := newTestPlaintext()
+	ciphertext := make([]byte, len(plaintext))
+	c.XORKeyStreamAt(ciphertext, plaintext, 0)
+
+	r := NewDecryptingReaderAt(bytes.NewReader(ciphertext), int64(len(ciphertext)), c)
+	if r.Size() != int64(len(plaintext)) {
+		t.Fatalf("Size() = %d, want %d", r.Size(), len(plaintext))
+	}
+	for _, off := range []int{0, 1, 99, 100, 101, 555, 999} {
+		for _, length := range []int{0, 1, 100, 201, 1000} {
+			p := make([]byte, length)
+			n, err := r.ReadAt(p, int64(off))
+			end := min(off+length, len(plaintext))
+			if n != end-off {
+				t.Fatalf("ReadAt(%d, %d) = %d, want %d", length, off, n, end-off)
+			}
+			if off+length > len(plaintext) && err != io.EOF {
+				t.Fatalf("ReadAt(%d, %d) error = %v, want EOF", length, off, err)
+			}
+			if off+length <= len(plaintext) && err != nil {
+				t.Fatalf("ReadAt(%d, %d) error = %v", length, off, err)
+			}
+			if !bytes.Equal(p[:n], plaintext[off:end]) {
+				t.Fatalf("ReadAt(%d, %d) = %x, want %x", length, off, p[:n], plaintext[off:end])
+			}
+		}
+	}
+	if n, err := r.ReadAt(make([]byte, 1), int64(len(plaintext))); n != 0 || err != io.EOF {
+		t.Fatalf("ReadAt at end = %d, %v", n, err)
+	}
+	if _, err := r.ReadAt(make([]byte, 1), -1); err == nil {
+		t.Fatal("expected error for negative offset")
+	}
+
+	got, err := io.ReadAll(io.NewSectionReader(r, 0, r.Size()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Fatal("ReadAll mismatch")
+	}
+}
+
+func TestDecryptingHandler(t *testing.T) {
+	c := newTestChunkedCipher(t)
+	plaintext := newTestPlaintext()
+	ciphertext := make([]byte, len(plaintext))
+	c.XORKeyStreamAt(ciphertext, plaintext, 0)
+
+	modtime := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	h := NewDecryptingHandler("video.mp4", modtime, bytes.NewReader(ciphertext), int64(len(ciphertext)), c)
+
+	// full content
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/video.mp4", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if !bytes.Equal(rec.Body.Bytes(), plaintext) {
+		t.Fatal("full content mismatch")
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "video/mp4" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	// byte ranges
+	for _, rng := range [][2]int{{0, 0}, {99, 100}, {150, 420}, {900, 999}} {
+		req := httptest.NewRequest(http.MethodGet, "/video.mp4", nil)
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", rng[0], rng[1]))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusPartialContent {
+			t.Fatalf("range %v: status = %d", rng, rec.Code)
+		}
+		if want := plaintext[rng[0] : rng[1]+1]; !bytes.Equal(rec.Body.Bytes(), want) {
+			t.Fatalf("range %v: got %x, want %x", rng, rec.Body.Bytes(), want)
+		}
+		if want := fmt.Sprintf("bytes %d-%d/%d", rng[0], rng[1], len(plaintext)); rec.Header().Get("Content-Range") != want {
+			t.Fatalf("range %v: Content-Range = %q, want %q", rng, rec.Header().Get("Content-Range"), want)
+		}
+	}
+
+	// unsatisfiable range
+	req := httptest.NewRequest(http.MethodGet, "/video.mp4", nil)
+	req.Header.Set("Range", "bytes=2000-")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusRequestedRangeNotSatisfiable {
+		t.Fatalf("status = %d", rec.Code)
+	}
+}